@@ -0,0 +1,22 @@
+terraform {
+  required_providers {
+    azapi = {
+      source = "Azure/azapi"
+    }
+  }
+}
+
+provider "azapi" {
+}
+
+// list the versions of a Key Vault secret
+data "azapi_data_plane_resource_action" "versions" {
+  type      = "Microsoft.KeyVault/vaults/secrets@7.4"
+  parent_id = "myvault.vault.azure.net"
+  name      = "mysecret"
+  action    = "versions"
+  method    = "GET"
+  response_export_values = {
+    "ids" = "value[].id"
+  }
+}
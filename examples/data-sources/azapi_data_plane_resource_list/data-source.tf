@@ -0,0 +1,25 @@
+terraform {
+  required_providers {
+    azapi = {
+      source = "Azure/azapi"
+    }
+  }
+}
+
+provider "azapi" {
+}
+
+data "azapi_data_plane_resource_list" "example" {
+  type      = "Microsoft.AppConfiguration/configurationStores/keyValues@1.0"
+  parent_id = "mystore.azconfig.io"
+  query_parameters = {
+    key = ["prefix*"]
+  }
+  response_export_values = {
+    "keys" = "value[].key"
+  }
+}
+
+output "keys" {
+  value = data.azapi_data_plane_resource_list.example.output.keys
+}
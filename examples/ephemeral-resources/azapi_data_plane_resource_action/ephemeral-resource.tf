@@ -0,0 +1,20 @@
+terraform {
+  required_providers {
+    azapi = {
+      source = "Azure/azapi"
+    }
+  }
+}
+
+provider "azapi" {
+}
+
+// backup a Key Vault key without storing the backup blob in the state
+ephemeral "azapi_data_plane_resource_action" "backup" {
+  type                   = "Microsoft.KeyVault/vaults/keys@7.4"
+  parent_id              = "myvault.vault.azure.net"
+  name                   = "mykey"
+  action                 = "backup"
+  method                 = "POST"
+  response_export_values = ["value"]
+}
@@ -0,0 +1,6 @@
+# Data plane resource can be imported using the resource id with the resource type as a query parameter, e.g.
+terraform import azapi_data_plane_resource.example "mystore.azconfig.io/kv/mykey?type=Microsoft.AppConfiguration/configurationStores/keyValues"
+
+# It also supports specifying API version by using the api-version query parameter or the `type` with api-version, e.g.
+terraform import azapi_data_plane_resource.example "mystore.azconfig.io/kv/mykey?type=Microsoft.AppConfiguration/configurationStores/keyValues&api-version=1.0"
+terraform import azapi_data_plane_resource.example "mystore.azconfig.io/kv/mykey?type=Microsoft.AppConfiguration/configurationStores/keyValues@1.0"
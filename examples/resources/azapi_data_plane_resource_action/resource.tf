@@ -0,0 +1,34 @@
+terraform {
+  required_providers {
+    azapi = {
+      source = "Azure/azapi"
+    }
+  }
+}
+
+provider "azapi" {
+}
+
+// rotate a Key Vault key
+resource "azapi_data_plane_resource_action" "rotate" {
+  type      = "Microsoft.KeyVault/vaults/keys@7.4"
+  parent_id = "myvault.vault.azure.net"
+  name      = "mykey"
+  action    = "rotate"
+  method    = "POST"
+  response_export_values = {
+    "kid" = "key.kid"
+  }
+}
+
+// trigger a Synapse pipeline run
+resource "azapi_data_plane_resource_action" "run" {
+  type      = "Microsoft.Synapse/workspaces/pipelines@2020-12-01"
+  parent_id = "myworkspace.dev.azuresynapse.net"
+  name      = "mypipeline"
+  action    = "createRun"
+  body = {
+    parameter1 = "value1"
+  }
+  response_export_values = ["runId"]
+}
@@ -0,0 +1,35 @@
+terraform {
+  required_providers {
+    azapi = {
+      source = "Azure/azapi"
+    }
+    azurerm = {
+      source = "hashicorp/azurerm"
+    }
+  }
+}
+
+provider "azapi" {
+}
+
+provider "azurerm" {
+  features {}
+}
+
+data "azurerm_app_configuration" "example" {
+  name                = "example-appconf"
+  resource_group_name = "example-resources"
+}
+
+// update the tags of an existing key-value which is managed outside of Terraform
+resource "azapi_data_plane_update_resource" "example" {
+  type               = "Microsoft.AppConfiguration/configurationStores/keyValues@1.0"
+  parent_id          = replace(data.azurerm_app_configuration.example.endpoint, "https://", "")
+  name               = "mykey"
+  restore_on_destroy = true
+  body = {
+    tags = {
+      environment = "production"
+    }
+  }
+}
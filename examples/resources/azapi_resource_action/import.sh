@@ -0,0 +1,5 @@
+# Resource action can be imported using the resource id with the resource type, api-version and action as query parameters, e.g.
+terraform import azapi_resource_action.example "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/resGroup1/providers/Microsoft.Automation/automationAccounts/account1?type=Microsoft.Automation/automationAccounts&api-version=2021-06-22&action=agentRegistrationInformation/regenerateKey"
+
+# The HTTP method can be specified by using the method query parameter, the body is imported from the resource when the method is PUT or PATCH, e.g.
+terraform import azapi_resource_action.example "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/resGroup1/providers/Microsoft.Network/loadBalancers/lb1?type=Microsoft.Network/loadBalancers@2023-09-01&method=PATCH"
@@ -0,0 +1,5 @@
+# Azure resource can be imported using the resource id, e.g.
+terraform import azapi_update_resource.example /subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/resGroup1/providers/Microsoft.Network/loadBalancers/lb1
+
+# It also supports specifying API version and resource type by using the query parameters, e.g.
+terraform import azapi_update_resource.example "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/resGroup1/providers/Microsoft.Network/loadBalancers/lb1?api-version=2023-09-01&type=Microsoft.Network/loadBalancers"
@@ -0,0 +1,114 @@
+package azure
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/Azure/terraform-provider-azapi/internal/azure/types"
+)
+
+// customResources holds the resource type definitions registered by users, keyed by resource type.
+// They take precedence over the embedded definitions with the same resource type and api-version.
+var customResources = make(map[string]*Resource)
+
+var customMutex = &sync.RWMutex{}
+
+// LoadCustomTypes registers the resource types defined in a bicep-types `types.json` document.
+func LoadCustomTypes(data []byte) error {
+	var schema types.Schema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return fmt.Errorf("failed to unmarshal custom types: %+v", err)
+	}
+
+	definitions := make(map[string]*ResourceDefinition)
+	for _, t := range schema.Types {
+		if t == nil {
+			continue
+		}
+		resourceType, ok := (*t).(*types.ResourceType)
+		if !ok {
+			continue
+		}
+		index := strings.LastIndex(resourceType.Name, "@")
+		if index == -1 {
+			return fmt.Errorf("api-version is not specified, type: %s", resourceType.Name)
+		}
+		definitions[resourceType.Name] = &ResourceDefinition{
+			Definition: resourceType,
+			ApiVersion: resourceType.Name[index+1:],
+		}
+	}
+	if len(definitions) == 0 {
+		return fmt.Errorf("no resource type is defined in custom types")
+	}
+
+	customMutex.Lock()
+	defer customMutex.Unlock()
+	for name, definition := range definitions {
+		resourceType := name[0:strings.LastIndex(name, "@")]
+		key := resourceType
+		for k := range customResources {
+			if strings.EqualFold(k, resourceType) {
+				key = k
+				break
+			}
+		}
+		resource := customResources[key]
+		if resource == nil {
+			resource = &Resource{
+				Definitions: make([]*ResourceDefinition, 0),
+			}
+			customResources[key] = resource
+		}
+		replaced := false
+		for i, v := range resource.Definitions {
+			if v.ApiVersion == definition.ApiVersion {
+				resource.Definitions[i] = definition
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			resource.Definitions = append(resource.Definitions, definition)
+		}
+	}
+	return nil
+}
+
+// ResetCustomTypes removes all the registered custom resource types.
+func ResetCustomTypes() {
+	customMutex.Lock()
+	defer customMutex.Unlock()
+	customResources = make(map[string]*Resource)
+}
+
+func getCustomApiVersions(resourceType string) []string {
+	customMutex.RLock()
+	defer customMutex.RUnlock()
+	res := make([]string, 0)
+	for key, value := range customResources {
+		if strings.EqualFold(key, resourceType) {
+			for _, v := range value.Definitions {
+				res = append(res, v.ApiVersion)
+			}
+		}
+	}
+	return res
+}
+
+func getCustomResourceDefinition(resourceType, apiVersion string) *types.ResourceType {
+	customMutex.RLock()
+	defer customMutex.RUnlock()
+	for key, value := range customResources {
+		if strings.EqualFold(key, resourceType) {
+			for _, v := range value.Definitions {
+				if v.ApiVersion == apiVersion {
+					return v.Definition
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package azure_test
+
+import (
+	"testing"
+
+	"github.com/Azure/terraform-provider-azapi/internal/azure"
+)
+
+const customTypesJson = `[
+  {
+    "$type": "StringType"
+  },
+  {
+    "$type": "StringLiteralType",
+    "value": "Microsoft.CustomProviders/resourceProviders/users"
+  },
+  {
+    "$type": "StringLiteralType",
+    "value": "2018-09-01-preview"
+  },
+  {
+    "$type": "ObjectType",
+    "name": "Microsoft.CustomProviders/resourceProviders/users",
+    "properties": {
+      "id": {
+        "type": {
+          "$ref": "#/0"
+        },
+        "flags": 10
+      },
+      "name": {
+        "type": {
+          "$ref": "#/0"
+        },
+        "flags": 9
+      },
+      "type": {
+        "type": {
+          "$ref": "#/1"
+        },
+        "flags": 10
+      },
+      "apiVersion": {
+        "type": {
+          "$ref": "#/2"
+        },
+        "flags": 10
+      },
+      "properties": {
+        "type": {
+          "$ref": "#/4"
+        },
+        "flags": 1
+      }
+    }
+  },
+  {
+    "$type": "ObjectType",
+    "name": "UserProperties",
+    "properties": {
+      "fullName": {
+        "type": {
+          "$ref": "#/0"
+        },
+        "flags": 1
+      },
+      "provisioningState": {
+        "type": {
+          "$ref": "#/0"
+        },
+        "flags": 2
+      }
+    }
+  },
+  {
+    "$type": "ResourceType",
+    "name": "Microsoft.CustomProviders/resourceProviders/users@2018-09-01-preview",
+    "scopeType": 8,
+    "body": {
+      "$ref": "#/3"
+    },
+    "flags": 0
+  }
+]`
+
+func Test_LoadCustomTypes(t *testing.T) {
+	defer azure.ResetCustomTypes()
+
+	if err := azure.LoadCustomTypes([]byte(`[{"$type": "StringType"}]`)); err == nil {
+		t.Fatalf("expect error when no resource type is defined, but got nil")
+	}
+	if err := azure.LoadCustomTypes([]byte(`{}`)); err == nil {
+		t.Fatalf("expect error when the document is invalid, but got nil")
+	}
+
+	if err := azure.LoadCustomTypes([]byte(customTypesJson)); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+
+	resourceType := "microsoft.customproviders/resourceProviders/users"
+	versions := azure.GetApiVersions(resourceType)
+	found := false
+	for _, v := range versions {
+		if v == "2018-09-01-preview" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expect api-version 2018-09-01-preview is registered, got %v", versions)
+	}
+
+	def, err := azure.GetResourceDefinition(resourceType, "2018-09-01-preview")
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if def == nil {
+		t.Fatalf("expect resource definition, got nil")
+	}
+
+	body := map[string]interface{}{
+		"properties": map[string]interface{}{
+			"fullName":          "foo",
+			"provisioningState": "Succeeded",
+		},
+	}
+	writeOnly, ok := def.GetWriteOnly(body).(map[string]interface{})
+	if !ok {
+		t.Fatalf("expect write-only body is an object")
+	}
+	if _, ok := writeOnly["properties"].(map[string]interface{})["provisioningState"]; ok {
+		t.Fatalf("expect read-only property is removed from write-only body")
+	}
+	readOnly, ok := def.GetReadOnly(body).(map[string]interface{})
+	if !ok {
+		t.Fatalf("expect read-only body is an object")
+	}
+	if _, ok := readOnly["properties"].(map[string]interface{})["provisioningState"]; !ok {
+		t.Fatalf("expect read-only property is kept in read-only body")
+	}
+	if errors := def.Validate(map[string]interface{}{"properties": map[string]interface{}{"unknown": "foo"}}, ""); len(errors) == 0 {
+		t.Fatalf("expect validation errors for unknown property")
+	}
+
+	azure.ResetCustomTypes()
+	if def, _ := azure.GetResourceDefinition(resourceType, "2018-09-01-preview"); def != nil {
+		t.Fatalf("expect custom resource definition is removed after reset")
+	}
+}
@@ -0,0 +1,98 @@
+package azure
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Azure/terraform-provider-azapi/internal/azure/types"
+)
+
+var dataPlaneSchema *Schema
+
+//go:embed dataplane
+var DataPlaneStaticFiles embed.FS
+
+var dataPlaneMutex = &sync.Mutex{}
+
+func GetDataPlaneSchema() *Schema {
+	dataPlaneMutex.Lock()
+	defer dataPlaneMutex.Unlock()
+	if dataPlaneSchema == nil {
+		data, err := DataPlaneStaticFiles.ReadFile("dataplane/index.json")
+		if err != nil {
+			log.Printf("[ERROR] failed to load data plane schema index: %+v", err)
+			return nil
+		}
+		err = json.Unmarshal(data, &dataPlaneSchema)
+		if err != nil {
+			log.Printf("[ERROR] failed to unmarshal data plane schema index: %+v", err)
+			return nil
+		}
+	}
+	return dataPlaneSchema
+}
+
+func GetDataPlaneApiVersions(resourceType string) []string {
+	res := getCustomApiVersions(resourceType)
+	azureSchema := GetDataPlaneSchema()
+	if azureSchema != nil {
+		for key, value := range azureSchema.Resources {
+			if strings.EqualFold(key, resourceType) {
+				for _, v := range value.Definitions {
+					if !slices.Contains(res, v.ApiVersion) {
+						res = append(res, v.ApiVersion)
+					}
+				}
+			}
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+func GetDataPlaneResourceDefinition(resourceType, apiVersion string) (*types.ResourceType, error) {
+	if def := getCustomResourceDefinition(resourceType, apiVersion); def != nil {
+		return def, nil
+	}
+	azureSchema := GetDataPlaneSchema()
+	if azureSchema == nil {
+		return nil, fmt.Errorf("failed to load data plane schema index")
+	}
+	for key, value := range azureSchema.Resources {
+		if strings.EqualFold(key, resourceType) {
+			for _, v := range value.Definitions {
+				if v.ApiVersion == apiVersion {
+					return v.getDataPlaneDefinition()
+				}
+			}
+		}
+	}
+	return nil, fmt.Errorf("failed to find resource type %s api-version %s in data plane schema index", resourceType, apiVersion)
+}
+
+func (o *ResourceDefinition) getDataPlaneDefinition() (*types.ResourceType, error) {
+	if o == nil {
+		return nil, nil
+	}
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	if o.Definition != nil {
+		return o.Definition, nil
+	}
+	data, err := DataPlaneStaticFiles.ReadFile("dataplane/" + o.Location.Location)
+	if err != nil {
+		return nil, err
+	}
+	definition, err := loadResourceType(data, o.Location.Index)
+	if err != nil {
+		return nil, err
+	}
+	o.Definition = definition
+	return o.Definition, nil
+}
@@ -0,0 +1,266 @@
+package azure_test
+
+import (
+	"testing"
+
+	"github.com/Azure/terraform-provider-azapi/internal/azure"
+)
+
+func Test_GetDataPlaneSchema(t *testing.T) {
+	schema := azure.GetDataPlaneSchema()
+	if schema == nil {
+		t.Fatal("failed to load data plane schema")
+	}
+	if len(schema.Resources) == 0 {
+		t.Fatal("expect resources are not empty")
+	}
+	for resourceType, res := range schema.Resources {
+		for _, definition := range res.Definitions {
+			def, err := azure.GetDataPlaneResourceDefinition(resourceType, definition.ApiVersion)
+			if err != nil {
+				t.Fatalf("failed to load resource definition for %s api-version %s: %+v", resourceType, definition.ApiVersion, err)
+			}
+			if def == nil {
+				t.Fatalf("expect resource definition is not nil for %s api-version %s", resourceType, definition.ApiVersion)
+			}
+		}
+	}
+}
+
+func Test_GetDataPlaneApiVersions(t *testing.T) {
+	if len(azure.GetDataPlaneApiVersions("Microsoft.KeyVault/vaults/certificates/issuers")) == 0 {
+		t.Errorf("expect api-versions for Microsoft.KeyVault/vaults/certificates/issuers but got 0")
+	}
+	if len(azure.GetDataPlaneApiVersions("Microsoft.KeyVault/vaults/certificates/issuers0")) != 0 {
+		t.Errorf("expect 0 api-version for Microsoft.KeyVault/vaults/certificates/issuers0")
+	}
+}
+
+func Test_DataPlaneBodyValidation(t *testing.T) {
+	testcases := []struct {
+		ResourceType string
+		ApiVersion   string
+		Body         map[string]interface{}
+		ExpectError  bool
+	}{
+		{
+			ResourceType: "Microsoft.KeyVault/vaults/certificates/issuers",
+			ApiVersion:   "7.4",
+			Body: map[string]interface{}{
+				"provider": "Test",
+				"credentials": map[string]interface{}{
+					"account_id": "keyvaultuser",
+				},
+				"org_details": map[string]interface{}{
+					"admin_details": []interface{}{
+						map[string]interface{}{
+							"first_name": "John",
+							"last_name":  "Doe",
+							"email":      "admin@microsoft.com",
+							"phone":      "4255555555",
+						},
+					},
+				},
+			},
+			ExpectError: false,
+		},
+		{
+			ResourceType: "Microsoft.KeyVault/vaults/certificates/issuers",
+			ApiVersion:   "7.4",
+			Body: map[string]interface{}{
+				"credentials": map[string]interface{}{
+					"account_id": "keyvaultuser",
+				},
+			},
+			ExpectError: true,
+		},
+		{
+			ResourceType: "Microsoft.IoTCentral/IoTApps/users",
+			ApiVersion:   "2022-07-31",
+			Body: map[string]interface{}{
+				"type": "email",
+				"roles": []interface{}{
+					map[string]interface{}{
+						"role": "ae2c9854-393b-4f97-8c42-479d70ce626e",
+					},
+				},
+				"email": "user5@contoso.com",
+			},
+			ExpectError: false,
+		},
+		{
+			ResourceType: "Microsoft.KeyVault/vaults/secrets",
+			ApiVersion:   "7.4",
+			Body: map[string]interface{}{
+				"value":       "secret",
+				"contentType": "text/plain",
+				"attributes": map[string]interface{}{
+					"enabled": true,
+				},
+				"tags": map[string]interface{}{
+					"env": "test",
+				},
+			},
+			ExpectError: false,
+		},
+		{
+			ResourceType: "Microsoft.KeyVault/vaults/secrets",
+			ApiVersion:   "7.4",
+			Body: map[string]interface{}{
+				"contentType": "text/plain",
+			},
+			ExpectError: true,
+		},
+		{
+			ResourceType: "Microsoft.KeyVault/vaults/keys",
+			ApiVersion:   "7.4",
+			Body: map[string]interface{}{
+				"key": map[string]interface{}{
+					"kty":     "RSA",
+					"key_ops": []interface{}{"encrypt", "decrypt"},
+					"n":       "modulus",
+					"e":       "AQAB",
+				},
+				"attributes": map[string]interface{}{
+					"exportable": false,
+				},
+			},
+			ExpectError: false,
+		},
+		{
+			ResourceType: "Microsoft.AppConfiguration/configurationStores/keyValues",
+			ApiVersion:   "1.0",
+			Body: map[string]interface{}{
+				"content_type": "",
+				"value":        "foo",
+				"unknown":      "bar",
+			},
+			ExpectError: true,
+		},
+		{
+			ResourceType: "Microsoft.AppConfiguration/configurationStores/keyValues",
+			ApiVersion:   "2023-11-01",
+			Body: map[string]interface{}{
+				"content_type": "",
+				"value":        "foo",
+			},
+			ExpectError: false,
+		},
+		{
+			ResourceType: "Microsoft.KeyVault/vaults/secrets",
+			ApiVersion:   "7.5",
+			Body: map[string]interface{}{
+				"value": "secret",
+			},
+			ExpectError: false,
+		},
+		{
+			ResourceType: "Microsoft.KeyVault/vaults/storage",
+			ApiVersion:   "7.4",
+			Body: map[string]interface{}{
+				"activeKeyName":     "key1",
+				"autoRegenerateKey": true,
+			},
+			ExpectError: true,
+		},
+		{
+			ResourceType: "Microsoft.Synapse/workspaces/datasets",
+			ApiVersion:   "2020-12-01",
+			Body: map[string]interface{}{
+				"properties": map[string]interface{}{
+					"type": "AzureBlob",
+					"typeProperties": map[string]interface{}{
+						"format": map[string]interface{}{
+							"type": "TextFormat",
+						},
+					},
+				},
+			},
+			ExpectError: false,
+		},
+		{
+			ResourceType: "Microsoft.Synapse/workspaces/pipelines",
+			ApiVersion:   "2020-12-01",
+			Body: map[string]interface{}{
+				"etag": "abc",
+				"properties": map[string]interface{}{
+					"activities": []interface{}{},
+				},
+			},
+			ExpectError: true,
+		},
+		{
+			ResourceType: "Microsoft.Purview/accounts/Scanning/classificationrules",
+			ApiVersion:   "2022-07-01-preview",
+			Body: map[string]interface{}{
+				"kind": "Custom",
+				"properties": map[string]interface{}{
+					"description":        "Let's put a cool desc here",
+					"classificationName": "MICROSOFT.FINANCIAL.AUSTRALIA.BANK_ACCOUNT_NUMBER",
+					"columnPatterns": []interface{}{
+						map[string]interface{}{
+							"pattern": "^data$",
+							"kind":    "Regex",
+						},
+					},
+					"minimumPercentageMatch": 60,
+					"ruleStatus":             "Enabled",
+				},
+			},
+			ExpectError: false,
+		},
+		{
+			ResourceType: "Microsoft.DigitalTwins/digitalTwinsInstances/digitaltwins",
+			ApiVersion:   "2023-10-31",
+			Body: map[string]interface{}{
+				"$dtId":       "room1",
+				"temperature": 20,
+			},
+			ExpectError: true,
+		},
+		{
+			ResourceType: "Microsoft.IoTCentral/iotApps/devices/attestation",
+			ApiVersion:   "2022-07-31",
+			Body: map[string]interface{}{
+				"type": "symmetricKey",
+				"symmetricKey": map[string]interface{}{
+					"primaryKey":   "primary",
+					"secondaryKey": "secondary",
+				},
+			},
+			ExpectError: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		def, err := azure.GetDataPlaneResourceDefinition(tc.ResourceType, tc.ApiVersion)
+		if err != nil {
+			t.Fatalf("failed to load resource definition for %s api-version %s: %+v", tc.ResourceType, tc.ApiVersion, err)
+		}
+		errors := def.Validate(tc.Body, "")
+		if tc.ExpectError != (len(errors) != 0) {
+			t.Errorf("resource type %s: expect error %v, got %v", tc.ResourceType, tc.ExpectError, errors)
+		}
+	}
+}
+
+func Test_DataPlaneReadOnly(t *testing.T) {
+	def, err := azure.GetDataPlaneResourceDefinition("Microsoft.AppConfiguration/configurationStores/keyValues", "1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	readOnly, ok := def.GetReadOnly(map[string]interface{}{
+		"key":   "foo",
+		"value": "bar",
+		"etag":  "abc",
+	}).(map[string]interface{})
+	if !ok {
+		t.Fatalf("expect read-only body is an object")
+	}
+	if readOnly["key"] != "foo" {
+		t.Errorf("expect read-only property key is kept, got %v", readOnly)
+	}
+	if _, ok := readOnly["value"]; ok {
+		t.Errorf("expect writable property value is removed, got %v", readOnly)
+	}
+}
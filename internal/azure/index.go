@@ -69,13 +69,17 @@ func (o *TypeLocation) LoadResourceTypeDefinition() (*types.ResourceType, error)
 	if err != nil {
 		return nil, err
 	}
+	return loadResourceType(data, o.Index)
+}
+
+func loadResourceType(data []byte, index int) (*types.ResourceType, error) {
 	var schema types.Schema
-	err = json.Unmarshal(data, &schema)
+	err := json.Unmarshal(data, &schema)
 	if err != nil {
 		return nil, err
 	}
-	if o.Index < len(schema.Types) && schema.Types[o.Index] != nil {
-		if resourceType, ok := (*schema.Types[o.Index]).(*types.ResourceType); ok {
+	if index < len(schema.Types) && schema.Types[index] != nil {
+		if resourceType, ok := (*schema.Types[index]).(*types.ResourceType); ok {
 			return resourceType, nil
 		}
 	}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -39,15 +40,18 @@ func GetAzureSchema() *Schema {
 }
 
 func GetApiVersions(resourceType string) []string {
+	res := getCustomApiVersions(resourceType)
 	azureSchema := GetAzureSchema()
 	if azureSchema == nil {
-		return []string{}
+		sort.Strings(res)
+		return res
 	}
-	res := make([]string, 0)
 	for key, value := range azureSchema.Resources {
 		if strings.EqualFold(key, resourceType) {
 			for _, v := range value.Definitions {
-				res = append(res, v.ApiVersion)
+				if !slices.Contains(res, v.ApiVersion) {
+					res = append(res, v.ApiVersion)
+				}
 			}
 		}
 	}
@@ -68,6 +72,9 @@ func GetApiVersions(resourceType string) []string {
 }
 
 func GetResourceDefinition(resourceType, apiVersion string) (*types.ResourceType, error) {
+	if def := getCustomResourceDefinition(resourceType, apiVersion); def != nil {
+		return def, nil
+	}
 	azureSchema := GetAzureSchema()
 	if azureSchema == nil {
 		return nil, fmt.Errorf("failed to load azure schema index")
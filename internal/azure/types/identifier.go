@@ -0,0 +1,144 @@
+package types
+
+import "strings"
+
+// knownArrayItemIdentifiers is a list of the array item identifiers of the known resource types, which are not marked in the schema.
+// The key of the inner map is the path of the array, and the value is the path of the property in the array items.
+var knownArrayItemIdentifiers = map[string]map[string]string{
+	"microsoft.network/applicationgatewaywebapplicationfirewallpolicies": {
+		"properties.customRules": "priority",
+		"properties.managedRules.managedRuleSets.ruleGroupOverrides":       "ruleGroupName",
+		"properties.managedRules.managedRuleSets.ruleGroupOverrides.rules": "ruleId",
+	},
+	"microsoft.network/azurefirewalls": {
+		"properties.applicationRuleCollections": "name",
+		"properties.natRuleCollections":         "name",
+		"properties.networkRuleCollections":     "name",
+	},
+	"microsoft.network/firewallpolicies": {
+		"properties.intrusionDetection.configuration.signatureOverrides": "id",
+	},
+	"microsoft.network/firewallpolicies/rulecollectiongroups": {
+		"properties.ruleCollections":       "name",
+		"properties.ruleCollections.rules": "name",
+	},
+	"microsoft.network/frontdoorwebapplicationfirewallpolicies": {
+		"properties.customRules.rules":                                     "priority",
+		"properties.managedRules.managedRuleSets.ruleGroupOverrides":       "ruleGroupName",
+		"properties.managedRules.managedRuleSets.ruleGroupOverrides.rules": "ruleId",
+	},
+	"microsoft.network/networksecuritygroups": {
+		"properties.securityRules": "name",
+	},
+}
+
+// GetKnownArrayItemIdentifiers returns the array item identifiers of the resource type from the maintained list of the known types.
+// The key of the returned map is the path of the array, for example, `properties.customRules`.
+func GetKnownArrayItemIdentifiers(resourceType string) map[string]string {
+	res := make(map[string]string)
+	for path, key := range knownArrayItemIdentifiers[strings.ToLower(resourceType)] {
+		res[path] = key
+	}
+	return res
+}
+
+// GetArrayItemIdentifiers returns the identifier property of the array items in the body, which is marked with the `Identifier` flag in the schema.
+// The key of the returned map is the path of the array, for example, `properties.securityRules`. The array items don't have index in the path.
+func GetArrayItemIdentifiers(t *TypeBase, body interface{}) map[string]string {
+	res := make(map[string]string)
+	if t != nil {
+		collectArrayItemIdentifiers(*t, body, "", res)
+	}
+	return res
+}
+
+func collectArrayItemIdentifiers(t TypeBase, body interface{}, path string, res map[string]string) {
+	if t == nil || body == nil {
+		return
+	}
+	switch v := t.(type) {
+	case *ResourceType:
+		if v.Body != nil && v.Body.Type != nil {
+			collectArrayItemIdentifiers(*v.Body.Type, body, path, res)
+		}
+	case *ObjectType:
+		bodyMap, ok := body.(map[string]interface{})
+		if !ok {
+			return
+		}
+		collectObjectPropertyIdentifiers(v.Properties, bodyMap, path, res)
+		if v.AdditionalProperties != nil && v.AdditionalProperties.Type != nil {
+			for key, value := range bodyMap {
+				if _, ok := v.Properties[key]; ok {
+					continue
+				}
+				collectArrayItemIdentifiers(*v.AdditionalProperties.Type, value, joinPath(path, key), res)
+			}
+		}
+	case *DiscriminatedObjectType:
+		bodyMap, ok := body.(map[string]interface{})
+		if !ok {
+			return
+		}
+		collectObjectPropertyIdentifiers(v.BaseProperties, bodyMap, path, res)
+		if discriminator, ok := bodyMap[v.Discriminator].(string); ok {
+			if v.Elements[discriminator] != nil && v.Elements[discriminator].Type != nil {
+				collectArrayItemIdentifiers(*v.Elements[discriminator].Type, body, path, res)
+			}
+		}
+	case *ArrayType:
+		bodyArray, ok := body.([]interface{})
+		if !ok || v.ItemType == nil || v.ItemType.Type == nil {
+			return
+		}
+		if identifier := identifierOfType(*v.ItemType.Type, bodyArray); identifier != "" {
+			res[path] = identifier
+		}
+		for _, item := range bodyArray {
+			collectArrayItemIdentifiers(*v.ItemType.Type, item, path, res)
+		}
+	}
+}
+
+func collectObjectPropertyIdentifiers(properties map[string]ObjectProperty, bodyMap map[string]interface{}, path string, res map[string]string) {
+	for key, def := range properties {
+		value, ok := bodyMap[key]
+		if !ok || def.Type == nil || def.Type.Type == nil {
+			continue
+		}
+		collectArrayItemIdentifiers(*def.Type.Type, value, joinPath(path, key), res)
+	}
+}
+
+// identifierOfType returns the name of the property which is marked with the `Identifier` flag in the item type.
+func identifierOfType(t TypeBase, items []interface{}) string {
+	switch v := t.(type) {
+	case *ObjectType:
+		for key, def := range v.Properties {
+			if def.IsIdentifier() {
+				return key
+			}
+		}
+	case *DiscriminatedObjectType:
+		for key, def := range v.BaseProperties {
+			if def.IsIdentifier() {
+				return key
+			}
+		}
+		// the identifier might be defined in the elements, use the discriminator of the first item to find it
+		for _, item := range items {
+			itemMap, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if discriminator, ok := itemMap[v.Discriminator].(string); ok && v.Elements[discriminator] != nil && v.Elements[discriminator].Type != nil {
+				return identifierOfType(*v.Elements[discriminator].Type, nil)
+			}
+		}
+	}
+	return ""
+}
+
+func joinPath(path, key string) string {
+	return strings.TrimPrefix(path+"."+key, ".")
+}
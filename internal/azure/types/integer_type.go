@@ -1,6 +1,7 @@
 package types
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/Azure/terraform-provider-azapi/internal/azure/utils"
@@ -36,6 +37,13 @@ func (t *IntegerType) Validate(body interface{}, path string) []error {
 		// TODO: skip validation for now because of the following issue:
 		// the bicep-types-az parses float as integer type and it should be fixed: https://github.com/Azure/bicep-types-az/issues/1404
 		return nil
+	case json.Number:
+		i, err := input.Int64()
+		if err != nil {
+			// the number is a float or out of range, skip validation for the same reason as float64
+			return nil
+		}
+		v = int(i)
 	case int64:
 		v = int(input)
 	case int32:
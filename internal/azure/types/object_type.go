@@ -188,6 +188,15 @@ func (o ObjectProperty) IsDeployTimeConstant() bool {
 	return false
 }
 
+func (o ObjectProperty) IsIdentifier() bool {
+	for _, value := range o.Flags {
+		if value == Identifier {
+			return true
+		}
+	}
+	return false
+}
+
 func (o *ObjectProperty) UnmarshalJSON(body []byte) error {
 	var m map[string]*json.RawMessage
 	err := json.Unmarshal(body, &m)
@@ -37,6 +37,17 @@ type Option struct {
 	SubscriptionId              string
 	TenantId                    string
 	MaxGoSdkRetries             int32
+	// PerOperationCorrelationRequestID generates a correlation request ID for each Terraform operation
+	PerOperationCorrelationRequestID bool
+	// OperationJournalPath is the path of the JSON lines file which records the requests, it's disabled if it's empty
+	OperationJournalPath string
+	// ReadOnly rejects the requests which may change the resources, except for the POST requests of the ReadOnlyAllowedActions
+	ReadOnly               bool
+	ReadOnlyAllowedActions []string
+	// ResourceManagerDefaultOptions are the default headers and query parameters of the ARM requests
+	ResourceManagerDefaultOptions RequestOptions
+	// DataPlaneDefaultOptions are the default headers and query parameters of the data plane requests
+	DataPlaneDefaultOptions RequestOptions
 }
 
 // NOTE: it should be possible for this method to become Private once the top level Client's removed
@@ -57,10 +68,20 @@ func (client *Client) Build(ctx context.Context, o *Option) error {
 		if id == "" {
 			id = correlationRequestID()
 		}
-		perCallPolicies = append(perCallPolicies, withCorrelationRequestID(id))
+		perCallPolicies = append(perCallPolicies, withCorrelationRequestID(id, o.PerOperationCorrelationRequestID))
+	}
+	if o.ReadOnly {
+		perCallPolicies = append(perCallPolicies, NewReadOnlyPolicy(o.ReadOnlyAllowedActions))
 	}
 	perRetryPolicies := make([]policy.Policy, 0)
-	perRetryPolicies = append(perRetryPolicies, NewLiveTrafficLogPolicy())
+	perRetryPolicies = append(perRetryPolicies, NewLiveTrafficLogPolicy(), NewTracingPolicy())
+	if o.OperationJournalPath != "" {
+		journalPolicy, err := NewOperationJournalPolicy(o.OperationJournalPath)
+		if err != nil {
+			return err
+		}
+		perRetryPolicies = append(perRetryPolicies, journalPolicy)
+	}
 
 	allowedHeaders := []string{
 		"Access-Control-Allow-Methods",
@@ -106,11 +127,13 @@ func (client *Client) Build(ctx context.Context, o *Option) error {
 			PerRetryPolicies: perRetryPolicies,
 			Retry:            policy.RetryOptions{MaxRetries: o.MaxGoSdkRetries},
 		},
-		DisableRPRegistration: o.SkipProviderRegistration,
+		// the resource providers are not registered in read-only mode, because the registration changes the subscription
+		DisableRPRegistration: o.SkipProviderRegistration || o.ReadOnly,
 	})
 	if err != nil {
 		return err
 	}
+	resourceClient.defaultOptions = o.ResourceManagerDefaultOptions
 	client.ResourceClient = resourceClient
 
 	dataPlaneClient, err := NewDataPlaneClient(o.Cred, &arm.ClientOptions{
@@ -129,11 +152,13 @@ func (client *Client) Build(ctx context.Context, o *Option) error {
 			PerRetryPolicies: perRetryPolicies,
 			Retry:            policy.RetryOptions{MaxRetries: o.MaxGoSdkRetries},
 		},
-		DisableRPRegistration: o.SkipProviderRegistration,
+		// the resource providers are not registered in read-only mode, because the registration changes the subscription
+		DisableRPRegistration: o.SkipProviderRegistration || o.ReadOnly,
 	})
 	if err != nil {
 		return err
 	}
+	dataPlaneClient.defaultOptions = o.DataPlaneDefaultOptions
 	client.DataPlaneClient = dataPlaneClient
 
 	client.Account = NewResourceManagerAccount(o.TenantId, o.SubscriptionId, ParsedTokenClaimsObjectIDProvider(o.Cred, o.CloudCfg))
@@ -21,10 +21,19 @@ var (
 
 type CorrelationIDPolicy struct {
 	CorrelationRequestID string
+	// PerOperation means the requests in the same Terraform operation, which is marked by WithOperation, share a generated correlation request ID,
+	// the CorrelationRequestID is used for the requests out of any operation.
+	PerOperation bool
 }
 
 func (c CorrelationIDPolicy) Do(req *policy.Request) (*http.Response, error) {
-	req.Raw().Header.Set(HeaderCorrelationRequestID, c.CorrelationRequestID)
+	id := c.CorrelationRequestID
+	if c.PerOperation {
+		if op, ok := operationFromContext(req.Raw().Context()); ok && op.CorrelationRequestID != "" {
+			id = op.CorrelationRequestID
+		}
+	}
+	req.Raw().Header.Set(HeaderCorrelationRequestID, id)
 	return req.Next()
 }
 
@@ -32,8 +41,9 @@ var _ policy.Policy = CorrelationIDPolicy{}
 
 // withCorrelationRequestID returns a policy.Policy that adds an HTTP extension header of
 // `x-ms-correlation-request-id` whose value is passed, undecorated UUID (e.g.,7F5A6223-F475-4A9C-B9D5-12575AA6B11B`).
-func withCorrelationRequestID(uuid string) policy.Policy {
-	return CorrelationIDPolicy{CorrelationRequestID: uuid}
+// If perOperation is true, the requests in a Terraform operation use the correlation request ID of the operation instead.
+func withCorrelationRequestID(uuid string, perOperation bool) policy.Policy {
+	return CorrelationIDPolicy{CorrelationRequestID: uuid, PerOperation: perOperation}
 }
 
 // correlationRequestID generates an UUID to pass through `x-ms-correlation-request-id` header.
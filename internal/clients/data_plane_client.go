@@ -17,6 +17,7 @@ import (
 	armpolicy "github.com/Azure/azure-sdk-for-go/sdk/azcore/arm/policy"
 	armruntime "github.com/Azure/azure-sdk-for-go/sdk/azcore/arm/runtime"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
 	"github.com/Azure/terraform-provider-azapi/internal/retry"
 	"github.com/Azure/terraform-provider-azapi/internal/services/parse"
@@ -29,6 +30,8 @@ type DataPlaneClient struct {
 	clientOptions   *arm.ClientOptions
 	cachedPipelines map[string]runtime.Pipeline
 	syncMux         sync.Mutex
+	// defaultOptions are the default headers and query parameters which are sent with all requests
+	defaultOptions RequestOptions
 }
 
 type DataPlaneClientRetryableErrors struct {
@@ -44,6 +47,7 @@ type DataPlaneRequester interface {
 	Get(ctx context.Context, id parse.DataPlaneResourceId, options RequestOptions) (interface{}, error)
 	DeleteThenPoll(ctx context.Context, id parse.DataPlaneResourceId, options RequestOptions) (interface{}, error)
 	Action(ctx context.Context, resourceID string, action string, apiVersion string, method string, body interface{}, options RequestOptions) (interface{}, error)
+	List(ctx context.Context, listUrl string, apiVersion string, options RequestOptions) (interface{}, error)
 }
 
 var (
@@ -114,12 +118,14 @@ func (client *DataPlaneClient) cachedPipeline(rawUrl string) (runtime.Pipeline,
 		return runtime.Pipeline{}, err
 	}
 	serviceName := cloud.ResourceManager
-	cloud := client.clientOptions.Cloud
-	host := parsedUrl.Host
-	for name, serviceConfiguration := range cloud.Services {
-		if strings.HasSuffix(host, strings.TrimPrefix(serviceConfiguration.Endpoint, "https://")) {
-			serviceName = name
-			break
+	cloudConfiguration := client.clientOptions.Cloud
+	if name, ok := matchDataPlaneService(cloudConfiguration.Services, parsedUrl.Host); ok {
+		serviceName = name
+	} else if name, ok := matchDataPlaneService(cloud.AzurePublic.Services, parsedUrl.Host); ok {
+		// the services which aren't available in the sovereign clouds are only registered in the public cloud,
+		// the token of the ResourceManager audience would be rejected by the service
+		if _, ok := cloudConfiguration.Services[name]; !ok {
+			return runtime.Pipeline{}, fmt.Errorf("the %s data plane service of the host %q is not available in this cloud, the endpoint and the audience of the service can be specified in the provider `endpoint` block if it's available", name, parsedUrl.Host)
 		}
 	}
 
@@ -129,7 +135,7 @@ func (client *DataPlaneClient) cachedPipeline(rawUrl string) (runtime.Pipeline,
 
 	plOpt := runtime.PipelineOptions{}
 	plOpt.APIVersion.Name = "api-version"
-	authPolicy := armruntime.NewBearerTokenPolicy(client.credential, &armpolicy.BearerTokenOptions{Scopes: []string{cloud.Services[serviceName].Audience + "/.default"}})
+	authPolicy := armruntime.NewBearerTokenPolicy(client.credential, &armpolicy.BearerTokenOptions{Scopes: []string{cloudConfiguration.Services[serviceName].Audience + "/.default"}})
 	plOpt.PerRetry = append(plOpt.PerRetry, authPolicy)
 	pl := runtime.NewPipeline(moduleName, moduleVersion, plOpt, &client.clientOptions.ClientOptions)
 
@@ -137,7 +143,23 @@ func (client *DataPlaneClient) cachedPipeline(rawUrl string) (runtime.Pipeline,
 	return pl, nil
 }
 
+// matchDataPlaneService returns the service whose endpoint is the longest suffix of the host,
+// so the user defined endpoints could be more specific than the built-in ones
+func matchDataPlaneService(services map[cloud.ServiceName]cloud.ServiceConfiguration, host string) (cloud.ServiceName, bool) {
+	serviceName := cloud.ServiceName("")
+	matchedEndpoint := ""
+	for name, serviceConfiguration := range services {
+		endpoint := strings.TrimSuffix(strings.TrimPrefix(serviceConfiguration.Endpoint, "https://"), "/")
+		if endpoint != "" && strings.HasSuffix(host, endpoint) && len(endpoint) > len(matchedEndpoint) {
+			serviceName = name
+			matchedEndpoint = endpoint
+		}
+	}
+	return serviceName, matchedEndpoint != ""
+}
+
 func (client *DataPlaneClient) CreateOrUpdateThenPoll(ctx context.Context, id parse.DataPlaneResourceId, body interface{}, options RequestOptions) (interface{}, error) {
+	options = options.withDefaults(client.defaultOptions)
 	// build request
 	urlPath := fmt.Sprintf("https://%s", id.AzureResourceId)
 	req, err := runtime.NewRequest(ctx, http.MethodPut, urlPath)
@@ -183,13 +205,14 @@ func (client *DataPlaneClient) CreateOrUpdateThenPoll(ctx context.Context, id pa
 
 	// unmarshal response
 	var responseBody interface{}
-	if err := runtime.UnmarshalAsJSON(resp, &responseBody); err != nil {
+	if err := unmarshalAsJSON(resp, &responseBody); err != nil {
 		return nil, err
 	}
 	return responseBody, nil
 }
 
 func (client *DataPlaneClient) Get(ctx context.Context, id parse.DataPlaneResourceId, options RequestOptions) (interface{}, error) {
+	options = options.withDefaults(client.defaultOptions)
 	// build request
 	urlPath := fmt.Sprintf("https://%s", id.AzureResourceId)
 	req, err := runtime.NewRequest(ctx, http.MethodGet, urlPath)
@@ -222,13 +245,14 @@ func (client *DataPlaneClient) Get(ctx context.Context, id parse.DataPlaneResour
 
 	// unmarshal response
 	var responseBody interface{}
-	if err := runtime.UnmarshalAsJSON(resp, &responseBody); err != nil {
+	if err := unmarshalAsJSON(resp, &responseBody); err != nil {
 		return nil, err
 	}
 	return responseBody, nil
 }
 
 func (client *DataPlaneClient) DeleteThenPoll(ctx context.Context, id parse.DataPlaneResourceId, options RequestOptions) (interface{}, error) {
+	options = options.withDefaults(client.defaultOptions)
 	// build request
 	urlPath := fmt.Sprintf("https://%s", id.AzureResourceId)
 	req, err := runtime.NewRequest(ctx, http.MethodDelete, urlPath)
@@ -270,17 +294,18 @@ func (client *DataPlaneClient) DeleteThenPoll(ctx context.Context, id parse.Data
 
 	// unmarshal response
 	var responseBody interface{}
-	if err := runtime.UnmarshalAsJSON(resp, &responseBody); err != nil {
+	if err := unmarshalAsJSON(resp, &responseBody); err != nil {
 		return nil, err
 	}
 	return responseBody, nil
 }
 
 func (client *DataPlaneClient) Action(ctx context.Context, resourceID string, action string, apiVersion string, method string, body interface{}, options RequestOptions) (interface{}, error) {
+	options = options.withDefaults(client.defaultOptions)
 	// build request
 	urlPath := fmt.Sprintf("https://%s", resourceID)
 	if action != "" {
-		urlPath = fmt.Sprintf("%s/%s", resourceID, action)
+		urlPath = fmt.Sprintf("https://%s/%s", resourceID, action)
 	}
 	req, err := runtime.NewRequest(ctx, method, urlPath)
 	if err != nil {
@@ -336,7 +361,7 @@ func (client *DataPlaneClient) Action(ctx context.Context, resourceID string, ac
 		}
 		responseBody = string(payload)
 	case strings.Contains(contentType, "application/json"):
-		if err := runtime.UnmarshalAsJSON(resp, &responseBody); err != nil {
+		if err := unmarshalAsJSON(resp, &responseBody); err != nil {
 			return nil, err
 		}
 	default:
@@ -344,6 +369,122 @@ func (client *DataPlaneClient) Action(ctx context.Context, resourceID string, ac
 	return responseBody, nil
 }
 
+// dataPlaneNextLinkNames are the property names used by the data plane APIs to return the link to the next page.
+var dataPlaneNextLinkNames = []string{"@nextLink", "nextLink", "@odata.nextLink"}
+
+// dataPlaneNextLink returns the link to the next page, or an empty string if it's the last page.
+func dataPlaneNextLink(page interface{}) string {
+	pageMap, ok := page.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	for _, name := range dataPlaneNextLinkNames {
+		if nextLink, ok := pageMap[name].(string); ok && nextLink != "" {
+			return nextLink
+		}
+	}
+	return ""
+}
+
+// dataPlanePageItems returns the items of a page. App Configuration returns the items in `items`, the other services use `value`.
+func dataPlanePageItems(page interface{}) ([]interface{}, bool) {
+	pageMap, ok := page.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	for _, key := range []string{"value", "items"} {
+		if pageValue, ok := pageMap[key].([]interface{}); ok {
+			return pageValue, true
+		}
+	}
+	return nil, false
+}
+
+func (client *DataPlaneClient) List(ctx context.Context, listUrl string, apiVersion string, options RequestOptions) (interface{}, error) {
+	options = options.withDefaults(client.defaultOptions)
+	urlPath := fmt.Sprintf("https://%s", listUrl)
+	baseUrl, err := url.Parse(urlPath)
+	if err != nil {
+		return nil, err
+	}
+	pipeline, err := client.cachedPipeline(urlPath)
+	if err != nil {
+		return nil, err
+	}
+
+	pager := runtime.NewPager(runtime.PagingHandler[interface{}]{
+		More: func(current interface{}) bool {
+			return dataPlaneNextLink(current) != ""
+		},
+		Fetcher: func(ctx context.Context, current *interface{}) (interface{}, error) {
+			var req *policy.Request
+			var err error
+			if current == nil {
+				req, err = runtime.NewRequest(ctx, http.MethodGet, urlPath)
+				if err != nil {
+					return nil, err
+				}
+				reqQP := req.Raw().URL.Query()
+				reqQP.Set("api-version", apiVersion)
+				for key, value := range options.QueryParameters {
+					reqQP.Set(key, value)
+				}
+				req.Raw().URL.RawQuery = reqQP.Encode()
+			} else {
+				// some services return the next link relative to the endpoint, e.g. `/kv?after=xxx`
+				nextLink, err := baseUrl.Parse(dataPlaneNextLink(*current))
+				if err != nil {
+					return nil, err
+				}
+				req, err = runtime.NewRequest(ctx, http.MethodGet, nextLink.String())
+				if err != nil {
+					return nil, err
+				}
+				if !req.Raw().URL.Query().Has("api-version") {
+					reqQP := req.Raw().URL.Query()
+					reqQP.Set("api-version", apiVersion)
+					req.Raw().URL.RawQuery = reqQP.Encode()
+				}
+			}
+			req.Raw().Header.Set("Accept", "application/json")
+			for key, value := range options.Headers {
+				req.Raw().Header.Set(key, value)
+			}
+			resp, err := pipeline.Do(req)
+			if err != nil {
+				return nil, err
+			}
+			if !runtime.HasStatusCode(resp, http.StatusOK) {
+				return nil, runtime.NewResponseError(resp)
+			}
+			var responseBody interface{}
+			if err := unmarshalAsJSON(resp, &responseBody); err != nil {
+				return nil, err
+			}
+			return responseBody, nil
+		},
+	})
+
+	value := make([]interface{}, 0)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if pageValue, ok := dataPlanePageItems(page); ok {
+			value = append(value, pageValue...)
+			continue
+		}
+
+		// if response doesn't follow the paging convention, return the response as is
+		return page, nil
+	}
+	return map[string]interface{}{
+		"value": value,
+	}, nil
+}
+
 func (retryclient *DataPlaneClientRetryableErrors) CreateOrUpdateThenPoll(ctx context.Context, id parse.DataPlaneResourceId, body interface{}, options RequestOptions) (interface{}, error) {
 	if retryclient.backoff == nil {
 		return nil, errors.New("retry is not configured, please call WithRetry() first")
@@ -484,6 +625,41 @@ func (retryclient *DataPlaneClientRetryableErrors) Action(ctx context.Context, r
 	return backoff.RetryWithData(op, exbo)
 }
 
+func (retryclient *DataPlaneClientRetryableErrors) List(ctx context.Context, listUrl string, apiVersion string, options RequestOptions) (interface{}, error) {
+	if retryclient.backoff == nil {
+		return nil, errors.New("retry is not configured, please call WithRetry() first")
+	}
+	ctx = tflog.SetField(ctx, "request", "List")
+	ctx = retryclient.updateContext(ctx)
+	tflog.Debug(ctx, "retryclient: Begin")
+	i := 0
+	op := backoff.OperationWithData[interface{}](
+		func() (interface{}, error) {
+			data, err := retryclient.client.List(ctx, listUrl, apiVersion, options)
+			if err != nil {
+				if isDataPlaneRetryable(ctx, *retryclient, data, err) {
+					tflog.Debug(ctx, "retryclient: Retry attempt", map[string]interface{}{
+						"err":     err,
+						"attempt": i,
+					})
+					i++
+					return data, err
+				}
+				tflog.Debug(ctx, "retryclient: PermanentError", map[string]interface{}{
+					"err":     err,
+					"attempt": i,
+				})
+				return nil, &backoff.PermanentError{Err: err}
+			}
+			tflog.Debug(ctx, "retryclient: Success", map[string]interface{}{
+				"attempt": i,
+			})
+			return data, err
+		})
+	exbo := backoff.WithContext(retryclient.backoff, ctx)
+	return backoff.RetryWithData(op, exbo)
+}
+
 func isDataPlaneRetryable(ctx context.Context, retryclient DataPlaneClientRetryableErrors, data interface{}, err error) bool {
 	for _, e := range retryclient.errors {
 		if e.MatchString(err.Error()) {
@@ -3,9 +3,15 @@ package clients_test
 import (
 	"context"
 	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/terraform-provider-azapi/internal/clients"
 	"github.com/Azure/terraform-provider-azapi/internal/services/parse"
 	"github.com/cenkalti/backoff/v4"
@@ -124,3 +130,103 @@ func TestRetryDataPlaneClientContextDeadline(t *testing.T) {
 	_, ok := <-ctx.Done()
 	assert.False(t, ok)
 }
+
+type fakeTokenCredential struct{}
+
+func (fakeTokenCredential) GetToken(_ context.Context, _ policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{Token: "token", ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+func TestDataPlaneClientList(t *testing.T) {
+	t.Parallel()
+	testcases := []struct {
+		Name     string
+		Pages    map[string]string
+		Expected interface{}
+	}{
+		{
+			Name: "@nextLink relative to the endpoint",
+			Pages: map[string]string{
+				"":        `{"items": [{"key": "a"}], "@nextLink": "/kv?after=a&api-version=1.0"}`,
+				"after=a": `{"items": [{"key": "b"}]}`,
+			},
+			Expected: map[string]interface{}{"value": []interface{}{map[string]interface{}{"key": "a"}, map[string]interface{}{"key": "b"}}},
+		},
+		{
+			Name: "nextLink",
+			Pages: map[string]string{
+				"":        `{"value": [{"id": "a"}], "nextLink": "{host}/kv?after=a"}`,
+				"after=a": `{"value": [{"id": "b"}], "nextLink": null}`,
+			},
+			Expected: map[string]interface{}{"value": []interface{}{map[string]interface{}{"id": "a"}, map[string]interface{}{"id": "b"}}},
+		},
+		{
+			Name: "@odata.nextLink",
+			Pages: map[string]string{
+				"":        `{"value": [{"id": "a"}], "@odata.nextLink": "{host}/kv?after=a"}`,
+				"after=a": `{"value": []}`,
+			},
+			Expected: map[string]interface{}{"value": []interface{}{map[string]interface{}{"id": "a"}}},
+		},
+		{
+			Name: "not paged",
+			Pages: map[string]string{
+				"": `{"name": "a"}`,
+			},
+			Expected: map[string]interface{}{"name": "a"},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.Name, func(t *testing.T) {
+			var server *httptest.Server
+			server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				after := r.URL.Query().Get("after")
+				if r.URL.Query().Get("api-version") != "1.0" || (after == "" && r.URL.Query().Get("key") != "prefix*") {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				page := ""
+				if after != "" {
+					page = "after=" + after
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(strings.ReplaceAll(tc.Pages[page], "{host}", server.URL)))
+			}))
+			defer server.Close()
+
+			client, err := clients.NewDataPlaneClient(fakeTokenCredential{}, &arm.ClientOptions{
+				ClientOptions: policy.ClientOptions{
+					Transport: server.Client(),
+				},
+			})
+			assert.NoError(t, err)
+			actual, err := client.List(context.Background(), strings.TrimPrefix(server.URL, "https://")+"/kv?key=prefix*", "1.0", clients.DefaultRequestOptions())
+			assert.NoError(t, err)
+			assert.Equal(t, tc.Expected, actual)
+		})
+	}
+}
+
+func TestDataPlaneClientAction(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/keys/mykey/backup" || r.URL.Query().Get("api-version") != "7.4" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"value": "backup"}`))
+	}))
+	defer server.Close()
+
+	client, err := clients.NewDataPlaneClient(fakeTokenCredential{}, &arm.ClientOptions{
+		ClientOptions: policy.ClientOptions{
+			Transport: server.Client(),
+		},
+	})
+	assert.NoError(t, err)
+	actual, err := client.Action(context.Background(), strings.TrimPrefix(server.URL, "https://")+"/keys/mykey", "backup", "7.4", http.MethodPost, nil, clients.DefaultRequestOptions())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"value": "backup"}, actual)
+}
@@ -0,0 +1,35 @@
+package clients
+
+import (
+	"context"
+	"log"
+
+	uuid "github.com/hashicorp/go-uuid"
+)
+
+type operationContextKey struct{}
+
+// operation is a Terraform operation, for example, creating a resource or reading a data source, which may send multiple requests
+type operation struct {
+	// CorrelationRequestID is the correlation request ID of the requests in the operation when the per-operation correlation request ID is enabled
+	CorrelationRequestID string
+	ResourceID           string
+}
+
+// WithOperation returns a context which marks the start of a Terraform operation on the resource,
+// the requests sent with the context share the same per-operation correlation request ID and are recorded with the resource ID in the operation journal.
+func WithOperation(ctx context.Context, resourceID string) context.Context {
+	id, err := uuid.GenerateUUID()
+	if err != nil {
+		log.Printf("[WARN] Failed to generate uuid for the operation of %s: %+v", resourceID, err)
+	}
+	return context.WithValue(ctx, operationContextKey{}, operation{
+		CorrelationRequestID: id,
+		ResourceID:           resourceID,
+	})
+}
+
+func operationFromContext(ctx context.Context) (operation, bool) {
+	op, ok := ctx.Value(operationContextKey{}).(operation)
+	return op, ok
+}
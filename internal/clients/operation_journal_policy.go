@@ -0,0 +1,83 @@
+package clients
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// operationJournalPolicy writes a JSON line for each request to the journal file, so the requests of a resource can be found by the correlation request ID.
+type operationJournalPolicy struct {
+	lock sync.Mutex
+	file *os.File
+}
+
+type operationJournalEntry struct {
+	Time                 string `json:"time"`
+	CorrelationRequestID string `json:"correlation_request_id"`
+	RequestID            string `json:"request_id"`
+	ResourceID           string `json:"resource_id"`
+	Method               string `json:"method"`
+	Url                  string `json:"url"`
+	StatusCode           int    `json:"status_code"`
+	DurationMs           int64  `json:"duration_ms"`
+	Error                string `json:"error,omitempty"`
+}
+
+// NewOperationJournalPolicy returns a policy which appends the requests to the journal file in the JSON lines format, the file is created if it doesn't exist
+func NewOperationJournalPolicy(path string) (policy.Policy, error) {
+	// #nosec G304
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("opening the operation journal %q: %+v", path, err)
+	}
+	return &operationJournalPolicy{
+		file: file,
+	}, nil
+}
+
+func (p *operationJournalPolicy) Do(req *policy.Request) (*http.Response, error) {
+	rawRequest := req.Raw()
+	start := time.Now()
+	response, err := req.Next()
+
+	entry := operationJournalEntry{
+		Time:                 start.UTC().Format(time.RFC3339Nano),
+		CorrelationRequestID: rawRequest.Header.Get(HeaderCorrelationRequestID),
+		ResourceID:           rawRequest.URL.Path,
+		Method:               rawRequest.Method,
+		Url:                  rawRequest.URL.Scheme + "://" + rawRequest.URL.Host + rawRequest.URL.Path,
+		DurationMs:           time.Since(start).Milliseconds(),
+	}
+	if op, ok := operationFromContext(rawRequest.Context()); ok {
+		entry.ResourceID = op.ResourceID
+	}
+	if err == nil {
+		entry.StatusCode = response.StatusCode
+		entry.RequestID = response.Header.Get("x-ms-request-id")
+	} else {
+		entry.Error = err.Error()
+	}
+	p.write(entry)
+
+	return response, err
+}
+
+func (p *operationJournalPolicy) write(entry operationJournalEntry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		log.Printf("[ERROR] Failed to marshal the operation journal entry: %v", err)
+		return
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if _, err := p.file.Write(append(data, '\n')); err != nil {
+		log.Printf("[ERROR] Failed to write the operation journal: %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package clients
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOperationJournal(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("x-ms-request-id", "request-"+r.Header.Get(HeaderCorrelationRequestID))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	journalPolicy, err := NewOperationJournalPolicy(path)
+	assert.NoError(t, err)
+	pl := runtime.NewPipeline("test", "v0.1.0", runtime.PipelineOptions{}, &policy.ClientOptions{
+		PerCallPolicies:  []policy.Policy{withCorrelationRequestID("provider", true)},
+		PerRetryPolicies: []policy.Policy{journalPolicy},
+		Transport:        server.Client(),
+	})
+
+	send := func(ctx context.Context, method string, path string) {
+		req, err := runtime.NewRequest(ctx, method, server.URL+path)
+		assert.NoError(t, err)
+		resp, err := pl.Do(req)
+		assert.NoError(t, err)
+		_ = resp.Body.Close()
+	}
+
+	resourceId := "/subscriptions/000/resourceGroups/rg"
+	first := WithOperation(context.Background(), resourceId)
+	send(first, http.MethodPut, resourceId)
+	send(first, http.MethodGet, resourceId)
+	second := WithOperation(context.Background(), resourceId)
+	send(second, http.MethodGet, resourceId)
+	send(context.Background(), http.MethodGet, "/subscriptions/000")
+
+	file, err := os.Open(path)
+	assert.NoError(t, err)
+	defer file.Close()
+	entries := make([]operationJournalEntry, 0)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry operationJournalEntry
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		entries = append(entries, entry)
+	}
+	assert.Len(t, entries, 4)
+
+	// the requests in the same operation share the correlation request ID
+	assert.Equal(t, entries[0].CorrelationRequestID, entries[1].CorrelationRequestID)
+	assert.NotEqual(t, entries[0].CorrelationRequestID, entries[2].CorrelationRequestID)
+	assert.NotEqual(t, "provider", entries[0].CorrelationRequestID)
+	// the requests out of any operation use the provider's correlation request ID
+	assert.Equal(t, "provider", entries[3].CorrelationRequestID)
+
+	assert.Equal(t, http.MethodPut, entries[0].Method)
+	assert.Equal(t, resourceId, entries[0].ResourceID)
+	assert.Equal(t, http.StatusOK, entries[0].StatusCode)
+	assert.Equal(t, "request-"+entries[0].CorrelationRequestID, entries[0].RequestID)
+	assert.Equal(t, "/subscriptions/000", entries[3].ResourceID)
+}
@@ -1,6 +1,9 @@
 package clients
 
-import "strings"
+import (
+	"net/http"
+	"strings"
+)
 
 type RequestOptions struct {
 	Headers         map[string]string
@@ -25,3 +28,25 @@ func NewRequestOptions(headers map[string]string, queryParameters map[string][]s
 
 	return opts
 }
+
+// withDefaults returns the request options merged with the default options, the values in the request options take precedence.
+// The header names are case-insensitive, so a default header is skipped if the same header is specified in the request options.
+func (o RequestOptions) withDefaults(defaults RequestOptions) RequestOptions {
+	if len(defaults.Headers) == 0 && len(defaults.QueryParameters) == 0 {
+		return o
+	}
+	out := DefaultRequestOptions()
+	for key, value := range defaults.Headers {
+		out.Headers[http.CanonicalHeaderKey(key)] = value
+	}
+	for key, value := range o.Headers {
+		out.Headers[http.CanonicalHeaderKey(key)] = value
+	}
+	for key, value := range defaults.QueryParameters {
+		out.QueryParameters[key] = value
+	}
+	for key, value := range o.QueryParameters {
+		out.QueryParameters[key] = value
+	}
+	return out
+}
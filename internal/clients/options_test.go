@@ -0,0 +1,47 @@
+package clients
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestOptionsWithDefaults(t *testing.T) {
+	testcases := []struct {
+		Name     string
+		Options  RequestOptions
+		Defaults RequestOptions
+		Expected RequestOptions
+	}{
+		{
+			Name:     "no defaults",
+			Options:  NewRequestOptions(map[string]string{"x-ms-foo": "bar"}, nil),
+			Defaults: DefaultRequestOptions(),
+			Expected: NewRequestOptions(map[string]string{"x-ms-foo": "bar"}, nil),
+		},
+		{
+			Name:     "defaults only",
+			Options:  NewRequestOptions(nil, nil),
+			Defaults: NewRequestOptions(map[string]string{"x-ms-client-tenant-id": "tenant"}, map[string][]string{"feature": {"a", "b"}}),
+			Expected: RequestOptions{
+				Headers:         map[string]string{"X-Ms-Client-Tenant-Id": "tenant"},
+				QueryParameters: map[string]string{"feature": "a,b"},
+			},
+		},
+		{
+			Name:     "request options take precedence",
+			Options:  NewRequestOptions(map[string]string{"X-MS-FOO": "request"}, map[string][]string{"feature": {"request"}}),
+			Defaults: NewRequestOptions(map[string]string{"x-ms-foo": "default", "x-ms-bar": "default"}, map[string][]string{"feature": {"default"}, "other": {"default"}}),
+			Expected: RequestOptions{
+				Headers:         map[string]string{"X-Ms-Foo": "request", "X-Ms-Bar": "default"},
+				QueryParameters: map[string]string{"feature": "request", "other": "default"},
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, tc.Options.withDefaults(tc.Defaults))
+		})
+	}
+}
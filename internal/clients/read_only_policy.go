@@ -0,0 +1,60 @@
+package clients
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// readOnlyPolicy rejects the requests which may change the resources when the provider is in read-only mode,
+// only the GET and HEAD requests, and the POST requests of the allowed actions are sent.
+type readOnlyPolicy struct {
+	// allowedActions are the lower case names of the POST actions which are allowed, for example, `listkeys`
+	allowedActions map[string]bool
+}
+
+// ReadOnlyModeError is returned when a request is rejected because the provider is in read-only mode
+type ReadOnlyModeError struct {
+	Method     string
+	ResourceID string
+}
+
+func (e *ReadOnlyModeError) Error() string {
+	return fmt.Sprintf("the provider is in read-only mode, the %s request to %s is blocked. The `read_only` must be disabled to change the resources, "+
+		"or the action must be added to the `read_only_allowed_actions` if it's a read-only POST action", e.Method, e.ResourceID)
+}
+
+// NewReadOnlyPolicy returns a policy which rejects the mutating requests, the POST requests of the allowed actions, for example, `listKeys`, are sent
+func NewReadOnlyPolicy(allowedActions []string) policy.Policy {
+	actions := make(map[string]bool)
+	for _, action := range allowedActions {
+		actions[strings.ToLower(action)] = true
+	}
+	return readOnlyPolicy{
+		allowedActions: actions,
+	}
+}
+
+func (p readOnlyPolicy) Do(req *policy.Request) (*http.Response, error) {
+	rawRequest := req.Raw()
+	switch rawRequest.Method {
+	case http.MethodGet, http.MethodHead:
+		return req.Next()
+	case http.MethodPost:
+		if p.allowedActions[strings.ToLower(path.Base(rawRequest.URL.Path))] {
+			return req.Next()
+		}
+	}
+
+	resourceId := rawRequest.URL.Path
+	if op, ok := operationFromContext(rawRequest.Context()); ok {
+		resourceId = op.ResourceID
+	}
+	return nil, &ReadOnlyModeError{
+		Method:     rawRequest.Method,
+		ResourceID: resourceId,
+	}
+}
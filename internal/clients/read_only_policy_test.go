@@ -0,0 +1,65 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadOnlyPolicy(t *testing.T) {
+	received := make([]string, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Method+" "+r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	pl := runtime.NewPipeline("test", "v0.1.0", runtime.PipelineOptions{}, &policy.ClientOptions{
+		PerCallPolicies: []policy.Policy{NewReadOnlyPolicy([]string{"listKeys"})},
+		Retry:           policy.RetryOptions{MaxRetries: -1},
+		Transport:       server.Client(),
+	})
+
+	resourceId := "/subscriptions/000/resourceGroups/rg/providers/Microsoft.Storage/storageAccounts/account"
+	ctx := WithOperation(context.Background(), resourceId)
+	testcases := []struct {
+		Method  string
+		Path    string
+		Blocked bool
+	}{
+		{Method: http.MethodGet, Path: resourceId},
+		{Method: http.MethodHead, Path: resourceId},
+		{Method: http.MethodPost, Path: resourceId + "/listkeys"},
+		{Method: http.MethodPost, Path: resourceId + "/regenerateKey", Blocked: true},
+		{Method: http.MethodPut, Path: resourceId, Blocked: true},
+		{Method: http.MethodPatch, Path: resourceId, Blocked: true},
+		{Method: http.MethodDelete, Path: resourceId, Blocked: true},
+	}
+	for _, tc := range testcases {
+		req, err := runtime.NewRequest(ctx, tc.Method, server.URL+tc.Path)
+		assert.NoError(t, err)
+		resp, err := pl.Do(req)
+		if !tc.Blocked {
+			assert.NoError(t, err)
+			_ = resp.Body.Close()
+			continue
+		}
+		var readOnlyErr *ReadOnlyModeError
+		if assert.True(t, errors.As(err, &readOnlyErr), "%s %s should be blocked", tc.Method, tc.Path) {
+			assert.Equal(t, tc.Method, readOnlyErr.Method)
+			assert.Equal(t, resourceId, readOnlyErr.ResourceID)
+			assert.Contains(t, err.Error(), resourceId)
+		}
+	}
+	assert.Equal(t, []string{
+		"GET " + resourceId,
+		"HEAD " + resourceId,
+		"POST " + resourceId + "/listkeys",
+	}, received)
+}
@@ -1,6 +1,7 @@
 package clients
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -17,8 +18,11 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
 	"github.com/Azure/terraform-provider-azapi/internal/retry"
+	"github.com/Azure/terraform-provider-azapi/internal/tracing"
+	"github.com/Azure/terraform-provider-azapi/utils"
 	"github.com/cenkalti/backoff/v4"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 const (
@@ -29,6 +33,8 @@ const (
 type ResourceClient struct {
 	host string
 	pl   runtime.Pipeline
+	// defaultOptions are the default headers and query parameters which are sent with all requests
+	defaultOptions RequestOptions
 }
 
 // ResourceClientRetryableErrors is a wrapper around ResourceClient that allows for retrying on specific errors.
@@ -85,6 +91,11 @@ func NewResourceClient(credential azcore.TokenCredential, opt *arm.ClientOptions
 	}, nil
 }
 
+// Host returns the endpoint of the Azure Resource Manager which the requests are sent to.
+func (client *ResourceClient) Host() string {
+	return client.host
+}
+
 // StringSliceToRegexpSliceMust converts a slice of strings to a slice of regexps.
 // It panics if any of the strings are invalid regexps.
 func StringSliceToRegexpSliceMust(ss []string) []regexp.Regexp {
@@ -138,7 +149,9 @@ func (retryclient *ResourceClientRetryableErrors) CreateOrUpdate(ctx context.Con
 	i := 0
 	op := backoff.OperationWithData[interface{}](
 		func() (interface{}, error) {
-			data, err := retryclient.client.CreateOrUpdate(ctx, resourceID, apiVersion, body, options)
+			attemptCtx, span := tracing.Start(ctx, "retryclient.CreateOrUpdate", resourceID, attribute.Int("attempt", i))
+			data, err := retryclient.client.CreateOrUpdate(attemptCtx, resourceID, apiVersion, body, options)
+			tracing.End(span, err)
 			if err != nil {
 				if isRetryable(ctx, *retryclient, data, err) {
 					tflog.Debug(ctx, "retryclient: Retry attempt", map[string]interface{}{
@@ -181,7 +194,7 @@ func (client *ResourceClient) CreateOrUpdate(ctx context.Context, resourceID str
 			return nil, err
 		}
 	}
-	if err := runtime.UnmarshalAsJSON(resp, &responseBody); err != nil {
+	if err := unmarshalAsJSON(resp, &responseBody); err != nil {
 		return nil, err
 	}
 	return responseBody, nil
@@ -203,6 +216,7 @@ func (client *ResourceClient) createOrUpdate(ctx context.Context, resourceID str
 }
 
 func (client *ResourceClient) createOrUpdateCreateRequest(ctx context.Context, resourceID string, apiVersion string, body interface{}, options RequestOptions) (*policy.Request, error) {
+	options = options.withDefaults(client.defaultOptions)
 	urlPath := resourceID
 	req, err := runtime.NewRequest(ctx, http.MethodPut, runtime.JoinPaths(client.host, urlPath))
 	if err != nil {
@@ -235,7 +249,9 @@ func (retryclient *ResourceClientRetryableErrors) Get(ctx context.Context, resou
 	i := 0
 	op := backoff.OperationWithData[interface{}](
 		func() (interface{}, error) {
-			data, err := retryclient.client.Get(ctx, resourceID, apiVersion, options)
+			attemptCtx, span := tracing.Start(ctx, "retryclient.Get", resourceID, attribute.Int("attempt", i))
+			data, err := retryclient.client.Get(attemptCtx, resourceID, apiVersion, options)
+			tracing.End(span, err)
 			if err != nil {
 				if isRetryable(ctx, *retryclient, data, err) {
 					tflog.Debug(ctx, "retryclient: Retry attempt", map[string]interface{}{
@@ -274,13 +290,14 @@ func (client *ResourceClient) Get(ctx context.Context, resourceID string, apiVer
 	}
 
 	var responseBody interface{}
-	if err := runtime.UnmarshalAsJSON(resp, &responseBody); err != nil {
+	if err := unmarshalAsJSON(resp, &responseBody); err != nil {
 		return nil, err
 	}
 	return responseBody, nil
 }
 
 func (client *ResourceClient) getCreateRequest(ctx context.Context, resourceID string, apiVersion string, options RequestOptions) (*policy.Request, error) {
+	options = options.withDefaults(client.defaultOptions)
 	urlPath := resourceID
 	req, err := runtime.NewRequest(ctx, http.MethodGet, runtime.JoinPaths(client.host, urlPath))
 	if err != nil {
@@ -313,7 +330,9 @@ func (retryclient *ResourceClientRetryableErrors) Delete(ctx context.Context, re
 	i := 0
 	op := backoff.OperationWithData[interface{}](
 		func() (interface{}, error) {
-			data, err := retryclient.client.Delete(ctx, resourceID, apiVersion, options)
+			attemptCtx, span := tracing.Start(ctx, "retryclient.Delete", resourceID, attribute.Int("attempt", i))
+			data, err := retryclient.client.Delete(attemptCtx, resourceID, apiVersion, options)
+			tracing.End(span, err)
 			if err != nil {
 				if isRetryable(ctx, *retryclient, data, err) {
 					tflog.Debug(ctx, "retryclient: Retry attempt", map[string]interface{}{
@@ -356,7 +375,7 @@ func (client *ResourceClient) Delete(ctx context.Context, resourceID string, api
 			return nil, err
 		}
 	}
-	if err := runtime.UnmarshalAsJSON(resp, &responseBody); err != nil {
+	if err := unmarshalAsJSON(resp, &responseBody); err != nil {
 		return nil, err
 	}
 	return responseBody, nil
@@ -378,6 +397,7 @@ func (client *ResourceClient) delete(ctx context.Context, resourceID string, api
 }
 
 func (client *ResourceClient) deleteCreateRequest(ctx context.Context, resourceID string, apiVersion string, options RequestOptions) (*policy.Request, error) {
+	options = options.withDefaults(client.defaultOptions)
 	urlPath := resourceID
 	req, err := runtime.NewRequest(ctx, http.MethodDelete, runtime.JoinPaths(client.host, urlPath))
 	if err != nil {
@@ -410,7 +430,9 @@ func (retryclient *ResourceClientRetryableErrors) Action(ctx context.Context, re
 	i := 0
 	op := backoff.OperationWithData[interface{}](
 		func() (interface{}, error) {
-			data, err := retryclient.client.Action(ctx, resourceID, action, apiVersion, method, body, options)
+			attemptCtx, span := tracing.Start(ctx, "retryclient.Action", resourceID, attribute.Int("attempt", i))
+			data, err := retryclient.client.Action(attemptCtx, resourceID, action, apiVersion, method, body, options)
+			tracing.End(span, err)
 			if err != nil {
 				if isRetryable(ctx, *retryclient, data, err) {
 					tflog.Debug(ctx, "retryclient: Retry attempt", map[string]interface{}{
@@ -463,7 +485,7 @@ func (client *ResourceClient) Action(ctx context.Context, resourceID string, act
 		}
 		responseBody = string(payload)
 	case strings.Contains(contentType, "application/json"):
-		if err := runtime.UnmarshalAsJSON(resp, &responseBody); err != nil {
+		if err := unmarshalAsJSON(resp, &responseBody); err != nil {
 			return nil, err
 		}
 	default:
@@ -487,6 +509,7 @@ func (client *ResourceClient) action(ctx context.Context, resourceID string, act
 }
 
 func (client *ResourceClient) actionCreateRequest(ctx context.Context, resourceID string, action string, apiVersion string, method string, body interface{}, options RequestOptions) (*policy.Request, error) {
+	options = options.withDefaults(client.defaultOptions)
 	urlPath := resourceID
 	if action != "" {
 		urlPath = fmt.Sprintf("%s/%s", resourceID, action)
@@ -525,7 +548,9 @@ func (retryclient *ResourceClientRetryableErrors) List(ctx context.Context, url
 	i := 0
 	op := backoff.OperationWithData[interface{}](
 		func() (interface{}, error) {
-			data, err := retryclient.client.List(ctx, url, apiVersion, options)
+			attemptCtx, span := tracing.Start(ctx, "retryclient.List", url, attribute.Int("attempt", i))
+			data, err := retryclient.client.List(attemptCtx, url, apiVersion, options)
+			tracing.End(span, err)
 			if err != nil {
 				if isRetryable(ctx, *retryclient, data, err) {
 					tflog.Debug(ctx, "retryclient: Retry attempt", map[string]interface{}{
@@ -551,6 +576,7 @@ func (retryclient *ResourceClientRetryableErrors) List(ctx context.Context, url
 }
 
 func (client *ResourceClient) List(ctx context.Context, url string, apiVersion string, options RequestOptions) (interface{}, error) {
+	options = options.withDefaults(client.defaultOptions)
 	pager := runtime.NewPager(runtime.PagingHandler[interface{}]{
 		More: func(current interface{}) bool {
 			if current == nil {
@@ -605,7 +631,7 @@ func (client *ResourceClient) List(ctx context.Context, url string, apiVersion s
 				return nil, runtime.NewResponseError(resp)
 			}
 			var responseBody interface{}
-			if err := runtime.UnmarshalAsJSON(resp, &responseBody); err != nil {
+			if err := unmarshalAsJSON(resp, &responseBody); err != nil {
 				return nil, err
 			}
 			return responseBody, nil
@@ -701,3 +727,20 @@ func (client *ResourceClient) ConfigureClientWithCustomRetry(ctx context.Context
 	backOff, errRegExps, statusCodes := configureCustomRetry(ctx, rtry, useReadAfterCreateValues)
 	return client.WithRetry(backOff, errRegExps, statusCodes, nil)
 }
+
+// unmarshalAsJSON is similar to runtime.UnmarshalAsJSON, but the numbers are decoded as json.Number to keep their precision.
+func unmarshalAsJSON(resp *http.Response, v interface{}) error {
+	payload, err := runtime.Payload(resp)
+	if err != nil {
+		return err
+	}
+	// some services return a UTF-8 BOM at the beginning of the payload
+	payload = bytes.TrimPrefix(payload, []byte("\xef\xbb\xbf"))
+	if len(payload) == 0 {
+		return nil
+	}
+	if err := utils.UnmarshalJSON(payload, v); err != nil {
+		return fmt.Errorf("unmarshalling type %T: %v", v, err)
+	}
+	return nil
+}
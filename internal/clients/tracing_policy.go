@@ -0,0 +1,53 @@
+package clients
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/terraform-provider-azapi/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracingPolicy creates a span for each HTTP request, including the retries of the pipeline and the polling requests of the long-running operations
+type tracingPolicy struct{}
+
+// NewTracingPolicy returns a policy which creates the spans of the HTTP requests, the query parameters are not recorded because they may contain secrets
+func NewTracingPolicy() policy.Policy {
+	return tracingPolicy{}
+}
+
+func (p tracingPolicy) Do(req *policy.Request) (*http.Response, error) {
+	rawRequest := req.Raw()
+	resourceId := rawRequest.URL.Path
+	if op, ok := operationFromContext(rawRequest.Context()); ok {
+		resourceId = op.ResourceID
+	}
+	ctx, span := tracing.Tracer().Start(rawRequest.Context(), fmt.Sprintf("HTTP %s", rawRequest.Method),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", rawRequest.Method),
+			attribute.String("url.full", rawRequest.URL.Scheme+"://"+rawRequest.URL.Host+rawRequest.URL.Path),
+			attribute.String("server.address", rawRequest.URL.Hostname()),
+			attribute.String(tracing.AttributeResourceID, resourceId),
+			attribute.String("az.correlation_request_id", rawRequest.Header.Get(HeaderCorrelationRequestID)),
+		))
+	defer span.End()
+
+	response, err := req.Clone(ctx).Next()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return response, err
+	}
+	span.SetAttributes(
+		attribute.Int("http.response.status_code", response.StatusCode),
+		attribute.String("az.service_request_id", response.Header.Get("x-ms-request-id")),
+	)
+	if response.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, response.Status)
+	}
+	return response, nil
+}
@@ -0,0 +1,69 @@
+package clients
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/terraform-provider-azapi/internal/tracing"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracingPolicy(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(previous)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("x-ms-request-id", "request")
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	pl := runtime.NewPipeline("test", "v0.1.0", runtime.PipelineOptions{}, &policy.ClientOptions{
+		PerRetryPolicies: []policy.Policy{NewTracingPolicy()},
+		Retry:            policy.RetryOptions{MaxRetries: -1},
+		Transport:        server.Client(),
+	})
+
+	resourceId := "/subscriptions/000/resourceGroups/rg"
+	ctx := WithOperation(context.Background(), resourceId)
+	ctx, span := tracing.Start(ctx, "azapi_resource.Create", resourceId)
+	for _, method := range []string{http.MethodGet, http.MethodDelete} {
+		req, err := runtime.NewRequest(ctx, method, server.URL+resourceId+"?api-version=2020-01-01")
+		assert.NoError(t, err)
+		resp, err := pl.Do(req)
+		assert.NoError(t, err)
+		_ = resp.Body.Close()
+	}
+	span.End()
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 3)
+	get, del, parent := spans[0], spans[1], spans[2]
+	assert.Equal(t, "azapi_resource.Create", parent.Name)
+	assert.Equal(t, "HTTP GET", get.Name)
+	assert.Equal(t, parent.SpanContext.SpanID(), get.Parent.SpanID())
+	assert.Contains(t, get.Attributes, attribute.String(tracing.AttributeResourceID, resourceId))
+	assert.Contains(t, get.Attributes, attribute.Int("http.response.status_code", http.StatusOK))
+	assert.Contains(t, get.Attributes, attribute.String("az.service_request_id", "request"))
+	// the query parameters are not recorded
+	assert.Contains(t, get.Attributes, attribute.String("url.full", server.URL+resourceId))
+	assert.Equal(t, codes.Unset, get.Status.Code)
+	assert.Equal(t, "HTTP DELETE", del.Name)
+	assert.Equal(t, codes.Error, del.Status.Code)
+}
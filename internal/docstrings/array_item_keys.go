@@ -0,0 +1,10 @@
+package docstrings
+
+const (
+	arrayItemKeysStr = `A mapping from the path of an array in the %sbody%s to the path of the property which identifies its items, for example, %s{ "properties.securityRules" = "properties.priority" }%s. The items of these arrays are matched by the key instead of their positions when comparing the %sbody%s with the remote state, so reordered items don't produce a plan diff. It's used for the arrays whose item identifier is not defined in the schema, and it overrides the built-in identifiers of the known resource types, for example, the custom rules of the web application firewall policies are matched by %spriority%s and the rule collections of the firewall policies are matched by %sname%s. The other arrays are matched by the %sname%s property.`
+)
+
+// ArrayItemKeys returns the docstring for the array_item_keys schema attribute.
+func ArrayItemKeys() string {
+	return addBackquotes(arrayItemKeysStr)
+}
@@ -0,0 +1,10 @@
+package docstrings
+
+const (
+	autoLockParentStr = `Whether to lock the parent resource when this resource is created, updated or deleted, if the resource type is known to conflict with the other child resources of the same parent resource, for example, the subnets of a virtual network or the security rules of a network security group. The parent resource is locked the same way as it's specified in the %slocks%s. Defaults to the %sauto_lock_parent%s in the provider block.`
+)
+
+// AutoLockParent returns the docstring for the auto_lock_parent schema attribute.
+func AutoLockParent() string {
+	return addBackquotes(autoLockParentStr)
+}
@@ -0,0 +1,24 @@
+package docstrings
+
+const (
+	dataPlaneActionParentIDStr = `The ID of the azure resource which contains the resource to perform the action on, for example, %smyvault.vault.azure.net%s.`
+
+	dataPlaneActionNameStr = `The name of the data plane resource to perform the action on. If it's omitted, the action is performed on the collection of the resource type, for example, the %srestore%s action of the Key Vault keys.`
+
+	dataPlaneActionStr = `The name of the resource action, for example, %sbackup%s. It's also possible to make HTTP requests towards the resource ID if leave this field empty.`
+)
+
+// DataPlaneActionParentID returns the docstring for the parent_id schema attribute of the data plane resource action.
+func DataPlaneActionParentID() string {
+	return addBackquotes(dataPlaneActionParentIDStr)
+}
+
+// DataPlaneActionName returns the docstring for the name schema attribute of the data plane resource action.
+func DataPlaneActionName() string {
+	return addBackquotes(dataPlaneActionNameStr)
+}
+
+// DataPlaneAction returns the docstring for the action schema attribute of the data plane resource action.
+func DataPlaneAction() string {
+	return addBackquotes(dataPlaneActionStr)
+}
@@ -0,0 +1,10 @@
+package docstrings
+
+const (
+	managementLockBehaviorStr = `The behavior when the resource is protected by the management locks, the %sMicrosoft.Authorization/locks%s resources, at or above its scope. Possible values are %snone%s and %slift_and_restore%s. Defaults to %snone%s, which means the update or deletion fails with the %sScopeLocked%s error if it's blocked by a lock. When set to %slift_and_restore%s, the %sReadOnly%s locks which block the update, or the %sReadOnly%s and %sCanNotDelete%s locks which block the deletion, are deleted before the operation and recreated with the same level, notes and owners after it, even if the operation fails. The locks at the scope of the resource are not recreated after the resource is deleted. The principal must have the permissions to delete and create the management locks.`
+)
+
+// ManagementLockBehavior returns the docstring for the management_lock_behavior schema attribute.
+func ManagementLockBehavior() string {
+	return addBackquotes(managementLockBehaviorStr)
+}
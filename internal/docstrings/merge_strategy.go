@@ -0,0 +1,14 @@
+package docstrings
+
+const (
+	mergeStrategyStr = `The strategy used to merge the %sbody%s with the existing resource body to compute the request body. Possible values are %sdeep%s, %smerge_patch%s and %sarray_by_key%s. Defaults to %sdeep%s.
+
+  - %sdeep%s: The objects are merged recursively, the arrays in the %sbody%s replace the existing arrays.
+  - %smerge_patch%s: The %sbody%s is applied as a JSON Merge Patch ([RFC 7396](https://www.rfc-editor.org/rfc/rfc7396)), a property whose value is %snull%s will be removed from the existing resource body.
+  - %sarray_by_key%s: Same as %sdeep%s, but the items of the object arrays are matched by the key specified in %sarray_item_keys%s or marked as the identifier in the schema (defaults to %sname%s, then %sid%s). The matched items are merged recursively, the unmatched items in the %sbody%s are appended and the other existing items are kept.`
+)
+
+// MergeStrategy returns the docstring for the merge_strategy schema attribute.
+func MergeStrategy() string {
+	return addBackquotes(mergeStrategyStr)
+}
@@ -0,0 +1,10 @@
+package docstrings
+
+const (
+	sharedLocksStr = `A list of ARM resource IDs which are locked in shared mode when the azapi resources are created/modified/deleted. The resources which hold the same shared lock can run at the same time, but they can't run at the same time with the resources which hold the same ID in %slocks%s. It's useful when the resources only need to prevent the concurrent modifications of their parent resource. If an ID is in both %slocks%s and %sshared_locks%s, it's locked exclusively. The locks are acquired before the operation timeout, otherwise an error is returned.`
+)
+
+// SharedLocks returns the docstring for the shared_locks schema attribute.
+func SharedLocks() string {
+	return addBackquotes(sharedLocksStr)
+}
@@ -5,7 +5,11 @@ type UserFeatures struct {
 	DefaultLocation      string
 	DefaultNaming        string
 	EnablePreflight      bool
+	EnableWhatIf         bool
+	EnablePolicyCheck    bool
 	DisableDefaultOutput bool
+	AutoLockParent       bool
+	AutoLockParentTypes  []string
 }
 
 func Default() UserFeatures {
@@ -14,6 +18,10 @@ func Default() UserFeatures {
 		DefaultLocation:      "",
 		DefaultNaming:        "",
 		EnablePreflight:      false,
+		EnableWhatIf:         false,
+		EnablePolicyCheck:    false,
 		DisableDefaultOutput: false,
+		AutoLockParent:       false,
+		AutoLockParentTypes:  nil,
 	}
 }
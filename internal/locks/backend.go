@@ -0,0 +1,45 @@
+package locks
+
+import (
+	"context"
+	"sync"
+)
+
+const (
+	// BackendMemory is the lock backend which coordinates the locks within the provider process
+	BackendMemory = "memory"
+	// BackendFile is the lock backend which coordinates the locks across the provider processes on the same host with file locks
+	BackendFile = "file"
+)
+
+// Backend is the backend which coordinates the locks
+type Backend interface {
+	// LockWithContext locks the key on behalf of the holder, it's locked in shared mode if shared is true.
+	// It returns an error if the context is done before the lock is acquired.
+	LockWithContext(ctx context.Context, key string, holder string, shared bool) error
+	// UnlockWithHolder unlocks the key which is locked by LockWithContext with the same holder and mode
+	UnlockWithHolder(ctx context.Context, key string, holder string, shared bool)
+}
+
+var (
+	backendLock sync.RWMutex
+	backend     Backend = armMutexKV
+)
+
+// NewMemoryBackend returns the lock backend which coordinates the locks within the provider process
+func NewMemoryBackend() Backend {
+	return armMutexKV
+}
+
+// SetBackend sets the backend which is used to coordinate the locks
+func SetBackend(b Backend) {
+	backendLock.Lock()
+	defer backendLock.Unlock()
+	backend = b
+}
+
+func currentBackend() Backend {
+	backendLock.RLock()
+	defer backendLock.RUnlock()
+	return backend
+}
@@ -0,0 +1,171 @@
+package locks
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// filePollInterval is the interval to retry acquiring the file lock which is held by another process
+const filePollInterval = 200 * time.Millisecond
+
+// errFileLocked is returned by tryLockFile if the file is locked by another process
+var errFileLocked = errors.New("the file is locked by another process")
+
+// fileBackend coordinates the locks across the provider processes on the same host, each key is mapped to a lock file in the directory.
+// The locks are also acquired in the in-memory backend, so the holders in the same process are coordinated and logged the same way.
+type fileBackend struct {
+	dir    string
+	memory *mutexKV
+
+	lock sync.Mutex
+	// files are the opened lock files which hold the file locks, the shared lock file is opened once and shared by the holders in this process
+	files map[string]*lockFile
+}
+
+type lockFile struct {
+	file  *os.File
+	count int
+}
+
+// NewFileBackend returns a lock backend which uses the file locks in the directory, the directory is created if it doesn't exist
+func NewFileBackend(dir string) (Backend, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("the lock directory is not specified")
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("creating the lock directory %q: %+v", dir, err)
+	}
+	return newFileBackend(dir, armMutexKV), nil
+}
+
+func newFileBackend(dir string, memory *mutexKV) *fileBackend {
+	return &fileBackend{
+		dir:    dir,
+		memory: memory,
+		files:  make(map[string]*lockFile),
+	}
+}
+
+// DefaultLockDirectory returns the default directory of the lock files
+func DefaultLockDirectory() string {
+	return filepath.Join(os.TempDir(), "terraform-provider-azapi-locks")
+}
+
+func (b *fileBackend) LockWithContext(ctx context.Context, key string, holder string, shared bool) error {
+	if err := b.memory.LockWithContext(ctx, key, holder, shared); err != nil {
+		return err
+	}
+	if err := b.lockFile(ctx, key, holder, shared); err != nil {
+		b.memory.UnlockWithHolder(ctx, key, holder, shared)
+		return err
+	}
+	return nil
+}
+
+func (b *fileBackend) UnlockWithHolder(ctx context.Context, key string, holder string, shared bool) {
+	b.lock.Lock()
+	if f, ok := b.files[key]; ok {
+		f.count--
+		if f.count <= 0 {
+			delete(b.files, key)
+			if !shared {
+				// clear the recorded holder, the next holder might be a shared holder which isn't recorded
+				_ = f.file.Truncate(0)
+			}
+			if err := unlockFile(f.file); err != nil {
+				tflog.Warn(ctx, fmt.Sprintf("Failed to unlock the lock file of %q: %+v", key, err))
+			}
+			_ = f.file.Close()
+		}
+	}
+	b.lock.Unlock()
+	b.memory.UnlockWithHolder(ctx, key, holder, shared)
+}
+
+// lockFile acquires the file lock of the key, the caller must hold the in-memory lock of the key,
+// so the file lock is only acquired concurrently by the shared holders in this process.
+func (b *fileBackend) lockFile(ctx context.Context, key string, holder string, shared bool) error {
+	b.lock.Lock()
+	if f, ok := b.files[key]; ok && shared {
+		f.count++
+		b.lock.Unlock()
+		return nil
+	}
+	b.lock.Unlock()
+
+	path := b.path(key)
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return fmt.Errorf("opening the lock file %q of %q: %+v", path, key, err)
+	}
+
+	start := time.Now()
+	logged := false
+	for {
+		err := tryLockFile(file, shared)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, errFileLocked) {
+			_ = file.Close()
+			return fmt.Errorf("locking the lock file %q of %q: %+v", path, key, err)
+		}
+		if !logged {
+			tflog.Debug(ctx, fmt.Sprintf("Waiting for the lock file %q of %q in %s mode, it's held by another process: %s", path, key, lockMode(shared), fileHolder(file)))
+			logged = true
+		}
+		select {
+		case <-ctx.Done():
+			holders := fileHolder(file)
+			_ = file.Close()
+			return fmt.Errorf("waiting for the lock file %q of %q in %s mode after %s, it's held by another process: %s: %w", path, key, lockMode(shared), time.Since(start).Round(time.Millisecond), holders, ctx.Err())
+		case <-time.After(filePollInterval):
+		}
+	}
+	tflog.Debug(ctx, fmt.Sprintf("Locked the lock file %q of %q in %s mode after waiting for %s", path, key, lockMode(shared), time.Since(start).Round(time.Millisecond)))
+
+	// the exclusive holder is recorded in the lock file, so the other processes can show who holds the lock
+	if !shared {
+		if err := file.Truncate(0); err == nil {
+			_, _ = file.WriteAt([]byte(fmt.Sprintf("%s (pid %d)", holder, os.Getpid())), 0)
+		}
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if f, ok := b.files[key]; ok && shared {
+		// another shared holder in this process has acquired the file lock in the meantime
+		f.count++
+		_ = unlockFile(file)
+		_ = file.Close()
+		return nil
+	}
+	b.files[key] = &lockFile{file: file, count: 1}
+	return nil
+}
+
+// path returns the path of the lock file, the file name is the hash of the key because the key is usually a resource ID which contains slashes
+func (b *fileBackend) path(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return filepath.Join(b.dir, hex.EncodeToString(hash[:])+".lock")
+}
+
+// fileHolder returns the exclusive holder recorded in the lock file
+func fileHolder(file *os.File) string {
+	data, err := io.ReadAll(io.NewSectionReader(file, 0, 4096))
+	if err != nil || len(strings.TrimSpace(string(data))) == 0 {
+		return "unknown"
+	}
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,17 @@
+//go:build !unix && !windows
+
+package locks
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+)
+
+func tryLockFile(_ *os.File, _ bool) error {
+	return fmt.Errorf("the file lock backend is not supported on %s", runtime.GOOS)
+}
+
+func unlockFile(_ *os.File) error {
+	return nil
+}
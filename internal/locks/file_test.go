@@ -0,0 +1,80 @@
+//go:build unix
+
+package locks
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_FileBackend(t *testing.T) {
+	dir := t.TempDir()
+	key := "/subscriptions/000/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/hub"
+
+	// the backends with different in-memory stores behave like the backends in different processes
+	process1 := newFileBackend(dir, NewMutexKV())
+	process2 := newFileBackend(dir, NewMutexKV())
+
+	if err := process1.LockWithContext(context.Background(), key, "subnet1", false); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	err := process2.LockWithContext(ctx, key, "subnet2", false)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expect deadline exceeded error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "subnet1") {
+		t.Fatalf("expect the error contains the holder, got %v", err)
+	}
+
+	process1.UnlockWithHolder(context.Background(), key, "subnet1", false)
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := process2.LockWithContext(ctx, key, "subnet2", false); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	process2.UnlockWithHolder(context.Background(), key, "subnet2", false)
+}
+
+func Test_FileBackendShared(t *testing.T) {
+	dir := t.TempDir()
+	key := "/subscriptions/000/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/hub"
+
+	process1 := newFileBackend(dir, NewMutexKV())
+	process2 := newFileBackend(dir, NewMutexKV())
+
+	// the shared locks can be held by multiple holders in multiple processes
+	for _, holder := range []string{"a", "b"} {
+		if err := process1.LockWithContext(context.Background(), key, holder, true); err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := process2.LockWithContext(ctx, key, "c", true); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	// the exclusive lock waits for all shared holders
+	process1.UnlockWithHolder(context.Background(), key, "a", true)
+	process2.UnlockWithHolder(context.Background(), key, "c", true)
+	ctx, cancel = context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	if err := process2.LockWithContext(ctx, key, "d", false); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expect deadline exceeded error, got %v", err)
+	}
+
+	process1.UnlockWithHolder(context.Background(), key, "b", true)
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := process2.LockWithContext(ctx, key, "d", false); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	process2.UnlockWithHolder(context.Background(), key, "d", false)
+}
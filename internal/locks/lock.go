@@ -1,12 +1,68 @@
 package locks
 
+import (
+	"context"
+	"slices"
+
+	"github.com/Azure/terraform-provider-azapi/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
 // armMutexKV is the instance of MutexKV for ARM resources
 var armMutexKV = NewMutexKV()
 
 func ByID(id string) {
-	armMutexKV.Lock(id)
+	_, span := tracing.Start(context.Background(), "locks.ByID", id)
+	defer span.End()
+	_ = currentBackend().LockWithContext(context.Background(), id, unknownHolder, false)
 }
 
 func UnlockByID(id string) {
-	armMutexKV.Unlock(id)
+	currentBackend().UnlockWithHolder(context.Background(), id, unknownHolder, false)
+}
+
+// Acquire locks the ids exclusively and the sharedIds in shared mode on behalf of the holder, which is used in the logs to show who holds the locks.
+// The locks are acquired in a stable order to avoid deadlocks, and an id in both lists is locked exclusively.
+// It returns a function to release the acquired locks. If the context is done before all locks are acquired, the acquired locks are released and an error is returned.
+func Acquire(ctx context.Context, holder string, ids []string, sharedIds []string) (func(), error) {
+	modes := make(map[string]bool)
+	for _, id := range sharedIds {
+		modes[id] = true
+	}
+	for _, id := range ids {
+		modes[id] = false
+	}
+	keys := make([]string, 0, len(modes))
+	for id := range modes {
+		keys = append(keys, id)
+	}
+	slices.Sort(keys)
+
+	// the locks are released by the same backend even if the backend is changed in the meantime
+	b := currentBackend()
+	acquired := make([]string, 0, len(keys))
+	release := func() {
+		for i := len(acquired) - 1; i >= 0; i-- {
+			b.UnlockWithHolder(ctx, acquired[i], holder, modes[acquired[i]])
+		}
+	}
+	// the span covers the time of waiting for the locks, it ends once all locks are acquired
+	ctx, span := tracing.Start(ctx, "locks.Acquire", holder, attribute.StringSlice("locks.exclusive", ids), attribute.StringSlice("locks.shared", sharedIds))
+	for _, id := range keys {
+		if err := lockWithSpan(ctx, b, id, holder, modes[id]); err != nil {
+			release()
+			tracing.End(span, err)
+			return nil, err
+		}
+		acquired = append(acquired, id)
+	}
+	span.End()
+	return release, nil
+}
+
+func lockWithSpan(ctx context.Context, b Backend, id string, holder string, shared bool) error {
+	ctx, span := tracing.Start(ctx, "locks.Lock", id, attribute.Bool("locks.shared", shared), attribute.String("locks.holder", holder))
+	err := b.LockWithContext(ctx, id, holder, shared)
+	tracing.End(span, err)
+	return err
 }
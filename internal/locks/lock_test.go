@@ -0,0 +1,139 @@
+package locks
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_AcquireShared(t *testing.T) {
+	id := "/subscriptions/000/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/shared"
+
+	unlock1, err := Acquire(context.Background(), "subnet1", nil, []string{id})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	// the shared locks can be held at the same time
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	unlock2, err := Acquire(ctx, "subnet2", nil, []string{id})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	// the exclusive lock waits for the shared locks
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := Acquire(ctx, "vnet", []string{id}, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expect deadline exceeded error, got %v", err)
+	}
+
+	unlock1()
+	unlock2()
+
+	unlock3, err := Acquire(context.Background(), "vnet", []string{id}, nil)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	unlock3()
+}
+
+func Test_AcquireExclusive(t *testing.T) {
+	id := "/subscriptions/000/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/exclusive"
+
+	unlock, err := Acquire(context.Background(), "vnet", []string{id}, nil)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	// the shared lock waits for the exclusive lock
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := Acquire(ctx, "subnet", nil, []string{id}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expect deadline exceeded error, got %v", err)
+	}
+
+	acquired := make(chan struct{})
+	go func() {
+		unlock, err := Acquire(context.Background(), "subnet", nil, []string{id})
+		if err != nil {
+			t.Errorf("expect no error, got %v", err)
+			return
+		}
+		unlock()
+		close(acquired)
+	}()
+
+	unlock()
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatalf("expect the shared lock is acquired after the exclusive lock is released")
+	}
+}
+
+func Test_AcquireWaitingWriter(t *testing.T) {
+	id := "/subscriptions/000/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/waiting"
+
+	unlock, err := Acquire(context.Background(), "subnet1", nil, []string{id})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	writerDone := make(chan struct{})
+	writerCtx, writerCancel := context.WithCancel(context.Background())
+	go func() {
+		defer close(writerDone)
+		if _, err := Acquire(writerCtx, "vnet", []string{id}, nil); !errors.Is(err, context.Canceled) {
+			t.Errorf("expect canceled error, got %v", err)
+		}
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	// the waiting writer blocks the new readers
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := Acquire(ctx, "subnet2", nil, []string{id}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expect deadline exceeded error, got %v", err)
+	}
+
+	// the readers can continue once the writer stops waiting
+	writerCancel()
+	<-writerDone
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	unlock2, err := Acquire(ctx, "subnet2", nil, []string{id})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	unlock2()
+	unlock()
+}
+
+func Test_AcquireReleasesOnError(t *testing.T) {
+	first := "/subscriptions/000/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/a"
+	second := "/subscriptions/000/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/b"
+
+	unlock, err := Acquire(context.Background(), "holder", []string{second}, nil)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := Acquire(ctx, "waiter", []string{first, second}, nil); err == nil {
+		t.Fatalf("expect an error")
+	}
+
+	// the first lock is released when the second lock can't be acquired
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	unlockFirst, err := Acquire(ctx, "other", []string{first}, nil)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	unlockFirst()
+	unlock()
+}
@@ -0,0 +1,260 @@
+package locks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/terraform-provider-azapi/internal/clients"
+	"github.com/Azure/terraform-provider-azapi/utils"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// ManagementLockBehaviorNone means the management locks are not changed, the operations fail if they're blocked by the locks
+	ManagementLockBehaviorNone = "none"
+	// ManagementLockBehaviorLiftAndRestore means the blocking management locks are deleted before the operation and recreated after it
+	ManagementLockBehaviorLiftAndRestore = "lift_and_restore"
+
+	managementLockApiVersion = "2020-05-01"
+	managementLockProvider   = "/providers/Microsoft.Authorization/locks/"
+
+	managementLockLevelCanNotDelete = "CanNotDelete"
+	managementLockLevelReadOnly     = "ReadOnly"
+
+	// managementLockRestoreTimeout is the timeout to restore the management locks, it's not bound to the operation's context,
+	// so the locks are restored even if the operation is timed out or cancelled.
+	managementLockRestoreTimeout = 5 * time.Minute
+)
+
+// ManagementLock is an ARM management lock, the `Microsoft.Authorization/locks` resource
+type ManagementLock struct {
+	ID    string
+	Level string
+	Notes string
+	// Owners are kept as is, so they can be restored without losing any fields
+	Owners interface{}
+}
+
+// liftedLock is a management lock which is lifted by the holders in this process
+type liftedLock struct {
+	lock  ManagementLock
+	count int
+}
+
+// liftedManagementLocks are the management locks which are lifted in this process, keyed by the lower case lock ID.
+// A lock is deleted by the first holder and recreated by the last holder, so the concurrent operations under the same lock don't race.
+var liftedManagementLocks = struct {
+	sync.Mutex
+	locks map[string]*liftedLock
+}{
+	locks: make(map[string]*liftedLock),
+}
+
+// LiftManagementLocks deletes the management locks at and above the scope of the resource which block the operation,
+// the CanNotDelete and ReadOnly locks block the deletion and the ReadOnly locks block the update.
+// It returns a function to recreate the lifted locks with the same level, notes and owners, which must be called even if the operation fails.
+// The keep function passed to the restore function can be used to skip restoring a lock, for example, the lock at the scope of a deleted resource.
+func LiftManagementLocks(ctx context.Context, client clients.Requester, holder string, resourceId string, deleting bool) (func(keep func(ManagementLock) bool) error, error) {
+	// the locks which are lifted by the other holders in this process are not returned by the API, but they're still blocking,
+	// they're collected before and after listing, so the locks which are lifted or restored in the meantime are not missed.
+	candidates := liftedManagementLocksInScope(resourceId)
+	existing, err := ListManagementLocks(ctx, client, resourceId)
+	if err != nil {
+		return nil, fmt.Errorf("listing the management locks of %s: %+v", resourceId, err)
+	}
+	for key, lock := range liftedManagementLocksInScope(resourceId) {
+		candidates[key] = lock
+	}
+	for _, lock := range existing {
+		candidates[strings.ToLower(lock.ID)] = lock
+	}
+
+	blocking := make([]ManagementLock, 0)
+	for _, lock := range candidates {
+		if strings.EqualFold(lock.Level, managementLockLevelReadOnly) || (deleting && strings.EqualFold(lock.Level, managementLockLevelCanNotDelete)) {
+			blocking = append(blocking, lock)
+		}
+	}
+
+	lifted := make([]ManagementLock, 0, len(blocking))
+	restore := func(keep func(ManagementLock) bool) error {
+		restoreCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), managementLockRestoreTimeout)
+		defer cancel()
+		errs := make([]string, 0)
+		for _, lock := range lifted {
+			if err := releaseManagementLock(restoreCtx, client, holder, lock, keep == nil || keep(lock)); err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+		if len(errs) != 0 {
+			return fmt.Errorf("restoring the management locks: %s", strings.Join(errs, "; "))
+		}
+		return nil
+	}
+	for _, lock := range blocking {
+		if err := liftManagementLock(ctx, client, holder, lock); err != nil {
+			if restoreErr := restore(nil); restoreErr != nil {
+				return nil, fmt.Errorf("%+v, %+v", err, restoreErr)
+			}
+			return nil, err
+		}
+		lifted = append(lifted, lock)
+	}
+	return restore, nil
+}
+
+// ListManagementLocks lists the management locks at and above the scope of the resource
+func ListManagementLocks(ctx context.Context, client clients.Requester, resourceId string) ([]ManagementLock, error) {
+	options := clients.DefaultRequestOptions()
+	options.QueryParameters["$filter"] = "atScope()"
+	responseBody, err := client.List(ctx, strings.TrimSuffix(resourceId, "/")+"/providers/Microsoft.Authorization/locks", managementLockApiVersion, options)
+	if err != nil {
+		return nil, err
+	}
+	responseMap, ok := responseBody.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected response: %v", responseBody)
+	}
+	values, _ := responseMap["value"].([]interface{})
+	out := make([]ManagementLock, 0, len(values))
+	for _, value := range values {
+		if lock, ok := expandManagementLock(value); ok {
+			out = append(out, lock)
+		}
+	}
+	return out, nil
+}
+
+// liftManagementLock deletes the management lock if it's not lifted by another holder in this process
+func liftManagementLock(ctx context.Context, client clients.Requester, holder string, lock ManagementLock) error {
+	unlock, err := Acquire(ctx, holder, []string{strings.ToLower(lock.ID)}, nil)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	key := strings.ToLower(lock.ID)
+	liftedManagementLocks.Lock()
+	if lifted, ok := liftedManagementLocks.locks[key]; ok {
+		lifted.count++
+		liftedManagementLocks.Unlock()
+		return nil
+	}
+	liftedManagementLocks.Unlock()
+
+	tflog.Info(ctx, fmt.Sprintf("Lifting the %s management lock %s", lock.Level, lock.ID))
+	if _, err := client.Delete(ctx, lock.ID, managementLockApiVersion, clients.DefaultRequestOptions()); err != nil {
+		if utils.ResponseErrorWasNotFound(err) {
+			// the lock has been deleted by someone else, it's not restored
+			return nil
+		}
+		return fmt.Errorf("deleting the management lock %s: %+v", lock.ID, err)
+	}
+
+	liftedManagementLocks.Lock()
+	liftedManagementLocks.locks[key] = &liftedLock{lock: lock, count: 1}
+	liftedManagementLocks.Unlock()
+	return nil
+}
+
+// releaseManagementLock recreates the management lock if it's released by the last holder in this process.
+// If restore is false and it's released by the last holder, the lock is not recreated.
+func releaseManagementLock(ctx context.Context, client clients.Requester, holder string, lock ManagementLock, restore bool) error {
+	unlock, err := Acquire(ctx, holder, []string{strings.ToLower(lock.ID)}, nil)
+	if err != nil {
+		return fmt.Errorf("restoring the management lock %s: %+v", lock.ID, err)
+	}
+	defer unlock()
+
+	key := strings.ToLower(lock.ID)
+	liftedManagementLocks.Lock()
+	lifted, ok := liftedManagementLocks.locks[key]
+	if !ok {
+		liftedManagementLocks.Unlock()
+		return nil
+	}
+	lifted.count--
+	if lifted.count > 0 {
+		liftedManagementLocks.Unlock()
+		return nil
+	}
+	delete(liftedManagementLocks.locks, key)
+	liftedManagementLocks.Unlock()
+
+	if !restore {
+		tflog.Info(ctx, fmt.Sprintf("Skipped restoring the %s management lock %s", lock.Level, lock.ID))
+		return nil
+	}
+
+	tflog.Info(ctx, fmt.Sprintf("Restoring the %s management lock %s", lock.Level, lock.ID))
+	properties := map[string]interface{}{
+		"level": lock.Level,
+	}
+	if lock.Notes != "" {
+		properties["notes"] = lock.Notes
+	}
+	if lock.Owners != nil {
+		properties["owners"] = lock.Owners
+	}
+	body := map[string]interface{}{
+		"properties": properties,
+	}
+	if _, err := client.CreateOrUpdate(ctx, lock.ID, managementLockApiVersion, body, clients.DefaultRequestOptions()); err != nil {
+		return fmt.Errorf("recreating the %s management lock %s with notes %q: %+v", lock.Level, lock.ID, lock.Notes, err)
+	}
+	return nil
+}
+
+// liftedManagementLocksInScope returns the management locks which are lifted in this process and apply to the resource
+func liftedManagementLocksInScope(resourceId string) map[string]ManagementLock {
+	out := make(map[string]ManagementLock)
+	liftedManagementLocks.Lock()
+	defer liftedManagementLocks.Unlock()
+	for key, lifted := range liftedManagementLocks.locks {
+		if isManagementLockInScope(lifted.lock.ID, resourceId) {
+			out[key] = lifted.lock
+		}
+	}
+	return out
+}
+
+// ManagementLockScope returns the scope of the management lock
+func ManagementLockScope(lockId string) string {
+	index := strings.LastIndex(strings.ToLower(lockId), strings.ToLower(managementLockProvider))
+	if index == -1 {
+		return ""
+	}
+	return lockId[:index]
+}
+
+// isManagementLockInScope checks if the management lock applies to the resource, which means it's at or above the scope of the resource
+func isManagementLockInScope(lockId string, resourceId string) bool {
+	scope := ManagementLockScope(lockId)
+	if scope == "" {
+		return false
+	}
+	return strings.HasPrefix(strings.ToLower(resourceId)+"/", strings.ToLower(scope)+"/")
+}
+
+func expandManagementLock(input interface{}) (ManagementLock, bool) {
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return ManagementLock{}, false
+	}
+	id, _ := inputMap["id"].(string)
+	if id == "" {
+		return ManagementLock{}, false
+	}
+	properties, _ := inputMap["properties"].(map[string]interface{})
+	level, _ := properties["level"].(string)
+	notes, _ := properties["notes"].(string)
+	return ManagementLock{
+		ID:     id,
+		Level:  level,
+		Notes:  notes,
+		Owners: properties["owners"],
+	}, true
+}
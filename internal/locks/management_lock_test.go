@@ -0,0 +1,191 @@
+package locks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Azure/terraform-provider-azapi/internal/clients"
+)
+
+type fakeManagementLockClient struct {
+	clients.Requester
+
+	mu      sync.Mutex
+	locks   map[string]map[string]interface{}
+	deleted []string
+	created []string
+	// failCreate makes the creation of the locks fail
+	failCreate bool
+}
+
+func newFakeManagementLockClient(locks ...map[string]interface{}) *fakeManagementLockClient {
+	client := &fakeManagementLockClient{
+		locks: make(map[string]map[string]interface{}),
+	}
+	for _, lock := range locks {
+		client.locks[strings.ToLower(lock["id"].(string))] = lock
+	}
+	return client
+}
+
+func (c *fakeManagementLockClient) List(_ context.Context, url string, _ string, _ clients.RequestOptions) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	resourceId := strings.TrimSuffix(url, "/providers/Microsoft.Authorization/locks")
+	values := make([]interface{}, 0)
+	for _, lock := range c.locks {
+		if isManagementLockInScope(lock["id"].(string), resourceId) {
+			values = append(values, lock)
+		}
+	}
+	return map[string]interface{}{"value": values}, nil
+}
+
+func (c *fakeManagementLockClient) Delete(_ context.Context, resourceID string, _ string, _ clients.RequestOptions) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.locks, strings.ToLower(resourceID))
+	c.deleted = append(c.deleted, resourceID)
+	return nil, nil
+}
+
+func (c *fakeManagementLockClient) CreateOrUpdate(_ context.Context, resourceID string, _ string, body interface{}, _ clients.RequestOptions) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.failCreate {
+		return nil, fmt.Errorf("creating %s failed", resourceID)
+	}
+	lock := map[string]interface{}{"id": resourceID}
+	for k, v := range body.(map[string]interface{}) {
+		lock[k] = v
+	}
+	c.locks[strings.ToLower(resourceID)] = lock
+	c.created = append(c.created, resourceID)
+	return nil, nil
+}
+
+func managementLock(scope string, name string, level string, notes string) map[string]interface{} {
+	return map[string]interface{}{
+		"id": scope + "/providers/Microsoft.Authorization/locks/" + name,
+		"properties": map[string]interface{}{
+			"level": level,
+			"notes": notes,
+		},
+	}
+}
+
+func Test_LiftManagementLocks(t *testing.T) {
+	rg := "/subscriptions/000/resourceGroups/lift"
+	resourceId := rg + "/providers/Microsoft.Automation/automationAccounts/account"
+	readOnly := managementLock(rg, "readonly", "ReadOnly", "do not change")
+	canNotDelete := managementLock(resourceId, "cannotdelete", "CanNotDelete", "do not delete")
+	other := managementLock(rg+"/providers/Microsoft.Automation/automationAccounts/other", "other", "ReadOnly", "")
+
+	t.Run("update", func(t *testing.T) {
+		client := newFakeManagementLockClient(readOnly, canNotDelete, other)
+		restore, err := LiftManagementLocks(context.Background(), client, "test", resourceId, false)
+		if err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+		// only the ReadOnly lock above the resource blocks the update
+		if len(client.deleted) != 1 || client.deleted[0] != readOnly["id"] {
+			t.Fatalf("expect %v to be deleted, got %v", readOnly["id"], client.deleted)
+		}
+		if err := restore(nil); err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+		if len(client.created) != 1 || client.created[0] != readOnly["id"] {
+			t.Fatalf("expect %v to be restored, got %v", readOnly["id"], client.created)
+		}
+		restored := client.locks[strings.ToLower(readOnly["id"].(string))]["properties"].(map[string]interface{})
+		if restored["level"] != "ReadOnly" || restored["notes"] != "do not change" {
+			t.Fatalf("expect the lock to be restored with the same level and notes, got %v", restored)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		client := newFakeManagementLockClient(readOnly, canNotDelete, other)
+		restore, err := LiftManagementLocks(context.Background(), client, "test", resourceId, true)
+		if err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+		if len(client.deleted) != 2 {
+			t.Fatalf("expect 2 locks to be deleted, got %v", client.deleted)
+		}
+		// the lock at the scope of the deleted resource is not restored
+		err = restore(func(lock ManagementLock) bool {
+			return !strings.EqualFold(ManagementLockScope(lock.ID), resourceId)
+		})
+		if err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+		if len(client.created) != 1 || client.created[0] != readOnly["id"] {
+			t.Fatalf("expect %v to be restored, got %v", readOnly["id"], client.created)
+		}
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		client := newFakeManagementLockClient(readOnly)
+		restore1, err := LiftManagementLocks(context.Background(), client, "first", resourceId, false)
+		if err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+		// the lock which is lifted by another holder is not returned by the API, but it's shared
+		restore2, err := LiftManagementLocks(context.Background(), client, "second", rg+"/providers/Microsoft.Automation/automationAccounts/second", false)
+		if err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+		if err := restore1(nil); err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+		if len(client.created) != 0 {
+			t.Fatalf("expect the lock not to be restored while it's lifted by another holder, got %v", client.created)
+		}
+		if err := restore2(nil); err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+		if len(client.deleted) != 1 || len(client.created) != 1 {
+			t.Fatalf("expect the lock to be deleted and restored once, got deleted %v, created %v", client.deleted, client.created)
+		}
+	})
+
+	t.Run("restore error", func(t *testing.T) {
+		client := newFakeManagementLockClient(readOnly)
+		client.failCreate = true
+		restore, err := LiftManagementLocks(context.Background(), client, "test", resourceId, false)
+		if err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+		if err := restore(nil); err == nil || !strings.Contains(err.Error(), "do not change") {
+			t.Fatalf("expect an error which contains the notes of the lock, got %v", err)
+		}
+	})
+}
+
+func Test_ManagementLockScope(t *testing.T) {
+	testcases := []struct {
+		lockId   string
+		expected string
+	}{
+		{
+			lockId:   "/subscriptions/000/providers/Microsoft.Authorization/locks/lock",
+			expected: "/subscriptions/000",
+		},
+		{
+			lockId:   "/subscriptions/000/resourceGroups/rg/providers/microsoft.authorization/locks/lock",
+			expected: "/subscriptions/000/resourceGroups/rg",
+		},
+		{
+			lockId:   "/subscriptions/000/resourceGroups/rg",
+			expected: "",
+		},
+	}
+	for _, tc := range testcases {
+		if actual := ManagementLockScope(tc.lockId); actual != tc.expected {
+			t.Errorf("expect %q for %q, got %q", tc.expected, tc.lockId, actual)
+		}
+	}
+}
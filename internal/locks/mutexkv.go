@@ -1,48 +1,168 @@
 package locks
 
 import (
-	"log"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
 	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
-// mutexKV is a simple key/value store for arbitrary mutexes. It can be used to
+// unknownHolder is the holder of the locks which are acquired without specifying the holder
+const unknownHolder = "unknown"
+
+// mutexKV is a simple key/value store for arbitrary read/write mutexes. It can be used to
 // serialize changes across arbitrary collaborators that share knowledge of the
 // keys they must serialize on.
 type mutexKV struct {
 	lock  sync.Mutex
-	store map[string]*sync.Mutex
+	store map[string]*rwMutex
+}
+
+// rwMutex is a read/write mutex which records its holders and supports cancellation.
+// Like sync.RWMutex, a blocked writer prevents new readers from acquiring the lock, so the writers won't be starved.
+type rwMutex struct {
+	writer         string
+	readers        map[string]int
+	waitingWriters int
+	// released is closed and replaced when the mutex is released, it wakes up the waiting callers
+	released chan struct{}
 }
 
 // Locks the mutex for the given key. Caller is responsible for calling Unlock
 // for the same key
 func (m *mutexKV) Lock(key string) {
-	log.Printf("[DEBUG] Locking %q", key)
-	m.get(key).Lock()
-	log.Printf("[DEBUG] Locked %q", key)
+	_ = m.LockWithContext(context.Background(), key, unknownHolder, false)
 }
 
 // Unlock the mutex for the given key. Caller must have called Lock for the same key first
 func (m *mutexKV) Unlock(key string) {
-	log.Printf("[DEBUG] Unlocking %q", key)
-	m.get(key).Unlock()
-	log.Printf("[DEBUG] Unlocked %q", key)
+	m.UnlockWithHolder(context.Background(), key, unknownHolder, false)
+}
+
+// LockWithContext locks the mutex for the given key on behalf of the holder, it's locked in shared mode if shared is true.
+// It returns an error if the context is done before the mutex is acquired.
+func (m *mutexKV) LockWithContext(ctx context.Context, key string, holder string, shared bool) error {
+	if holder == "" {
+		holder = unknownHolder
+	}
+	mode := lockMode(shared)
+	start := time.Now()
+	tflog.Debug(ctx, fmt.Sprintf("Locking %q in %s mode", key, mode))
+
+	m.lock.Lock()
+	mutex := m.get(key)
+	if !shared {
+		mutex.waitingWriters++
+	}
+	for !mutex.available(shared) {
+		tflog.Debug(ctx, fmt.Sprintf("Waiting for %q in %s mode, it's held by %s", key, mode, mutex.holders()))
+		released := mutex.released
+		m.lock.Unlock()
+
+		select {
+		case <-released:
+			m.lock.Lock()
+		case <-ctx.Done():
+			m.lock.Lock()
+			holders := mutex.holders()
+			if !shared {
+				mutex.waitingWriters--
+				// the readers which are blocked by this writer can continue
+				mutex.notify()
+			}
+			m.lock.Unlock()
+			return fmt.Errorf("waiting for the lock %q in %s mode after %s, it's held by %s: %w", key, mode, time.Since(start).Round(time.Millisecond), holders, ctx.Err())
+		}
+	}
+	if shared {
+		mutex.readers[holder]++
+	} else {
+		mutex.waitingWriters--
+		mutex.writer = holder
+	}
+	m.lock.Unlock()
+
+	tflog.Debug(ctx, fmt.Sprintf("Locked %q in %s mode after waiting for %s", key, mode, time.Since(start).Round(time.Millisecond)))
+	return nil
 }
 
-// Returns a mutex for the given key, no guarantee of its lock status
-func (m *mutexKV) get(key string) *sync.Mutex {
+// UnlockWithHolder unlocks the mutex for the given key which is locked by LockWithContext with the same holder and mode
+func (m *mutexKV) UnlockWithHolder(ctx context.Context, key string, holder string, shared bool) {
+	if holder == "" {
+		holder = unknownHolder
+	}
+	tflog.Debug(ctx, fmt.Sprintf("Unlocking %q in %s mode", key, lockMode(shared)))
 	m.lock.Lock()
-	defer m.lock.Unlock()
+	mutex := m.get(key)
+	if shared {
+		if mutex.readers[holder] <= 1 {
+			delete(mutex.readers, holder)
+		} else {
+			mutex.readers[holder]--
+		}
+	} else {
+		mutex.writer = ""
+	}
+	mutex.notify()
+	m.lock.Unlock()
+	tflog.Debug(ctx, fmt.Sprintf("Unlocked %q in %s mode", key, lockMode(shared)))
+}
+
+// Returns a mutex for the given key, no guarantee of its lock status. Caller must hold the lock of the store.
+func (m *mutexKV) get(key string) *rwMutex {
 	mutex, ok := m.store[key]
 	if !ok {
-		mutex = &sync.Mutex{}
+		mutex = &rwMutex{
+			readers:  make(map[string]int),
+			released: make(chan struct{}),
+		}
 		m.store[key] = mutex
 	}
 	return mutex
 }
 
+func (r *rwMutex) available(shared bool) bool {
+	if r.writer != "" || (!shared && len(r.readers) != 0) {
+		return false
+	}
+	// the waiting writers take precedence over the new readers
+	return !shared || r.waitingWriters == 0
+}
+
+func (r *rwMutex) notify() {
+	close(r.released)
+	r.released = make(chan struct{})
+}
+
+func (r *rwMutex) holders() string {
+	holders := make([]string, 0)
+	if r.writer != "" {
+		holders = append(holders, fmt.Sprintf("%s (exclusive)", r.writer))
+	}
+	for holder := range r.readers {
+		holders = append(holders, fmt.Sprintf("%s (shared)", holder))
+	}
+	if len(holders) == 0 {
+		return "no one"
+	}
+	sort.Strings(holders)
+	return strings.Join(holders, ", ")
+}
+
+func lockMode(shared bool) string {
+	if shared {
+		return "shared"
+	}
+	return "exclusive"
+}
+
 // Returns a properly initialized mutexKV
 func NewMutexKV() *mutexKV {
 	return &mutexKV{
-		store: make(map[string]*sync.Mutex),
+		store: make(map[string]*rwMutex),
 	}
 }
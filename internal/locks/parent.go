@@ -0,0 +1,44 @@
+package locks
+
+import "strings"
+
+// parentLockTypes are the child resource types which can't be created/modified/deleted at the same time with the other child resources of the same parent resource,
+// the resource provider returns errors like `AnotherOperationInProgress` or `RetryableError` when it happens. The keys are in lower case.
+var parentLockTypes = map[string]bool{
+	"microsoft.network/applicationgatewaywebapplicationfirewallpolicies/customrules": true,
+	"microsoft.network/expressroutecircuits/authorizations":                          true,
+	"microsoft.network/expressroutecircuits/peerings":                                true,
+	"microsoft.network/expressroutegateways/expressrouteconnections":                 true,
+	"microsoft.network/firewallpolicies/rulecollectiongroups":                        true,
+	"microsoft.network/loadbalancers/backendaddresspools":                            true,
+	"microsoft.network/loadbalancers/inboundnatrules":                                true,
+	"microsoft.network/networksecuritygroups/securityrules":                          true,
+	"microsoft.network/routefilters/routefilterrules":                                true,
+	"microsoft.network/routetables/routes":                                           true,
+	"microsoft.network/virtualhubs/bgpconnections":                                   true,
+	"microsoft.network/virtualhubs/hubroutetables":                                   true,
+	"microsoft.network/virtualhubs/hubvirtualnetworkconnections":                     true,
+	"microsoft.network/virtualhubs/ipconfigurations":                                 true,
+	"microsoft.network/virtualhubs/routemaps":                                        true,
+	"microsoft.network/virtualhubs/routingintent":                                    true,
+	"microsoft.network/virtualnetworkgateways/natrules":                              true,
+	"microsoft.network/virtualnetworks/subnets":                                      true,
+	"microsoft.network/virtualnetworks/virtualnetworkpeerings":                       true,
+	"microsoft.network/vpngateways/natrules":                                         true,
+	"microsoft.network/vpngateways/vpnconnections":                                   true,
+	"microsoft.network/vpnsites/vpnsitelinks":                                        true,
+}
+
+// NeedsParentLock checks if the parent resource should be locked when the resource of the given type is created/modified/deleted.
+// The resource type is either in the maintained list of the known types, or in the additional types specified by the user.
+func NeedsParentLock(resourceType string, additionalTypes []string) bool {
+	if parentLockTypes[strings.ToLower(resourceType)] {
+		return true
+	}
+	for _, t := range additionalTypes {
+		if strings.EqualFold(t, resourceType) {
+			return true
+		}
+	}
+	return false
+}
@@ -12,6 +12,19 @@ const (
 	Synapse          cloud.ServiceName = "Synapse"
 )
 
+// dataPlaneServiceNames returns the names of the data plane services whose endpoint and audience can be overridden.
+func dataPlaneServiceNames() []string {
+	return []string{
+		string(AppConfiguration),
+		string(DeviceUpdate),
+		string(DigitalTwins),
+		string(IoTCentral),
+		string(KeyVault),
+		string(Purview),
+		string(Synapse),
+	}
+}
+
 func init() {
 	cloud.AzurePublic.Services[AppConfiguration] = cloud.ServiceConfiguration{
 		Audience: "https://azconfig.io",
@@ -41,4 +54,48 @@ func init() {
 		Audience: "https://dev.azuresynapse.net",
 		Endpoint: "https://dev.azuresynapse.net",
 	}
+
+	// Device Update and IoT Central are not available in the sovereign clouds, the DataPlaneClient returns an error for their requests
+	// instead of authenticating them with the ResourceManager audience
+	cloud.AzureGovernment.Services[AppConfiguration] = cloud.ServiceConfiguration{
+		Audience: "https://azconfig.azure.us",
+		Endpoint: "https://azconfig.azure.us",
+	}
+	cloud.AzureGovernment.Services[DigitalTwins] = cloud.ServiceConfiguration{
+		Audience: "https://digitaltwins.azure.us",
+		Endpoint: "https://digitaltwins.azure.us",
+	}
+	cloud.AzureGovernment.Services[KeyVault] = cloud.ServiceConfiguration{
+		Audience: "https://vault.usgovcloudapi.net",
+		Endpoint: "https://vault.usgovcloudapi.net",
+	}
+	cloud.AzureGovernment.Services[Purview] = cloud.ServiceConfiguration{
+		Audience: "https://purview.azure.us",
+		Endpoint: "https://purview.azure.us",
+	}
+	cloud.AzureGovernment.Services[Synapse] = cloud.ServiceConfiguration{
+		Audience: "https://dev.azuresynapse.usgovcloudapi.net",
+		Endpoint: "https://dev.azuresynapse.usgovcloudapi.net",
+	}
+
+	cloud.AzureChina.Services[AppConfiguration] = cloud.ServiceConfiguration{
+		Audience: "https://azconfig.azure.cn",
+		Endpoint: "https://azconfig.azure.cn",
+	}
+	cloud.AzureChina.Services[DigitalTwins] = cloud.ServiceConfiguration{
+		Audience: "https://digitaltwins.azure.cn",
+		Endpoint: "https://digitaltwins.azure.cn",
+	}
+	cloud.AzureChina.Services[KeyVault] = cloud.ServiceConfiguration{
+		Audience: "https://vault.azure.cn",
+		Endpoint: "https://vault.azure.cn",
+	}
+	cloud.AzureChina.Services[Purview] = cloud.ServiceConfiguration{
+		Audience: "https://purview.azure.cn",
+		Endpoint: "https://purview.azure.cn",
+	}
+	cloud.AzureChina.Services[Synapse] = cloud.ServiceConfiguration{
+		Audience: "https://dev.azuresynapse.azure.cn",
+		Endpoint: "https://dev.azuresynapse.azure.cn",
+	}
 }
@@ -0,0 +1,41 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/terraform-provider-azapi/internal/clients"
+	"github.com/Azure/terraform-provider-azapi/internal/services/parse"
+)
+
+type fakeTokenCredential struct{}
+
+func (fakeTokenCredential) GetToken(_ context.Context, _ policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{Token: "token", ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+func Test_DataPlaneServiceNotAvailable(t *testing.T) {
+	for _, configuration := range []cloud.Configuration{cloud.AzureGovernment, cloud.AzureChina} {
+		client, err := clients.NewDataPlaneClient(fakeTokenCredential{}, &arm.ClientOptions{
+			ClientOptions: policy.ClientOptions{Cloud: configuration},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, id := range []string{
+			"myapp.azureiotcentral.com/api/users/user",
+			"myaccount.api.adu.microsoft.com/deviceupdate/instance/management/groups/group",
+		} {
+			_, err = client.Get(context.Background(), parse.DataPlaneResourceId{AzureResourceId: id, ApiVersion: "2022-07-31"}, clients.DefaultRequestOptions())
+			if err == nil || !strings.Contains(err.Error(), "is not available in this cloud") {
+				t.Errorf("expect the service of %q is not available in %s, got %v", id, configuration.ActiveDirectoryAuthorityHost, err)
+			}
+		}
+	}
+}
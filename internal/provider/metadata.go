@@ -0,0 +1,150 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+)
+
+const metadataApiVersion = "2022-09-01"
+
+// metadataTimeout is the timeout of retrieving the metadata, so an unreachable metadata host doesn't block the provider configuration
+var metadataTimeout = 30 * time.Second
+
+// metadataEnvironment is the cloud environment returned by the ARM metadata endpoint `/metadata/endpoints`.
+type metadataEnvironment struct {
+	Name            string `json:"name"`
+	ResourceManager string `json:"resourceManager"`
+	Authentication  struct {
+		LoginEndpoint string   `json:"loginEndpoint"`
+		Audiences     []string `json:"audiences"`
+	} `json:"authentication"`
+	Suffixes struct {
+		KeyVaultDns      string `json:"keyVaultDns"`
+		SynapseAnalytics string `json:"synapseAnalytics"`
+	} `json:"suffixes"`
+	SynapseAnalyticsResourceId string `json:"synapseAnalyticsResourceId"`
+}
+
+// cloudConfigurationFromMetadataHost builds the cloud configuration from the metadata returned by the metadata host.
+func cloudConfigurationFromMetadataHost(ctx context.Context, client *http.Client, metadataHost string) (cloud.Configuration, error) {
+	ctx, cancel := context.WithTimeout(ctx, metadataTimeout)
+	defer cancel()
+	metadataUrl := fmt.Sprintf("https://%s/metadata/endpoints?api-version=%s", metadataHost, metadataApiVersion)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, metadataUrl, nil)
+	if err != nil {
+		return cloud.Configuration{}, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return cloud.Configuration{}, fmt.Errorf("retrieving metadata from %q: %+v", metadataUrl, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return cloud.Configuration{}, fmt.Errorf("reading metadata from %q: %+v", metadataUrl, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return cloud.Configuration{}, fmt.Errorf("retrieving metadata from %q: unexpected status code %d: %s", metadataUrl, resp.StatusCode, string(data))
+	}
+	return cloudConfigurationFromMetadata(data, metadataHost)
+}
+
+// cloudConfigurationFromMetadataFile builds the cloud configuration from a local copy of the metadata.
+func cloudConfigurationFromMetadataFile(path string, metadataHost string) (cloud.Configuration, error) {
+	// #nosec G304
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cloud.Configuration{}, fmt.Errorf("reading metadata from file %q: %v", path, err)
+	}
+	return cloudConfigurationFromMetadata(data, metadataHost)
+}
+
+// cloudConfigurationFromMetadata builds the cloud configuration from the metadata, which is either a single environment or a list of environments.
+// If there are multiple environments, the one whose resource manager endpoint matches the metadata host is used, otherwise the first one.
+func cloudConfigurationFromMetadata(data []byte, metadataHost string) (cloud.Configuration, error) {
+	environments := make([]metadataEnvironment, 0)
+	if strings.HasPrefix(strings.TrimSpace(string(data)), "[") {
+		if err := json.Unmarshal(data, &environments); err != nil {
+			return cloud.Configuration{}, fmt.Errorf("unmarshalling metadata: %+v", err)
+		}
+	} else {
+		var environment metadataEnvironment
+		if err := json.Unmarshal(data, &environment); err != nil {
+			return cloud.Configuration{}, fmt.Errorf("unmarshalling metadata: %+v", err)
+		}
+		environments = append(environments, environment)
+	}
+	if len(environments) == 0 {
+		return cloud.Configuration{}, fmt.Errorf("no environment is found in the metadata")
+	}
+
+	environment := environments[0]
+	for _, v := range environments {
+		if u, err := url.Parse(v.ResourceManager); err == nil && metadataHost != "" && strings.EqualFold(u.Host, metadataHost) {
+			environment = v
+			break
+		}
+	}
+
+	// the metadata of Azure Stack Hub doesn't contain the resource manager endpoint, which is the metadata host itself
+	if environment.ResourceManager == "" && metadataHost != "" {
+		environment.ResourceManager = fmt.Sprintf("https://%s/", metadataHost)
+	}
+	if environment.ResourceManager == "" {
+		return cloud.Configuration{}, fmt.Errorf("the resource manager endpoint is not found in the metadata")
+	}
+	if environment.Authentication.LoginEndpoint == "" {
+		return cloud.Configuration{}, fmt.Errorf("the login endpoint is not found in the metadata")
+	}
+	if len(environment.Authentication.Audiences) == 0 {
+		return cloud.Configuration{}, fmt.Errorf("the resource manager audience is not found in the metadata")
+	}
+
+	config := cloud.Configuration{
+		ActiveDirectoryAuthorityHost: strings.TrimSuffix(environment.Authentication.LoginEndpoint, "/") + "/",
+		Services: map[cloud.ServiceName]cloud.ServiceConfiguration{
+			cloud.ResourceManager: {
+				Audience: environment.Authentication.Audiences[0],
+				Endpoint: environment.ResourceManager,
+			},
+		},
+	}
+	if v := environment.Suffixes.KeyVaultDns; v != "" {
+		config.Services[KeyVault] = cloud.ServiceConfiguration{
+			Audience: fmt.Sprintf("https://%s", v),
+			Endpoint: fmt.Sprintf("https://%s", v),
+		}
+	}
+	if v := environment.Suffixes.SynapseAnalytics; v != "" {
+		audience := strings.TrimSuffix(environment.SynapseAnalyticsResourceId, "/")
+		if audience == "" {
+			audience = fmt.Sprintf("https://%s", v)
+		}
+		config.Services[Synapse] = cloud.ServiceConfiguration{
+			Audience: audience,
+			Endpoint: fmt.Sprintf("https://%s", v),
+		}
+	}
+	return config, nil
+}
+
+// missingDataPlaneServices returns the data plane services whose endpoints are not provided by the metadata or the `endpoint` block,
+// the metadata only contains the endpoints of Key Vault and Synapse.
+func missingDataPlaneServices(config cloud.Configuration) []string {
+	res := make([]string, 0)
+	for _, name := range dataPlaneServiceNames() {
+		if config.Services[cloud.ServiceName(name)].Endpoint == "" {
+			res = append(res, name)
+		}
+	}
+	return res
+}
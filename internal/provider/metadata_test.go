@@ -0,0 +1,234 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+)
+
+const testMetadata = `[
+  {
+    "name": "AzureCloud",
+    "resourceManager": "https://management.azure.com/",
+    "authentication": {
+      "loginEndpoint": "https://login.microsoftonline.com",
+      "audiences": [
+        "https://management.core.windows.net/",
+        "https://management.azure.com/"
+      ],
+      "tenant": "common",
+      "identityProvider": "AAD"
+    },
+    "suffixes": {
+      "keyVaultDns": "vault.azure.net",
+      "synapseAnalytics": "dev.azuresynapse.net"
+    },
+    "synapseAnalyticsResourceId": "https://dev.azuresynapse.net"
+  },
+  {
+    "name": "AzureCustomCloud",
+    "resourceManager": "https://management.contoso.local/",
+    "authentication": {
+      "loginEndpoint": "https://login.contoso.local/",
+      "audiences": [
+        "https://management.core.contoso.local/"
+      ]
+    },
+    "suffixes": {
+      "keyVaultDns": "vault.contoso.local"
+    }
+  }
+]`
+
+func Test_CloudConfigurationFromMetadata(t *testing.T) {
+	testcases := []struct {
+		Name         string
+		Metadata     string
+		MetadataHost string
+		ExpectError  bool
+		Expected     cloud.Configuration
+	}{
+		{
+			Name:         "match by metadata host",
+			Metadata:     testMetadata,
+			MetadataHost: "management.contoso.local",
+			Expected: cloud.Configuration{
+				ActiveDirectoryAuthorityHost: "https://login.contoso.local/",
+				Services: map[cloud.ServiceName]cloud.ServiceConfiguration{
+					cloud.ResourceManager: {
+						Audience: "https://management.core.contoso.local/",
+						Endpoint: "https://management.contoso.local/",
+					},
+					KeyVault: {
+						Audience: "https://vault.contoso.local",
+						Endpoint: "https://vault.contoso.local",
+					},
+				},
+			},
+		},
+		{
+			Name:     "first environment is used",
+			Metadata: testMetadata,
+			Expected: cloud.Configuration{
+				ActiveDirectoryAuthorityHost: "https://login.microsoftonline.com/",
+				Services: map[cloud.ServiceName]cloud.ServiceConfiguration{
+					cloud.ResourceManager: {
+						Audience: "https://management.core.windows.net/",
+						Endpoint: "https://management.azure.com/",
+					},
+					KeyVault: {
+						Audience: "https://vault.azure.net",
+						Endpoint: "https://vault.azure.net",
+					},
+					Synapse: {
+						Audience: "https://dev.azuresynapse.net",
+						Endpoint: "https://dev.azuresynapse.net",
+					},
+				},
+			},
+		},
+		{
+			Name:         "azure stack hub",
+			Metadata:     `{"galleryEndpoint": "https://portal.local.azurestack.external:30015/", "authentication": {"loginEndpoint": "https://login.microsoftonline.com/", "audiences": ["https://management.contoso.onmicrosoft.com/0000"]}}`,
+			MetadataHost: "management.local.azurestack.external",
+			Expected: cloud.Configuration{
+				ActiveDirectoryAuthorityHost: "https://login.microsoftonline.com/",
+				Services: map[cloud.ServiceName]cloud.ServiceConfiguration{
+					cloud.ResourceManager: {
+						Audience: "https://management.contoso.onmicrosoft.com/0000",
+						Endpoint: "https://management.local.azurestack.external/",
+					},
+				},
+			},
+		},
+		{
+			Name:        "no environment",
+			Metadata:    `[]`,
+			ExpectError: true,
+		},
+		{
+			Name:        "missing audiences",
+			Metadata:    `{"resourceManager": "https://management.contoso.local/", "authentication": {"loginEndpoint": "https://login.contoso.local/"}}`,
+			ExpectError: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.Name, func(t *testing.T) {
+			actual, err := cloudConfigurationFromMetadata([]byte(tc.Metadata), tc.MetadataHost)
+			if tc.ExpectError {
+				if err == nil {
+					t.Fatal("expect an error but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %+v", err)
+			}
+			assertCloudConfiguration(t, tc.Expected, actual)
+		})
+	}
+}
+
+func Test_CloudConfigurationFromMetadataHost(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/metadata/endpoints" || r.URL.Query().Get("api-version") != metadataApiVersion {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(testMetadata))
+	}))
+	defer server.Close()
+
+	actual, err := cloudConfigurationFromMetadataHost(context.Background(), server.Client(), strings.TrimPrefix(server.URL, "https://"))
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if actual.Services[cloud.ResourceManager].Endpoint != "https://management.azure.com/" {
+		t.Fatalf("expect the first environment is used, got %+v", actual)
+	}
+
+	notFound := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer notFound.Close()
+	if _, err := cloudConfigurationFromMetadataHost(context.Background(), notFound.Client(), strings.TrimPrefix(notFound.URL, "https://")); err == nil {
+		t.Fatal("expect an error but got nil")
+	}
+}
+
+func Test_CloudConfigurationFromMetadataHostTimeout(t *testing.T) {
+	done := make(chan struct{})
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(done)
+
+	timeout := metadataTimeout
+	metadataTimeout = 100 * time.Millisecond
+	defer func() { metadataTimeout = timeout }()
+
+	start := time.Now()
+	if _, err := cloudConfigurationFromMetadataHost(context.Background(), server.Client(), strings.TrimPrefix(server.URL, "https://")); err == nil {
+		t.Fatal("expect an error but got nil")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("expect the request to time out, but it took %s", elapsed)
+	}
+}
+
+func Test_MissingDataPlaneServices(t *testing.T) {
+	config, err := cloudConfigurationFromMetadata([]byte(testMetadata), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual := missingDataPlaneServices(config)
+	expected := []string{string(AppConfiguration), string(DeviceUpdate), string(DigitalTwins), string(IoTCentral), string(Purview)}
+	if strings.Join(actual, ",") != strings.Join(expected, ",") {
+		t.Fatalf("expect %v, got %v", expected, actual)
+	}
+}
+
+func Test_CloudConfigurationFromMetadataFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metadata.json")
+	if err := os.WriteFile(path, []byte(testMetadata), 0600); err != nil {
+		t.Fatal(err)
+	}
+	actual, err := cloudConfigurationFromMetadataFile(path, "management.contoso.local")
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if actual.ActiveDirectoryAuthorityHost != "https://login.contoso.local/" {
+		t.Fatalf("expect the custom environment is used, got %+v", actual)
+	}
+
+	if _, err := cloudConfigurationFromMetadataFile(filepath.Join(t.TempDir(), "not-exist.json"), ""); err == nil {
+		t.Fatal("expect an error but got nil")
+	}
+}
+
+func assertCloudConfiguration(t *testing.T, expected, actual cloud.Configuration) {
+	if expected.ActiveDirectoryAuthorityHost != actual.ActiveDirectoryAuthorityHost {
+		t.Fatalf("expect ActiveDirectoryAuthorityHost %q, got %q", expected.ActiveDirectoryAuthorityHost, actual.ActiveDirectoryAuthorityHost)
+	}
+	if len(expected.Services) != len(actual.Services) {
+		t.Fatalf("expect %d services, got %d: %+v", len(expected.Services), len(actual.Services), actual.Services)
+	}
+	for name, service := range expected.Services {
+		if actual.Services[name] != service {
+			t.Fatalf("expect service %s to be %+v, got %+v", name, service, actual.Services[name])
+		}
+	}
+}
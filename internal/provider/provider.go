@@ -5,7 +5,9 @@ import (
 	"encoding/base64"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"regexp"
 	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
@@ -16,11 +18,15 @@ import (
 	"github.com/Azure/terraform-provider-azapi/internal/azure/tags"
 	"github.com/Azure/terraform-provider-azapi/internal/clients"
 	"github.com/Azure/terraform-provider-azapi/internal/features"
+	"github.com/Azure/terraform-provider-azapi/internal/locks"
 	"github.com/Azure/terraform-provider-azapi/internal/services"
 	"github.com/Azure/terraform-provider-azapi/internal/services/functions"
 	"github.com/Azure/terraform-provider-azapi/internal/services/myvalidator"
+	"github.com/Azure/terraform-provider-azapi/internal/services/parse"
+	"github.com/Azure/terraform-provider-azapi/internal/services/preflight"
 	"github.com/Azure/terraform-provider-azapi/version"
 	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
@@ -46,38 +52,57 @@ type Provider struct {
 }
 
 type providerData struct {
-	SubscriptionID               types.String `tfsdk:"subscription_id"`
-	ClientID                     types.String `tfsdk:"client_id"`
-	ClientIDFilePath             types.String `tfsdk:"client_id_file_path"`
-	TenantID                     types.String `tfsdk:"tenant_id"`
-	AuxiliaryTenantIDs           types.List   `tfsdk:"auxiliary_tenant_ids"`
-	Endpoint                     types.List   `tfsdk:"endpoint"`
-	Environment                  types.String `tfsdk:"environment"`
-	ClientCertificate            types.String `tfsdk:"client_certificate"`
-	ClientCertificatePath        types.String `tfsdk:"client_certificate_path"`
-	ClientCertificatePassword    types.String `tfsdk:"client_certificate_password"`
-	ClientSecret                 types.String `tfsdk:"client_secret"`
-	ClientSecretFilePath         types.String `tfsdk:"client_secret_file_path"`
-	SkipProviderRegistration     types.Bool   `tfsdk:"skip_provider_registration"`
-	OIDCRequestToken             types.String `tfsdk:"oidc_request_token"`
-	OIDCRequestURL               types.String `tfsdk:"oidc_request_url"`
-	OIDCToken                    types.String `tfsdk:"oidc_token"`
-	OIDCTokenFilePath            types.String `tfsdk:"oidc_token_file_path"`
-	OIDCAzureServiceConnectionID types.String `tfsdk:"oidc_azure_service_connection_id"`
-	UseOIDC                      types.Bool   `tfsdk:"use_oidc"`
-	UseCLI                       types.Bool   `tfsdk:"use_cli"`
-	UseMSI                       types.Bool   `tfsdk:"use_msi"`
-	UseAKSWorkloadIdentity       types.Bool   `tfsdk:"use_aks_workload_identity"`
-	PartnerID                    types.String `tfsdk:"partner_id"`
-	CustomCorrelationRequestID   types.String `tfsdk:"custom_correlation_request_id"`
-	DisableCorrelationRequestID  types.Bool   `tfsdk:"disable_correlation_request_id"`
-	DisableTerraformPartnerID    types.Bool   `tfsdk:"disable_terraform_partner_id"`
-	DefaultName                  types.String `tfsdk:"default_name"`
-	DefaultLocation              types.String `tfsdk:"default_location"`
-	DefaultTags                  types.Map    `tfsdk:"default_tags"`
-	EnablePreflight              types.Bool   `tfsdk:"enable_preflight"`
-	DisableDefaultOutput         types.Bool   `tfsdk:"disable_default_output"`
-	MaximumBusyRetryAttempts     types.Int32  `tfsdk:"maximum_busy_retry_attempts"`
+	SubscriptionID                   types.String `tfsdk:"subscription_id"`
+	ClientID                         types.String `tfsdk:"client_id"`
+	ClientIDFilePath                 types.String `tfsdk:"client_id_file_path"`
+	TenantID                         types.String `tfsdk:"tenant_id"`
+	AuxiliaryTenantIDs               types.List   `tfsdk:"auxiliary_tenant_ids"`
+	Endpoint                         types.List   `tfsdk:"endpoint"`
+	Environment                      types.String `tfsdk:"environment"`
+	MetadataHost                     types.String `tfsdk:"metadata_host"`
+	MetadataFile                     types.String `tfsdk:"metadata_file"`
+	ClientCertificate                types.String `tfsdk:"client_certificate"`
+	ClientCertificatePath            types.String `tfsdk:"client_certificate_path"`
+	ClientCertificatePassword        types.String `tfsdk:"client_certificate_password"`
+	ClientSecret                     types.String `tfsdk:"client_secret"`
+	ClientSecretFilePath             types.String `tfsdk:"client_secret_file_path"`
+	SkipProviderRegistration         types.Bool   `tfsdk:"skip_provider_registration"`
+	OIDCRequestToken                 types.String `tfsdk:"oidc_request_token"`
+	OIDCRequestURL                   types.String `tfsdk:"oidc_request_url"`
+	OIDCToken                        types.String `tfsdk:"oidc_token"`
+	OIDCTokenFilePath                types.String `tfsdk:"oidc_token_file_path"`
+	OIDCAzureServiceConnectionID     types.String `tfsdk:"oidc_azure_service_connection_id"`
+	UseOIDC                          types.Bool   `tfsdk:"use_oidc"`
+	UseCLI                           types.Bool   `tfsdk:"use_cli"`
+	UseMSI                           types.Bool   `tfsdk:"use_msi"`
+	UseAKSWorkloadIdentity           types.Bool   `tfsdk:"use_aks_workload_identity"`
+	PartnerID                        types.String `tfsdk:"partner_id"`
+	CustomCorrelationRequestID       types.String `tfsdk:"custom_correlation_request_id"`
+	DisableCorrelationRequestID      types.Bool   `tfsdk:"disable_correlation_request_id"`
+	PerOperationCorrelationRequestID types.Bool   `tfsdk:"per_operation_correlation_request_id"`
+	OperationJournalPath             types.String `tfsdk:"operation_journal_path"`
+	DisableTerraformPartnerID        types.Bool   `tfsdk:"disable_terraform_partner_id"`
+	DefaultName                      types.String `tfsdk:"default_name"`
+	DefaultLocation                  types.String `tfsdk:"default_location"`
+	DefaultTags                      types.Map    `tfsdk:"default_tags"`
+	DefaultHeaders                   types.Map    `tfsdk:"default_headers"`
+	DefaultQueryParameters           types.Map    `tfsdk:"default_query_parameters"`
+	DataPlaneDefaultHeaders          types.Map    `tfsdk:"data_plane_default_headers"`
+	DataPlaneDefaultQueryParameters  types.Map    `tfsdk:"data_plane_default_query_parameters"`
+	EnablePreflight                  types.Bool   `tfsdk:"enable_preflight"`
+	PreflightCacheDirectory          types.String `tfsdk:"preflight_cache_directory"`
+	EnableWhatIf                     types.Bool   `tfsdk:"enable_what_if"`
+	EnablePolicyCheck                types.Bool   `tfsdk:"enable_policy_check"`
+	AutoLockParent                   types.Bool   `tfsdk:"auto_lock_parent"`
+	AutoLockParentTypes              types.List   `tfsdk:"auto_lock_parent_types"`
+	LockBackend                      types.String `tfsdk:"lock_backend"`
+	LockDirectory                    types.String `tfsdk:"lock_directory"`
+	ReadOnly                         types.Bool   `tfsdk:"read_only"`
+	ReadOnlyAllowedActions           types.List   `tfsdk:"read_only_allowed_actions"`
+	DisableDefaultOutput             types.Bool   `tfsdk:"disable_default_output"`
+	MaximumBusyRetryAttempts         types.Int32  `tfsdk:"maximum_busy_retry_attempts"`
+	CustomTypes                      types.List   `tfsdk:"custom_types"`
+	DataPlaneTypes                   types.List   `tfsdk:"data_plane_types"`
 }
 
 func (model providerData) GetClientId() (*string, error) {
@@ -150,6 +175,19 @@ type providerEndpointData struct {
 	ActiveDirectoryAuthorityHost types.String `tfsdk:"active_directory_authority_host"`
 	ResourceManagerEndpoint      types.String `tfsdk:"resource_manager_endpoint"`
 	ResourceManagerAudience      types.String `tfsdk:"resource_manager_audience"`
+	DataPlaneServices            types.Map    `tfsdk:"data_plane_services"`
+}
+
+type providerDataPlaneServiceEndpointData struct {
+	Endpoint types.String `tfsdk:"endpoint"`
+	Audience types.String `tfsdk:"audience"`
+}
+
+type providerDataPlaneTypeData struct {
+	ResourceType   types.String `tfsdk:"resource_type"`
+	UrlFormat      types.String `tfsdk:"url_format"`
+	EndpointSuffix types.String `tfsdk:"endpoint_suffix"`
+	Audience       types.String `tfsdk:"audience"`
 }
 
 func (p Provider) Metadata(ctx context.Context, request provider.MetadataRequest, response *provider.MetadataResponse) {
@@ -207,6 +245,59 @@ func (p Provider) Schema(ctx context.Context, request provider.SchemaRequest, re
 							Optional:            true,
 							MarkdownDescription: "The Azure Active Directory login endpoint to use. This can also be sourced from the `ARM_ACTIVE_DIRECTORY_AUTHORITY_HOST` Environment Variable. Defaults to `https://login.microsoftonline.com/` for public cloud.",
 						},
+
+						"data_plane_services": schema.MapNestedAttribute{
+							Optional: true,
+							Validators: []validator.Map{
+								mapvalidator.KeysAre(stringvalidator.OneOf(dataPlaneServiceNames()...)),
+							},
+							MarkdownDescription: "A mapping of data plane service names to their endpoint configurations, which overrides the built-in endpoint and audience of the data plane service in the selected environment. Possible keys are `AppConfiguration`, `DeviceUpdate`, `DigitalTwins`, `IoTCentral`, `KeyVault`, `Purview` and `Synapse`.",
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: map[string]schema.Attribute{
+									"endpoint": schema.StringAttribute{
+										Optional:            true,
+										MarkdownDescription: "The endpoint of the data plane service, for example, `https://vault.azure.net`. The requests whose host ends with the endpoint's host use the audience of this service.",
+									},
+
+									"audience": schema.StringAttribute{
+										Optional:            true,
+										MarkdownDescription: "The audience used to obtain AD tokens for the data plane service, for example, `https://vault.azure.net`.",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+
+			"data_plane_types": schema.ListNestedAttribute{
+				Optional:            true,
+				MarkdownDescription: "A list of additional data plane resource types which can be managed by the `azapi_data_plane_resource` resource. The data plane resource types defined here take precedence over the built-in ones.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"resource_type": schema.StringAttribute{
+							Required:            true,
+							Validators:          []validator.String{myvalidator.StringIsNotEmpty()},
+							MarkdownDescription: "The data plane resource type, for example, `Microsoft.Search/searchServices/indexes`.",
+						},
+
+						"url_format": schema.StringAttribute{
+							Required:            true,
+							Validators:          []validator.String{stringvalidator.RegexMatches(regexp.MustCompile(`^\{parentId\}/`), "must start with `{parentId}/`")},
+							MarkdownDescription: "The format of the resource URL without the scheme. It must start with the `{parentId}` placeholder, and the `{name}` placeholder is replaced with the resource name, for example, `{parentId}/indexes/{name}`.",
+						},
+
+						"endpoint_suffix": schema.StringAttribute{
+							Required:            true,
+							Validators:          []validator.String{myvalidator.StringIsNotEmpty()},
+							MarkdownDescription: "The suffix of the data plane endpoint host, which is used to find the audience for the requests, for example, `search.windows.net`.",
+						},
+
+						"audience": schema.StringAttribute{
+							Required:            true,
+							Validators:          []validator.String{myvalidator.StringIsNotEmpty()},
+							MarkdownDescription: "The audience used to obtain AD tokens for the requests, for example, `https://search.azure.com`.",
+						},
 					},
 				},
 			},
@@ -214,18 +305,20 @@ func (p Provider) Schema(ctx context.Context, request provider.SchemaRequest, re
 			"environment": schema.StringAttribute{
 				Optional: true,
 				Validators: []validator.String{
-					stringvalidator.OneOfCaseInsensitive("public", "usgovernment", "china"),
+					stringvalidator.OneOfCaseInsensitive("public", "usgovernment", "china", "custom"),
 				},
-				MarkdownDescription: "The Cloud Environment which should be used. Possible values are `public`, `usgovernment` and `china`. Defaults to `public`. This can also be sourced from the `ARM_ENVIRONMENT` Environment Variable.",
+				MarkdownDescription: "The Cloud Environment which should be used. Possible values are `public`, `usgovernment`, `china` and `custom`. Defaults to `public`. When set to `custom`, the endpoints are loaded from either `metadata_host` or `metadata_file`. The metadata only provides the data plane endpoints of Key Vault and Synapse, the endpoints of the other data plane services can be specified in the `endpoint.data_plane_services` field. This can also be sourced from the `ARM_ENVIRONMENT` Environment Variable.",
 			},
 
-			// TODO@mgd: the metadata_host is used to retrieve metadata from Azure to identify current environment, this is used to eliminate Azure Stack usage, in which case the provider doesn't support.
-			// "metadata_host": {
-			// 	Type:        schema.TypeString,
-			// 	Required:    true,
-			// 	DefaultFunc: schema.EnvDefaultFunc("ARM_METADATA_HOSTNAME", ""),
-			// 	Description: "The Hostname which should be used for the Azure Metadata Service.",
-			// },
+			"metadata_host": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The Hostname of the Azure Metadata Service, for example, `management.azure.com` or the Azure Resource Manager endpoint of Azure Stack Hub. It's used to retrieve the endpoints when the `environment` is `custom`. The request times out after 30 seconds. This can also be sourced from the `ARM_METADATA_HOSTNAME` Environment Variable.",
+			},
+
+			"metadata_file": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The path to a file containing the response of the Azure Metadata Service `/metadata/endpoints` API. It's used to load the endpoints when the `environment` is `custom`, in which case it takes precedence over the `metadata_host`. If the file contains multiple environments, the one whose resource manager endpoint matches the `metadata_host` is used, otherwise the first one. This can also be sourced from the `ARM_METADATA_FILE` Environment Variable.",
+			},
 
 			// Client Certificate specific fields
 			"client_certificate_path": schema.StringAttribute{
@@ -334,6 +427,19 @@ func (p Provider) Schema(ctx context.Context, request provider.SchemaRequest, re
 				MarkdownDescription: "This will disable the x-ms-correlation-request-id header.",
 			},
 
+			"per_operation_correlation_request_id": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: "Whether to generate an `x-ms-correlation-request-id` header for each Terraform operation, for example, creating, reading, updating or deleting a resource, so the Azure Resource Manager operations of a resource can be found by its correlation request ID. The default is false, which means all the requests sent by the provider share the same correlation request ID, which is the `custom_correlation_request_id` or an auto-generated UUID. It has no effect if the `disable_correlation_request_id` is true. This can also be sourced from the `ARM_PER_OPERATION_CORRELATION_REQUEST_ID` Environment Variable.",
+			},
+
+			"operation_journal_path": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				MarkdownDescription: "The path of the operation journal file. When it's specified, a JSON line is appended to the file for each request sent by the provider, which contains the time, the `x-ms-correlation-request-id`, the `x-ms-request-id`, the resource ID, the method, the URL, the status code and the duration of the request. The file is created if it doesn't exist. This can also be sourced from the `ARM_OPERATION_JOURNAL_PATH` Environment Variable.",
+			},
+
 			"disable_terraform_partner_id": schema.BoolAttribute{
 				Optional:            true,
 				MarkdownDescription: "Disable sending the Terraform Partner ID if a custom `partner_id` isn't specified, which allows Microsoft to better understand the usage of Terraform. The Partner ID does not give HashiCorp any direct access to usage information. This can also be sourced from the `ARM_DISABLE_TERRAFORM_PARTNER_ID` environment variable. Defaults to `false`.",
@@ -358,9 +464,99 @@ func (p Provider) Schema(ctx context.Context, request provider.SchemaRequest, re
 				MarkdownDescription: "A mapping of tags which should be assigned to the azure resource as default tags. The`tags` in each resource block can override the `default_tags`.",
 			},
 
+			"default_headers": schema.MapAttribute{
+				Optional:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "A mapping of headers which should be sent with all the Azure Resource Manager requests, for example, the `x-ms-client-tenant-id` header or the feature headers of a resource provider. The headers in each resource block, for example, the `create_headers`, take precedence over the `default_headers`.",
+			},
+
+			"default_query_parameters": schema.MapAttribute{
+				Optional: true,
+				ElementType: types.ListType{
+					ElemType: types.StringType,
+				},
+				MarkdownDescription: "A mapping of query parameters which should be sent with all the Azure Resource Manager requests. The query parameters in each resource block, for example, the `create_query_parameters`, take precedence over the `default_query_parameters`.",
+			},
+
+			"data_plane_default_headers": schema.MapAttribute{
+				Optional:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "A mapping of headers which should be sent with all the data plane requests. The headers in each resource block, for example, the `create_headers`, take precedence over the `data_plane_default_headers`.",
+			},
+
+			"data_plane_default_query_parameters": schema.MapAttribute{
+				Optional: true,
+				ElementType: types.ListType{
+					ElemType: types.StringType,
+				},
+				MarkdownDescription: "A mapping of query parameters which should be sent with all the data plane requests. The query parameters in each resource block, for example, the `create_query_parameters`, take precedence over the `data_plane_default_query_parameters`.",
+			},
+
 			"enable_preflight": schema.BoolAttribute{
 				Optional:    true,
-				Description: "Enable Preflight Validation. The default is false. When set to true, the provider will use Preflight to do static validation before really deploying a new resource. When set to false, the provider will disable this validation. This can also be sourced from the `ARM_ENABLE_PREFLIGHT` Environment Variable.",
+				Description: "Enable Preflight Validation. The default is false. When set to true, the provider will use Preflight to do static validation before really deploying a new resource or updating an existing resource. When set to false, the provider will disable this validation. This can also be sourced from the `ARM_ENABLE_PREFLIGHT` Environment Variable.",
+			},
+
+			"auto_lock_parent": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: "Whether to lock the parent resource when a child resource is created, updated or deleted, if the child resource type is known to conflict with the other child resources of the same parent resource, for example, the subnets of a virtual network or the security rules of a network security group. The default is false. The `auto_lock_parent` in each resource block can override this value. This can also be sourced from the `ARM_AUTO_LOCK_PARENT` Environment Variable.",
+			},
+
+			"auto_lock_parent_types": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+				MarkdownDescription: "A list of additional child resource types whose parent resource should be locked when `auto_lock_parent` is enabled, for example, `Microsoft.Network/virtualNetworks/subnets`. They are used together with the built-in list of the resource types which are known to conflict with the other child resources of the same parent resource.",
+			},
+
+			"preflight_cache_directory": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				MarkdownDescription: "The directory where the successful preflight validation results are cached when `enable_preflight` is enabled. The results are reused for one hour, so the unchanged resources are not validated again by the later Terraform runs on the same host. Defaults to the `terraform-provider-azapi-preflight-cache` directory in the temporary directory of the operating system. This can also be sourced from the `ARM_PREFLIGHT_CACHE_DIRECTORY` Environment Variable.",
+			},
+
+			"lock_backend": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(locks.BackendMemory, locks.BackendFile),
+				},
+				MarkdownDescription: "The backend which is used to coordinate the `locks`. Possible values are `memory` and `file`. Defaults to `memory`. When set to `memory`, the locks only work within the provider process. When set to `file`, the locks are coordinated with the file locks in the `lock_directory`, so they also work across the Terraform runs on the same host, for example, the parallel `terraform apply` of multiple workspaces on the same CI agent. This can also be sourced from the `ARM_LOCK_BACKEND` Environment Variable.",
+			},
+
+			"lock_directory": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				MarkdownDescription: "The directory of the lock files when the `lock_backend` is `file`. The Terraform runs which share the locks must use the same directory. Defaults to the `terraform-provider-azapi-locks` directory in the temporary directory of the operating system. This can also be sourced from the `ARM_LOCK_DIRECTORY` Environment Variable.",
+			},
+
+			"read_only": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: "Enable the read-only mode. The default is false. When set to true, the provider only sends the `GET` and `HEAD` requests to the Azure Resource Manager and the data plane, the other requests, which may change the resources, are blocked with an error, except for the `POST` actions in the `read_only_allowed_actions`. The resource providers are not registered automatically in this mode. It's useful for the auditing and drift checks, a mistaken `terraform apply` can't change the resources even if the credential is allowed to. This can also be sourced from the `ARM_READ_ONLY` Environment Variable.",
+			},
+
+			"read_only_allowed_actions": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+				MarkdownDescription: "A list of the `POST` actions which are allowed when the `read_only` is enabled, for example, `listKeys`. The action is the last segment of the request URL and is case-insensitive. The `POST` requests sent by the `enable_preflight`, `enable_what_if` and `enable_policy_check` features are also blocked unless their actions, `validateResources`, `whatIf` and `checkPolicyRestrictions`, are allowed.",
+			},
+
+			"enable_policy_check": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Enable Azure Policy Check. The default is false. When set to true, the provider will use the `checkPolicyRestrictions` API to evaluate the Azure Policies assigned to the subscription or the resource group before really deploying a new resource or updating an existing resource. The non-compliance with the policies whose effect is deny and the configured values which are denied by the field restrictions are reported as errors, the non-compliance with the audit policies and the other restrictions of the fields are reported as warnings. When set to false, the provider will disable this check. This can also be sourced from the `ARM_ENABLE_POLICY_CHECK` Environment Variable.",
+			},
+
+			"enable_what_if": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Enable What-If preview. The default is false. When set to true, the provider will use the deployment What-If operation to preview the changes of an existing resource which is deployed in a resource group when planning an update, and the predicted changes are shown as warnings. When set to false, the provider will disable this preview. This can also be sourced from the `ARM_ENABLE_WHAT_IF` Environment Variable.",
 			},
 
 			"disable_default_output": schema.BoolAttribute{
@@ -372,6 +568,12 @@ func (p Provider) Schema(ctx context.Context, request provider.SchemaRequest, re
 				Optional:            true,
 				MarkdownDescription: "The maximum number of retries to attempt if the Azure API returns an HTTP 408, 429, 500, 502, 503, or 504 response. The default is `3`. The resource-specific retry configuration may additionally be used to retry on other errors and conditions.",
 			},
+
+			"custom_types": schema.ListAttribute{
+				ElementType:         types.StringType,
+				Optional:            true,
+				MarkdownDescription: "A list of additional resource type definitions in the [bicep-types](https://github.com/Azure/bicep-types) `types.json` format. Each item can be either a path to the `types.json` file or its content. The custom types are used for schema validation and default output the same way as the embedded types, and they take precedence over the embedded types with the same resource type and API version. This can also be sourced from the `ARM_CUSTOM_TYPES` Environment Variable, in which case multiple paths are separated by `;`.",
+			},
 		},
 	}
 }
@@ -429,6 +631,14 @@ func (p Provider) Configure(ctx context.Context, request provider.ConfigureReque
 		attrTypes["active_directory_authority_host"] = types.StringType
 		attrTypes["resource_manager_endpoint"] = types.StringType
 		attrTypes["resource_manager_audience"] = types.StringType
+		attrTypes["data_plane_services"] = types.MapType{
+			ElemType: types.ObjectType{
+				AttrTypes: map[string]attr.Type{
+					"endpoint": types.StringType,
+					"audience": types.StringType,
+				},
+			},
+		}
 		model.Endpoint = types.ListValueMust(types.ObjectType{
 			AttrTypes: attrTypes,
 		}, []attr.Value{
@@ -436,6 +646,7 @@ func (p Provider) Configure(ctx context.Context, request provider.ConfigureReque
 				"active_directory_authority_host": types.StringValue(activeDirectoryAuthorityHost),
 				"resource_manager_endpoint":       types.StringValue(resourceManagerEndpoint),
 				"resource_manager_audience":       types.StringValue(resourceManagerAudience),
+				"data_plane_services":             types.MapNull(attrTypes["data_plane_services"].(types.MapType).ElemType),
 			}),
 		})
 	}
@@ -448,6 +659,18 @@ func (p Provider) Configure(ctx context.Context, request provider.ConfigureReque
 		}
 	}
 
+	if model.MetadataHost.IsNull() {
+		if v := os.Getenv("ARM_METADATA_HOSTNAME"); v != "" {
+			model.MetadataHost = types.StringValue(v)
+		}
+	}
+
+	if model.MetadataFile.IsNull() {
+		if v := os.Getenv("ARM_METADATA_FILE"); v != "" {
+			model.MetadataFile = types.StringValue(v)
+		}
+	}
+
 	if model.AuxiliaryTenantIDs.IsNull() {
 		if v := os.Getenv("ARM_AUXILIARY_TENANT_IDS"); v != "" {
 			values := make([]attr.Value, 0)
@@ -458,6 +681,16 @@ func (p Provider) Configure(ctx context.Context, request provider.ConfigureReque
 		}
 	}
 
+	if model.CustomTypes.IsNull() {
+		if v := os.Getenv("ARM_CUSTOM_TYPES"); v != "" {
+			values := make([]attr.Value, 0)
+			for _, v := range strings.Split(v, ";") {
+				values = append(values, types.StringValue(v))
+			}
+			model.CustomTypes = types.ListValueMust(types.StringType, values)
+		}
+	}
+
 	if model.ClientCertificate.IsNull() {
 		if v := os.Getenv("ARM_CLIENT_CERTIFICATE"); v != "" {
 			model.ClientCertificate = types.StringValue(v)
@@ -576,6 +809,20 @@ func (p Provider) Configure(ctx context.Context, request provider.ConfigureReque
 		}
 	}
 
+	if model.PerOperationCorrelationRequestID.IsNull() {
+		if v := os.Getenv("ARM_PER_OPERATION_CORRELATION_REQUEST_ID"); v != "" {
+			model.PerOperationCorrelationRequestID = types.BoolValue(v == "true")
+		} else {
+			model.PerOperationCorrelationRequestID = types.BoolValue(false)
+		}
+	}
+
+	if model.OperationJournalPath.IsNull() {
+		if v := os.Getenv("ARM_OPERATION_JOURNAL_PATH"); v != "" {
+			model.OperationJournalPath = types.StringValue(v)
+		}
+	}
+
 	if model.DisableTerraformPartnerID.IsNull() {
 		if v := os.Getenv("ARM_DISABLE_TERRAFORM_PARTNER_ID"); v != "" {
 			model.DisableTerraformPartnerID = types.BoolValue(v == "true")
@@ -591,6 +838,55 @@ func (p Provider) Configure(ctx context.Context, request provider.ConfigureReque
 			model.EnablePreflight = types.BoolValue(false)
 		}
 	}
+	if model.PreflightCacheDirectory.IsNull() {
+		if v := os.Getenv("ARM_PREFLIGHT_CACHE_DIRECTORY"); v != "" {
+			model.PreflightCacheDirectory = types.StringValue(v)
+		} else {
+			model.PreflightCacheDirectory = types.StringValue(preflight.DefaultCacheDirectory())
+		}
+	}
+	if model.AutoLockParent.IsNull() {
+		if v := os.Getenv("ARM_AUTO_LOCK_PARENT"); v != "" {
+			model.AutoLockParent = types.BoolValue(v == "true")
+		} else {
+			model.AutoLockParent = types.BoolValue(false)
+		}
+	}
+	if model.LockBackend.IsNull() {
+		if v := os.Getenv("ARM_LOCK_BACKEND"); v != "" {
+			model.LockBackend = types.StringValue(v)
+		} else {
+			model.LockBackend = types.StringValue(locks.BackendMemory)
+		}
+	}
+	if model.LockDirectory.IsNull() {
+		if v := os.Getenv("ARM_LOCK_DIRECTORY"); v != "" {
+			model.LockDirectory = types.StringValue(v)
+		} else {
+			model.LockDirectory = types.StringValue(locks.DefaultLockDirectory())
+		}
+	}
+	if model.ReadOnly.IsNull() {
+		if v := os.Getenv("ARM_READ_ONLY"); v != "" {
+			model.ReadOnly = types.BoolValue(v == "true")
+		} else {
+			model.ReadOnly = types.BoolValue(false)
+		}
+	}
+	if model.EnablePolicyCheck.IsNull() {
+		if v := os.Getenv("ARM_ENABLE_POLICY_CHECK"); v != "" {
+			model.EnablePolicyCheck = types.BoolValue(v == "true")
+		} else {
+			model.EnablePolicyCheck = types.BoolValue(false)
+		}
+	}
+	if model.EnableWhatIf.IsNull() {
+		if v := os.Getenv("ARM_ENABLE_WHAT_IF"); v != "" {
+			model.EnableWhatIf = types.BoolValue(v == "true")
+		} else {
+			model.EnableWhatIf = types.BoolValue(false)
+		}
+	}
 	if model.DisableDefaultOutput.IsNull() {
 		if v := os.Getenv("ARM_DISABLE_DEFAULT_OUTPUT"); v != "" {
 			model.DisableDefaultOutput = types.BoolValue(v == "true")
@@ -608,11 +904,32 @@ func (p Provider) Configure(ctx context.Context, request provider.ConfigureReque
 		cloudConfig = cloud.AzureGovernment
 	case "china":
 		cloudConfig = cloud.AzureChina
+	case "custom":
+		var err error
+		switch {
+		case model.MetadataFile.ValueString() != "":
+			cloudConfig, err = cloudConfigurationFromMetadataFile(model.MetadataFile.ValueString(), model.MetadataHost.ValueString())
+		case model.MetadataHost.ValueString() != "":
+			cloudConfig, err = cloudConfigurationFromMetadataHost(ctx, http.DefaultClient, model.MetadataHost.ValueString())
+		default:
+			err = fmt.Errorf("either `metadata_host` or `metadata_file` must be specified when the `environment` is 'custom'")
+		}
+		if err != nil {
+			response.Diagnostics.AddError("Failed to load the custom environment.", err.Error())
+			return
+		}
 	default:
-		response.Diagnostics.AddError("Invalid `environment` value.", fmt.Sprintf("The `environment` value '%s' is invalid. Valid values are 'public', 'usgovernment' and 'china'.", env))
+		response.Diagnostics.AddError("Invalid `environment` value.", fmt.Sprintf("The `environment` value '%s' is invalid. Valid values are 'public', 'usgovernment', 'china' and 'custom'.", env))
 		return
 	}
 
+	// copy the services to avoid modifying the global cloud configuration
+	services := make(map[cloud.ServiceName]cloud.ServiceConfiguration)
+	for k, v := range cloudConfig.Services {
+		services[k] = v
+	}
+	cloudConfig.Services = services
+
 	if elements := model.Endpoint.Elements(); len(elements) != 0 {
 		var endpoint providerEndpointData
 		diags := elements[0].(basetypes.ObjectValue).As(ctx, &endpoint, basetypes.ObjectAsOptions{
@@ -638,6 +955,58 @@ func (p Provider) Configure(ctx context.Context, request provider.ConfigureReque
 		if v := endpoint.ActiveDirectoryAuthorityHost.ValueString(); v != "" {
 			cloudConfig.ActiveDirectoryAuthorityHost = v
 		}
+		for name, element := range endpoint.DataPlaneServices.Elements() {
+			var dataPlaneService providerDataPlaneServiceEndpointData
+			diags := element.(basetypes.ObjectValue).As(ctx, &dataPlaneService, basetypes.ObjectAsOptions{
+				UnhandledNullAsEmpty:    false,
+				UnhandledUnknownAsEmpty: false,
+			})
+			response.Diagnostics.Append(diags...)
+			if diags.HasError() {
+				return
+			}
+			serviceConfiguration := cloudConfig.Services[cloud.ServiceName(name)]
+			if v := dataPlaneService.Endpoint.ValueString(); v != "" {
+				serviceConfiguration.Endpoint = strings.TrimSuffix(v, "/")
+			}
+			if v := dataPlaneService.Audience.ValueString(); v != "" {
+				serviceConfiguration.Audience = strings.TrimSuffix(v, "/")
+			}
+			cloudConfig.Services[cloud.ServiceName(name)] = serviceConfiguration
+		}
+	}
+
+	if strings.EqualFold(env, "custom") {
+		if missing := missingDataPlaneServices(cloudConfig); len(missing) != 0 {
+			response.Diagnostics.AddWarning("Data plane services are not configured in the custom environment.", fmt.Sprintf("The endpoints of the data plane services %s are not provided by the metadata of the custom environment. The requests to these services are authenticated with the resource manager audience and might be rejected, unless their endpoints and audiences are specified in the `endpoint.data_plane_services` field.", strings.Join(missing, ", ")))
+		}
+	}
+
+	if elements := model.DataPlaneTypes.Elements(); len(elements) != 0 {
+		apiPaths := make([]parse.ApiPath, 0)
+		for _, element := range elements {
+			var dataPlaneType providerDataPlaneTypeData
+			diags := element.(basetypes.ObjectValue).As(ctx, &dataPlaneType, basetypes.ObjectAsOptions{
+				UnhandledNullAsEmpty:    false,
+				UnhandledUnknownAsEmpty: false,
+			})
+			response.Diagnostics.Append(diags...)
+			if diags.HasError() {
+				return
+			}
+			endpointSuffix := strings.TrimSuffix(strings.TrimPrefix(dataPlaneType.EndpointSuffix.ValueString(), "https://"), "/")
+			apiPaths = append(apiPaths, parse.ApiPath{
+				UrlFormat:    dataPlaneType.UrlFormat.ValueString(),
+				ResourceType: dataPlaneType.ResourceType.ValueString(),
+			})
+			cloudConfig.Services[cloud.ServiceName(endpointSuffix)] = cloud.ServiceConfiguration{
+				Audience: strings.TrimSuffix(dataPlaneType.Audience.ValueString(), "/"),
+				Endpoint: fmt.Sprintf("https://%s", endpointSuffix),
+			}
+		}
+		parse.SetCustomApiPaths(apiPaths)
+	} else {
+		parse.SetCustomApiPaths(nil)
 	}
 
 	var auxTenants []string
@@ -664,6 +1033,14 @@ func (p Provider) Configure(ctx context.Context, request provider.ConfigureReque
 	if !model.MaximumBusyRetryAttempts.IsNull() {
 		maxGoSdkRetryAttempts = model.MaximumBusyRetryAttempts.ValueInt32()
 	}
+	autoLockParentTypes := make([]string, 0)
+	for _, element := range model.AutoLockParentTypes.Elements() {
+		autoLockParentTypes = append(autoLockParentTypes, element.(basetypes.StringValue).ValueString())
+	}
+	readOnlyAllowedActions := make([]string, 0)
+	for _, element := range model.ReadOnlyAllowedActions.Elements() {
+		readOnlyAllowedActions = append(readOnlyAllowedActions, element.(basetypes.StringValue).ValueString())
+	}
 	copt := &clients.Option{
 		Cred:                 cred,
 		CloudCfg:             cloudConfig,
@@ -674,13 +1051,23 @@ func (p Provider) Configure(ctx context.Context, request provider.ConfigureReque
 			DefaultLocation:      location.Normalize(model.DefaultLocation.ValueString()),
 			DefaultNaming:        model.DefaultName.ValueString(),
 			EnablePreflight:      model.EnablePreflight.ValueBool(),
+			EnableWhatIf:         model.EnableWhatIf.ValueBool(),
+			EnablePolicyCheck:    model.EnablePolicyCheck.ValueBool(),
+			AutoLockParent:       model.AutoLockParent.ValueBool(),
+			AutoLockParentTypes:  autoLockParentTypes,
 			DisableDefaultOutput: model.DisableDefaultOutput.ValueBool(),
 		},
-		SkipProviderRegistration:    model.SkipProviderRegistration.ValueBool(),
-		DisableCorrelationRequestID: model.DisableCorrelationRequestID.ValueBool(),
-		CustomCorrelationRequestID:  model.CustomCorrelationRequestID.ValueString(),
-		SubscriptionId:              model.SubscriptionID.ValueString(),
-		TenantId:                    model.TenantID.ValueString(),
+		SkipProviderRegistration:         model.SkipProviderRegistration.ValueBool(),
+		DisableCorrelationRequestID:      model.DisableCorrelationRequestID.ValueBool(),
+		CustomCorrelationRequestID:       model.CustomCorrelationRequestID.ValueString(),
+		PerOperationCorrelationRequestID: model.PerOperationCorrelationRequestID.ValueBool(),
+		OperationJournalPath:             model.OperationJournalPath.ValueString(),
+		ReadOnly:                         model.ReadOnly.ValueBool(),
+		ReadOnlyAllowedActions:           readOnlyAllowedActions,
+		SubscriptionId:                   model.SubscriptionID.ValueString(),
+		TenantId:                         model.TenantID.ValueString(),
+		ResourceManagerDefaultOptions:    clients.NewRequestOptions(expandHeaders(model.DefaultHeaders), expandQueryParameters(model.DefaultQueryParameters)),
+		DataPlaneDefaultOptions:          clients.NewRequestOptions(expandHeaders(model.DataPlaneDefaultHeaders), expandQueryParameters(model.DataPlaneDefaultQueryParameters)),
 	}
 
 	client := &clients.Client{}
@@ -692,6 +1079,34 @@ func (p Provider) Configure(ctx context.Context, request provider.ConfigureReque
 	// load schema
 	azure.GetAzureSchema()
 
+	// load custom types
+	azure.ResetCustomTypes()
+	for _, element := range model.CustomTypes.Elements() {
+		if err := loadCustomTypes(element.(basetypes.StringValue).ValueString()); err != nil {
+			response.Diagnostics.AddError("Failed to load custom types", err.Error())
+			return
+		}
+	}
+
+	// configure the preflight validation cache
+	preflight.SetCacheDirectory(model.PreflightCacheDirectory.ValueString())
+
+	// configure the lock backend
+	switch model.LockBackend.ValueString() {
+	case locks.BackendFile:
+		backend, err := locks.NewFileBackend(model.LockDirectory.ValueString())
+		if err != nil {
+			response.Diagnostics.AddError("Failed to configure the lock backend", err.Error())
+			return
+		}
+		locks.SetBackend(backend)
+	case locks.BackendMemory:
+		locks.SetBackend(locks.NewMemoryBackend())
+	default:
+		response.Diagnostics.AddError("Failed to configure the lock backend", fmt.Sprintf("the lock backend %q is not supported, possible values are %q and %q", model.LockBackend.ValueString(), locks.BackendMemory, locks.BackendFile))
+		return
+	}
+
 	response.ResourceData = client
 	response.DataSourceData = client
 	response.EphemeralResourceData = client
@@ -732,6 +1147,15 @@ func (p Provider) DataSources(ctx context.Context) []func() datasource.DataSourc
 		func() datasource.DataSource {
 			return &services.ClientConfigDataSource{}
 		},
+		func() datasource.DataSource {
+			return &services.DataPlaneResourceDataSource{}
+		},
+		func() datasource.DataSource {
+			return &services.DataPlaneResourceListDataSource{}
+		},
+		func() datasource.DataSource {
+			return &services.DataPlaneActionDataSource{}
+		},
 	}
 
 }
@@ -750,6 +1174,12 @@ func (p Provider) Resources(ctx context.Context) []func() resource.Resource {
 		func() resource.Resource {
 			return &services.DataPlaneResource{}
 		},
+		func() resource.Resource {
+			return &services.DataPlaneActionResource{}
+		},
+		func() resource.Resource {
+			return &services.DataPlaneUpdateResource{}
+		},
 	}
 }
 
@@ -758,6 +1188,9 @@ func (p Provider) EphemeralResources(ctx context.Context) []func() ephemeral.Eph
 		func() ephemeral.EphemeralResource {
 			return &services.ActionEphemeral{}
 		},
+		func() ephemeral.EphemeralResource {
+			return &services.DataPlaneActionEphemeral{}
+		},
 	}
 }
 
@@ -969,6 +1402,20 @@ func buildAzurePipelinesCredential(model providerData, options azidentity.Defaul
 	return azidentity.NewAzurePipelinesCredential(options.TenantID, *clientId, model.OIDCAzureServiceConnectionID.ValueString(), model.OIDCRequestToken.ValueString(), o)
 }
 
+// loadCustomTypes registers the custom types from either a path to a `types.json` file or its content.
+func loadCustomTypes(value string) error {
+	data := []byte(value)
+	if !strings.HasPrefix(strings.TrimSpace(value), "[") {
+		// #nosec G304
+		fileData, err := os.ReadFile(value)
+		if err != nil {
+			return fmt.Errorf("reading custom types from file %q: %v", value, err)
+		}
+		data = fileData
+	}
+	return azure.LoadCustomTypes(data)
+}
+
 func decodeCertificate(clientCertificate string) ([]byte, error) {
 	var pfx []byte
 	if clientCertificate != "" {
@@ -981,3 +1428,23 @@ func decodeCertificate(clientCertificate string) ([]byte, error) {
 	}
 	return pfx, nil
 }
+
+func expandHeaders(input types.Map) map[string]string {
+	out := make(map[string]string)
+	for key, value := range input.Elements() {
+		out[key] = value.(basetypes.StringValue).ValueString()
+	}
+	return out
+}
+
+func expandQueryParameters(input types.Map) map[string][]string {
+	out := make(map[string][]string)
+	for key, value := range input.Elements() {
+		values := make([]string, 0)
+		for _, element := range value.(basetypes.ListValue).Elements() {
+			values = append(values, element.(basetypes.StringValue).ValueString())
+		}
+		out[key] = values
+	}
+	return out
+}
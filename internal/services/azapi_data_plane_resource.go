@@ -5,9 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
-	"slices"
 	"time"
 
+	"github.com/Azure/terraform-provider-azapi/internal/azure"
 	"github.com/Azure/terraform-provider-azapi/internal/clients"
 	"github.com/Azure/terraform-provider-azapi/internal/docstrings"
 	"github.com/Azure/terraform-provider-azapi/internal/locks"
@@ -46,11 +46,13 @@ type DataPlaneResourceModel struct {
 	Body                          types.Dynamic    `tfsdk:"body"`
 	IgnoreCasing                  types.Bool       `tfsdk:"ignore_casing"`
 	IgnoreMissingProperty         types.Bool       `tfsdk:"ignore_missing_property"`
+	ArrayItemKeys                 types.Map        `tfsdk:"array_item_keys"`
 	ReplaceTriggersExternalValues types.Dynamic    `tfsdk:"replace_triggers_external_values"`
 	ReplaceTriggersRefs           types.List       `tfsdk:"replace_triggers_refs"`
 	ResponseExportValues          types.Dynamic    `tfsdk:"response_export_values"`
 	Retry                         retry.RetryValue `tfsdk:"retry" skip_on:"update"`
 	Locks                         types.List       `tfsdk:"locks"`
+	SharedLocks                   types.List       `tfsdk:"shared_locks"`
 	Output                        types.Dynamic    `tfsdk:"output"`
 	Timeouts                      timeouts.Value   `tfsdk:"timeouts" skip_on:"update"`
 	CreateHeaders                 types.Map        `tfsdk:"create_headers" skip_on:"update"`
@@ -61,6 +63,7 @@ type DataPlaneResourceModel struct {
 	DeleteQueryParameters         types.Map        `tfsdk:"delete_query_parameters" skip_on:"update"`
 	ReadHeaders                   types.Map        `tfsdk:"read_headers" skip_on:"update"`
 	ReadQueryParameters           types.Map        `tfsdk:"read_query_parameters" skip_on:"update"`
+	SchemaValidationEnabled       types.Bool       `tfsdk:"schema_validation_enabled" skip_on:"update"`
 }
 
 type DataPlaneResource struct {
@@ -71,6 +74,7 @@ var _ resource.Resource = &DataPlaneResource{}
 var _ resource.ResourceWithConfigure = &DataPlaneResource{}
 var _ resource.ResourceWithModifyPlan = &DataPlaneResource{}
 var _ resource.ResourceWithUpgradeState = &DataPlaneResource{}
+var _ resource.ResourceWithImportState = &DataPlaneResource{}
 
 func (r *DataPlaneResource) Configure(ctx context.Context, request resource.ConfigureRequest, response *resource.ConfigureResponse) {
 	tflog.Debug(ctx, "Configuring azapi_data_plane_resource")
@@ -158,6 +162,19 @@ func (r *DataPlaneResource) Schema(ctx context.Context, request resource.SchemaR
 				MarkdownDescription: docstrings.IgnoreMissingProperty(),
 			},
 
+			"array_item_keys": schema.MapAttribute{
+				ElementType:         types.StringType,
+				Optional:            true,
+				MarkdownDescription: docstrings.ArrayItemKeys(),
+			},
+
+			"schema_validation_enabled": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             defaults.BoolDefault(true),
+				MarkdownDescription: docstrings.SchemaValidationEnabled(),
+			},
+
 			"response_export_values": schema.DynamicAttribute{
 				Optional: true,
 				PlanModifiers: []planmodifier.Dynamic{
@@ -213,6 +230,15 @@ func (r *DataPlaneResource) Schema(ctx context.Context, request resource.SchemaR
 				MarkdownDescription: docstrings.Locks(),
 			},
 
+			"shared_locks": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(myvalidator.StringIsNotEmpty()),
+				},
+				MarkdownDescription: docstrings.SharedLocks(),
+			},
+
 			"output": schema.DynamicAttribute{
 				Computed:            true,
 				MarkdownDescription: docstrings.Output("azapi_data_plane_resource"),
@@ -308,6 +334,24 @@ func (r *DataPlaneResource) ModifyPlan(ctx context.Context, request resource.Mod
 		plan.Output = state.Output
 	}
 
+	if plan.SchemaValidationEnabled.ValueBool() && dynamic.IsFullyKnown(plan.Body) {
+		azureResourceType, apiVersion, err := utils.GetAzureResourceTypeApiVersion(config.Type.ValueString())
+		if err != nil {
+			response.Diagnostics.AddError("Invalid configuration", fmt.Sprintf(`The argument "type" is invalid: %s`, err.Error()))
+			return
+		}
+		resourceDef, _ := azure.GetDataPlaneResourceDefinition(azureResourceType, apiVersion)
+		body := make(map[string]interface{})
+		if err := unmarshalBody(plan.Body, &body); err != nil {
+			response.Diagnostics.AddError("Invalid body", fmt.Sprintf(`The argument "body" is invalid: %s`, err.Error()))
+			return
+		}
+		if err = dataPlaneSchemaValidation(azureResourceType, apiVersion, resourceDef, body); err != nil {
+			response.Diagnostics.AddError("Invalid configuration", err.Error())
+			return
+		}
+	}
+
 	response.Diagnostics.Append(response.Plan.Set(ctx, plan)...)
 
 	// Check if any paths in replace_triggers_refs have changed
@@ -386,6 +430,7 @@ func (r *DataPlaneResource) CreateUpdate(ctx context.Context, plan tfsdk.Plan, s
 	}
 
 	ctx = tflog.SetField(ctx, "resource_id", id.ID())
+	ctx = clients.WithOperation(ctx, id.ID())
 
 	isNewResource := state == nil || state.Raw.IsNull()
 
@@ -428,12 +473,12 @@ func (r *DataPlaneResource) CreateUpdate(ctx context.Context, plan tfsdk.Plan, s
 		diagnostics.AddError("Invalid body", fmt.Sprintf(`The argument "body" is invalid: %s`, err.Error()))
 		return
 	}
-	lockIds := AsStringList(model.Locks)
-	slices.Sort(lockIds)
-	for _, lockId := range lockIds {
-		locks.ByID(lockId)
-		defer locks.UnlockByID(lockId)
+	unlock, err := locks.Acquire(ctx, id.ID(), AsStringList(model.Locks), AsStringList(model.SharedLocks))
+	if err != nil {
+		diagnostics.AddError("Failed to acquire locks", err.Error())
+		return
 	}
+	defer unlock()
 
 	_, err = client.CreateOrUpdateThenPoll(ctx, id, body, clients.NewRequestOptions(AsMapOfString(model.CreateHeaders), AsMapOfLists(model.CreateQueryParameters)))
 	if err != nil {
@@ -458,7 +503,12 @@ func (r *DataPlaneResource) CreateUpdate(ctx context.Context, plan tfsdk.Plan, s
 
 	model.ID = basetypes.NewStringValue(id.ID())
 
-	output, err := buildOutputFromBody(responseBody, model.ResponseExportValues, nil)
+	var defaultOutput interface{}
+	if !r.ProviderData.Features.DisableDefaultOutput {
+		defaultOutput = id.ResourceDef.GetReadOnly(responseBody)
+		defaultOutput = utils.RemoveFields(defaultOutput, volatileFieldList())
+	}
+	output, err := buildOutputFromBody(responseBody, model.ResponseExportValues, defaultOutput)
 	if err != nil {
 		diagnostics.AddError("Failed to build output", err.Error())
 		return
@@ -489,6 +539,7 @@ func (r *DataPlaneResource) Read(ctx context.Context, request resource.ReadReque
 		return
 	}
 	ctx = tflog.SetField(ctx, "resource_id", id.ID())
+	ctx = clients.WithOperation(ctx, id.ID())
 
 	// Ensure that the context deadline has been set before calling ConfigureClientWithCustomRetry().
 	client := r.ProviderData.DataPlaneClient.ConfigureClientWithCustomRetry(ctx, model.Retry, false)
@@ -513,6 +564,7 @@ func (r *DataPlaneResource) Read(ctx context.Context, request resource.ReadReque
 	option := utils.UpdateJsonOption{
 		IgnoreCasing:          model.IgnoreCasing.ValueBool(),
 		IgnoreMissingProperty: model.IgnoreMissingProperty.ValueBool(),
+		ArrayItemKeys:         arrayItemKeys(id.AzureResourceType, id.ResourceDef, requestBody, model.ArrayItemKeys),
 	}
 	body := utils.UpdateObject(requestBody, responseBody, option)
 
@@ -522,7 +574,12 @@ func (r *DataPlaneResource) Read(ctx context.Context, request resource.ReadReque
 		return
 	}
 
-	output, err := buildOutputFromBody(responseBody, model.ResponseExportValues, nil)
+	var defaultOutput interface{}
+	if !r.ProviderData.Features.DisableDefaultOutput {
+		defaultOutput = id.ResourceDef.GetReadOnly(responseBody)
+		defaultOutput = utils.RemoveFields(defaultOutput, volatileFieldList())
+	}
+	output, err := buildOutputFromBody(responseBody, model.ResponseExportValues, defaultOutput)
 	if err != nil {
 		response.Diagnostics.AddError("Failed to build output", err.Error())
 		return
@@ -571,19 +628,133 @@ func (r *DataPlaneResource) Delete(ctx context.Context, request resource.DeleteR
 	}
 
 	ctx = tflog.SetField(ctx, "resource_id", id.ID())
+	ctx = clients.WithOperation(ctx, id.ID())
 
 	// Ensure the context deadline has been set before calling ConfigureClientWithCustomRetry().
 	client := r.ProviderData.DataPlaneClient.ConfigureClientWithCustomRetry(ctx, model.Retry, false)
 
-	lockIds := AsStringList(model.Locks)
-	slices.Sort(lockIds)
-	for _, lockId := range lockIds {
-		locks.ByID(lockId)
-		defer locks.UnlockByID(lockId)
+	unlock, err := locks.Acquire(ctx, id.ID(), AsStringList(model.Locks), AsStringList(model.SharedLocks))
+	if err != nil {
+		response.Diagnostics.AddError("Failed to acquire locks", err.Error())
+		return
 	}
+	defer unlock()
 
 	_, err = client.DeleteThenPoll(ctx, id, clients.NewRequestOptions(AsMapOfString(model.DeleteHeaders), AsMapOfLists(model.DeleteQueryParameters)))
 	if err != nil && !utils.ResponseErrorWasNotFound(err) {
 		response.Diagnostics.AddError("Failed to delete resource", fmt.Errorf("deleting %s: %+v", id, err).Error())
 	}
 }
+
+func (r *DataPlaneResource) ImportState(ctx context.Context, request resource.ImportStateRequest, response *resource.ImportStateResponse) {
+	tflog.Debug(ctx, fmt.Sprintf("Importing Resource - parsing %q", request.ID))
+
+	id, err := parseDataPlaneImportId(request.ID)
+	if err != nil {
+		response.Diagnostics.AddError("Invalid Import ID", err.Error())
+		return
+	}
+
+	client := r.ProviderData.DataPlaneClient
+
+	state := r.defaultDataPlaneResourceModel()
+	state.ID = types.StringValue(id.ID())
+	state.Name = types.StringValue(id.Name)
+	state.ParentID = types.StringValue(id.ParentId)
+	state.Type = types.StringValue(fmt.Sprintf("%s@%s", id.AzureResourceType, id.ApiVersion))
+
+	responseBody, err := client.Get(ctx, id, clients.NewRequestOptions(AsMapOfString(state.ReadHeaders), AsMapOfLists(state.ReadQueryParameters)))
+	if err != nil {
+		if utils.ResponseErrorWasNotFound(err) {
+			tflog.Info(ctx, fmt.Sprintf("[INFO] Error reading %q - removing from state", id.ID()))
+			response.State.RemoveResource(ctx)
+			return
+		}
+		response.Diagnostics.AddError("Failed to retrieve resource", fmt.Errorf("reading %s: %+v", id, err).Error())
+		return
+	}
+
+	tflog.Info(ctx, fmt.Sprintf("resource %q is imported", id.ID()))
+	payload, err := flattenDataPlaneBody(responseBody, id.ResourceDef)
+	if err != nil {
+		response.Diagnostics.AddError("Invalid body", err.Error())
+		return
+	}
+	state.Body = payload
+
+	var defaultOutput interface{}
+	if !r.ProviderData.Features.DisableDefaultOutput {
+		defaultOutput = id.ResourceDef.GetReadOnly(responseBody)
+		defaultOutput = utils.RemoveFields(defaultOutput, volatileFieldList())
+	}
+	output, err := buildOutputFromBody(responseBody, state.ResponseExportValues, defaultOutput)
+	if err != nil {
+		response.Diagnostics.AddError("Failed to build output", err.Error())
+		return
+	}
+	state.Output = output
+
+	response.Diagnostics.Append(response.State.Set(ctx, state)...)
+}
+
+func (r *DataPlaneResource) defaultDataPlaneResourceModel() DataPlaneResourceModel {
+	return DataPlaneResourceModel{
+		ID:                            types.StringNull(),
+		Name:                          types.StringNull(),
+		ParentID:                      types.StringNull(),
+		Type:                          types.StringNull(),
+		Body:                          types.Dynamic{},
+		IgnoreCasing:                  types.BoolValue(false),
+		IgnoreMissingProperty:         types.BoolValue(true),
+		ArrayItemKeys:                 types.MapNull(types.StringType),
+		ReplaceTriggersExternalValues: types.DynamicNull(),
+		ReplaceTriggersRefs:           types.ListNull(types.StringType),
+		ResponseExportValues:          types.DynamicNull(),
+		Retry:                         retry.RetryValue{},
+		Locks:                         types.ListNull(types.StringType),
+		SharedLocks:                   types.ListNull(types.StringType),
+		Output:                        types.DynamicNull(),
+		Timeouts: timeouts.Value{
+			Object: types.ObjectNull(map[string]attr.Type{
+				"create": types.StringType,
+				"update": types.StringType,
+				"read":   types.StringType,
+				"delete": types.StringType,
+			}),
+		},
+		CreateHeaders:           types.MapNull(types.StringType),
+		CreateQueryParameters:   types.MapNull(types.ListType{ElemType: types.StringType}),
+		UpdateHeaders:           types.MapNull(types.StringType),
+		UpdateQueryParameters:   types.MapNull(types.ListType{ElemType: types.StringType}),
+		DeleteHeaders:           types.MapNull(types.StringType),
+		DeleteQueryParameters:   types.MapNull(types.ListType{ElemType: types.StringType}),
+		ReadHeaders:             types.MapNull(types.StringType),
+		ReadQueryParameters:     types.MapNull(types.ListType{ElemType: types.StringType}),
+		SchemaValidationEnabled: types.BoolValue(true),
+	}
+}
+
+// parseDataPlaneImportId parses the import ID of the data plane resources, it's in a format like `<id>?type=<resource-type>&api-version=<api-version>`.
+// The latest api-version is used if it's not specified.
+func parseDataPlaneImportId(input string) (parse.DataPlaneResourceId, error) {
+	importId, err := parse.ParseImportId(input)
+	if err != nil {
+		return parse.DataPlaneResourceId{}, fmt.Errorf("parsing Import ID %q: %+v", input, err)
+	}
+	if importId.ResourceType == "" {
+		return parse.DataPlaneResourceId{}, fmt.Errorf("the resource type is not specified in the Import ID %q, it should be like `<id>?type=<resource-type>&api-version=<api-version>`", input)
+	}
+	if importId.ApiVersion == "" {
+		apiVersions := azure.GetDataPlaneApiVersions(importId.ResourceType)
+		if len(apiVersions) == 0 {
+			return parse.DataPlaneResourceId{}, fmt.Errorf("the api-version is not specified in the Import ID %q and no api-version is found for the resource type %q", input, importId.ResourceType)
+		}
+		importId.ApiVersion = apiVersions[len(apiVersions)-1]
+	}
+
+	id, err := parse.DataPlaneResourceIDWithResourceType(importId.Id, importId.TypeWithApiVersion())
+	if err != nil {
+		return parse.DataPlaneResourceId{}, fmt.Errorf("parsing Resource ID %q: %+v", importId.Id, err)
+	}
+	return id, nil
+}
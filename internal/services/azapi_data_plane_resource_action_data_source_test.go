@@ -0,0 +1,42 @@
+package services_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Azure/terraform-provider-azapi/internal/acceptance"
+	"github.com/Azure/terraform-provider-azapi/internal/acceptance/check"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+type DataPlaneActionDataSource struct{}
+
+func TestAccDataPlaneActionDataSource_keyVaultSecretVersions(t *testing.T) {
+	data := acceptance.BuildTestData(t, "data.azapi_data_plane_resource_action", "test")
+	r := DataPlaneActionDataSource{}
+
+	data.DataSourceTest(t, []resource.TestStep{
+		{
+			Config:            r.keyVaultSecretVersions(data),
+			ExternalProviders: externalProvidersAzurerm(),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).Key("output.value.#").HasValue("1"),
+			),
+		},
+	})
+}
+
+func (r DataPlaneActionDataSource) keyVaultSecretVersions(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+%s
+
+data "azapi_data_plane_resource_action" "test" {
+  type                   = "Microsoft.KeyVault/vaults/secrets@7.4"
+  parent_id              = replace(azurerm_key_vault.example.vault_uri, "https://", "")
+  name                   = azurerm_key_vault_secret.example.name
+  action                 = "versions"
+  method                 = "GET"
+  response_export_values = ["value"]
+}
+`, DataPlaneActionResource{}.keyVaultTemplate(data))
+}
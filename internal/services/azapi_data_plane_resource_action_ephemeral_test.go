@@ -0,0 +1,38 @@
+package services_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Azure/terraform-provider-azapi/internal/acceptance"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+type DataPlaneActionEphemeral struct{}
+
+func TestAccDataPlaneActionEphemeral_keyVaultKeyBackup(t *testing.T) {
+	data := acceptance.BuildTestData(t, "ephemeral.azapi_data_plane_resource_action", "test")
+	r := DataPlaneActionEphemeral{}
+
+	data.DataSourceTest(t, []resource.TestStep{
+		{
+			Config:            r.keyVaultKeyBackup(data),
+			ExternalProviders: externalProvidersAzurerm(),
+			Check:             resource.ComposeTestCheckFunc(),
+		},
+	})
+}
+
+func (r DataPlaneActionEphemeral) keyVaultKeyBackup(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+%s
+
+ephemeral "azapi_data_plane_resource_action" "test" {
+  type                   = "Microsoft.KeyVault/vaults/keys@7.4"
+  parent_id              = replace(azurerm_key_vault.example.vault_uri, "https://", "")
+  name                   = azurerm_key_vault_key.example.name
+  action                 = "backup"
+  response_export_values = ["value"]
+}
+`, DataPlaneActionResource{}.keyVaultTemplate(data))
+}
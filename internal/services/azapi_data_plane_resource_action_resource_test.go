@@ -0,0 +1,125 @@
+package services_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Azure/terraform-provider-azapi/internal/acceptance"
+	"github.com/Azure/terraform-provider-azapi/internal/acceptance/check"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+type DataPlaneActionResource struct{}
+
+func TestAccDataPlaneActionResource_keyVaultKeyRotate(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azapi_data_plane_resource_action", "test")
+	r := DataPlaneActionResource{}
+
+	data.DataSourceTest(t, []resource.TestStep{
+		{
+			Config:            r.keyVaultKeyRotate(data),
+			ExternalProviders: externalProvidersAzurerm(),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).Key("output.key.kid").Exists(),
+			),
+		},
+	})
+}
+
+func TestAccDataPlaneActionResource_keyVaultKeyBackupWhenDestroy(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azapi_data_plane_resource_action", "test")
+	r := DataPlaneActionResource{}
+
+	data.DataSourceTest(t, []resource.TestStep{
+		{
+			Config:            r.keyVaultKeyBackupWhenDestroy(data),
+			ExternalProviders: externalProvidersAzurerm(),
+			Check:             resource.ComposeTestCheckFunc(),
+		},
+		{
+			Destroy:           true,
+			Config:            r.keyVaultKeyBackupWhenDestroy(data),
+			ExternalProviders: externalProvidersAzurerm(),
+			Check:             resource.ComposeTestCheckFunc(),
+		},
+	})
+}
+
+func (r DataPlaneActionResource) keyVaultKeyRotate(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+%s
+
+resource "azapi_data_plane_resource_action" "test" {
+  type                   = "Microsoft.KeyVault/vaults/keys@7.4"
+  parent_id              = replace(azurerm_key_vault.example.vault_uri, "https://", "")
+  name                   = azurerm_key_vault_key.example.name
+  action                 = "rotate"
+  response_export_values = ["key.kid"]
+}
+`, r.keyVaultTemplate(data))
+}
+
+func (r DataPlaneActionResource) keyVaultKeyBackupWhenDestroy(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+%s
+
+resource "azapi_data_plane_resource_action" "test" {
+  type      = "Microsoft.KeyVault/vaults/keys@7.4"
+  parent_id = replace(azurerm_key_vault.example.vault_uri, "https://", "")
+  name      = azurerm_key_vault_key.example.name
+  action    = "backup"
+  when      = "destroy"
+}
+`, r.keyVaultTemplate(data))
+}
+
+func (r DataPlaneActionResource) keyVaultTemplate(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+provider "azurerm" {
+  features {
+    key_vault {
+      purge_soft_delete_on_destroy       = false
+      purge_soft_deleted_keys_on_destroy = false
+    }
+  }
+}
+
+data "azurerm_client_config" "current" {}
+
+resource "azurerm_resource_group" "example" {
+  name     = "acctest%[2]s"
+  location = "%[1]s"
+}
+
+resource "azurerm_key_vault" "example" {
+  name                       = "acctest%[2]s"
+  location                   = azurerm_resource_group.example.location
+  resource_group_name        = azurerm_resource_group.example.name
+  tenant_id                  = data.azurerm_client_config.current.tenant_id
+  soft_delete_retention_days = 7
+  purge_protection_enabled   = false
+  sku_name                   = "standard"
+
+  access_policy {
+    tenant_id          = data.azurerm_client_config.current.tenant_id
+    object_id          = data.azurerm_client_config.current.object_id
+    key_permissions    = ["Backup", "Create", "Delete", "Get", "Purge", "Recover", "Rotate", "GetRotationPolicy"]
+    secret_permissions = ["Delete", "Get", "List", "Purge", "Set"]
+  }
+}
+
+resource "azurerm_key_vault_key" "example" {
+  name         = "acctest%[2]s"
+  key_vault_id = azurerm_key_vault.example.id
+  key_type     = "RSA"
+  key_size     = 2048
+  key_opts     = ["decrypt", "encrypt", "sign", "unwrapKey", "verify", "wrapKey"]
+}
+
+resource "azurerm_key_vault_secret" "example" {
+  name         = "acctest%[2]s"
+  value        = "secret"
+  key_vault_id = azurerm_key_vault.example.id
+}
+`, data.LocationPrimary, data.RandomString)
+}
@@ -0,0 +1,41 @@
+package services_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Azure/terraform-provider-azapi/internal/acceptance"
+	"github.com/Azure/terraform-provider-azapi/internal/acceptance/check"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+type DataPlaneResourceListDataSource struct{}
+
+func TestAccDataPlaneResourceListDataSource_appConfigKeyValues(t *testing.T) {
+	data := acceptance.BuildTestData(t, "data.azapi_data_plane_resource_list", "test")
+	r := DataPlaneResourceListDataSource{}
+
+	data.DataSourceTest(t, []resource.TestStep{
+		{
+			Config:            r.appConfigKeyValues(data),
+			ExternalProviders: externalProvidersAzurerm(),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).Key("output.value.#").HasValue("1"),
+			),
+		},
+	})
+}
+
+func (r DataPlaneResourceListDataSource) appConfigKeyValues(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+%s
+
+data "azapi_data_plane_resource_list" "test" {
+  type      = azapi_data_plane_resource.test.type
+  parent_id = azapi_data_plane_resource.test.parent_id
+  query_parameters = {
+    key = ["my*"]
+  }
+}
+`, DataPlaneResource{}.appConfigKeyValues(data))
+}
@@ -3,6 +3,7 @@ package services_test
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"testing"
 
 	"github.com/Azure/terraform-provider-azapi/internal/acceptance"
@@ -28,6 +29,14 @@ func TestAccDataPlaneResource_appConfigKeyValues(t *testing.T) {
 				check.That(data.ResourceName).ExistsInAzure(r),
 			),
 		},
+		{
+			ResourceName:            data.ResourceName,
+			ExternalProviders:       externalProvidersAzurerm(),
+			ImportState:             true,
+			ImportStateVerify:       true,
+			ImportStateIdFunc:       r.ImportIdFunc,
+			ImportStateVerifyIgnore: defaultIgnores(),
+		},
 	})
 }
 
@@ -149,6 +158,11 @@ func TestAccDataPlaneResource_replaceTriggeredByExternalValues(t *testing.T) {
 	})
 }
 
+func (DataPlaneResource) ImportIdFunc(tfState *terraform.State) (string, error) {
+	state := tfState.RootModule().Resources["azapi_data_plane_resource.test"].Primary
+	return fmt.Sprintf("%s?type=%s", state.ID, url.QueryEscape(state.Attributes["type"])), nil
+}
+
 func (DataPlaneResource) Exists(ctx context.Context, client *clients.Client, state *terraform.InstanceState) (*bool, error) {
 	resourceType := state.Attributes["type"]
 	id, err := parse.DataPlaneResourceIDWithResourceType(state.ID, resourceType)
@@ -0,0 +1,420 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Azure/terraform-provider-azapi/internal/clients"
+	"github.com/Azure/terraform-provider-azapi/internal/docstrings"
+	"github.com/Azure/terraform-provider-azapi/internal/retry"
+	"github.com/Azure/terraform-provider-azapi/internal/services/dynamic"
+	"github.com/Azure/terraform-provider-azapi/internal/services/myvalidator"
+	"github.com/Azure/terraform-provider-azapi/internal/services/parse"
+	"github.com/Azure/terraform-provider-azapi/internal/skip"
+	"github.com/Azure/terraform-provider-azapi/utils"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+type DataPlaneUpdateResourceModel struct {
+	ID                    types.String     `tfsdk:"id"`
+	Name                  types.String     `tfsdk:"name"`
+	ParentID              types.String     `tfsdk:"parent_id"`
+	Type                  types.String     `tfsdk:"type"`
+	Body                  types.Dynamic    `tfsdk:"body"`
+	IgnoreCasing          types.Bool       `tfsdk:"ignore_casing"`
+	IgnoreMissingProperty types.Bool       `tfsdk:"ignore_missing_property"`
+	ArrayItemKeys         types.Map        `tfsdk:"array_item_keys"`
+	ResponseExportValues  types.Dynamic    `tfsdk:"response_export_values"`
+	Locks                 types.List       `tfsdk:"locks"`
+	SharedLocks           types.List       `tfsdk:"shared_locks"`
+	Output                types.Dynamic    `tfsdk:"output"`
+	Timeouts              timeouts.Value   `tfsdk:"timeouts" skip_on:"update"`
+	Retry                 retry.RetryValue `tfsdk:"retry" skip_on:"update"`
+	UpdateHeaders         types.Map        `tfsdk:"update_headers"`
+	UpdateQueryParameters types.Map        `tfsdk:"update_query_parameters"`
+	ReadHeaders           types.Map        `tfsdk:"read_headers" skip_on:"update"`
+	ReadQueryParameters   types.Map        `tfsdk:"read_query_parameters" skip_on:"update"`
+	RestoreOnDestroy      types.Bool       `tfsdk:"restore_on_destroy" skip_on:"update"`
+	MergeStrategy         types.String     `tfsdk:"merge_strategy"`
+}
+
+type DataPlaneUpdateResource struct {
+	ProviderData *clients.Client
+}
+
+var _ resource.Resource = &DataPlaneUpdateResource{}
+var _ resource.ResourceWithConfigure = &DataPlaneUpdateResource{}
+var _ resource.ResourceWithModifyPlan = &DataPlaneUpdateResource{}
+var _ resource.ResourceWithImportState = &DataPlaneUpdateResource{}
+
+func (r *DataPlaneUpdateResource) Configure(ctx context.Context, request resource.ConfigureRequest, response *resource.ConfigureResponse) {
+	if v, ok := request.ProviderData.(*clients.Client); ok {
+		r.ProviderData = v
+	}
+}
+
+func (r *DataPlaneUpdateResource) Metadata(ctx context.Context, request resource.MetadataRequest, response *resource.MetadataResponse) {
+	response.TypeName = request.ProviderTypeName + "_data_plane_update_resource"
+}
+
+func (r *DataPlaneUpdateResource) Schema(ctx context.Context, request resource.SchemaRequest, response *resource.SchemaResponse) {
+	response.Schema = schema.Schema{
+		MarkdownDescription: "This resource can manage a subset of any existing Azure data plane resource's properties.\n\n" +
+			"-> **Note** This resource is used to add or modify properties on an existing data plane resource. When delete `azapi_data_plane_update_resource`, no operation will be performed, and these properties will stay unchanged, unless `restore_on_destroy` is enabled.",
+		Description: "This resource can manage a subset of any existing Azure data plane resource's properties.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				MarkdownDescription: docstrings.ID(),
+			},
+
+			"name": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				MarkdownDescription: "Specifies the name of the existing Azure data plane resource. Changing this forces a new resource to be created.",
+			},
+
+			"parent_id": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					myvalidator.StringIsNotEmpty(),
+				},
+				MarkdownDescription: "The ID of the azure resource in which the existing data plane resource is created. Changing this forces a new resource to be created.",
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+				Read:   true,
+				Delete: true,
+			}),
+		},
+	}
+	for name, attribute := range updateResourceAttributes(ctx, "azapi_data_plane_update_resource") {
+		response.Schema.Attributes[name] = attribute
+	}
+}
+
+func (r *DataPlaneUpdateResource) ModifyPlan(ctx context.Context, request resource.ModifyPlanRequest, response *resource.ModifyPlanResponse) {
+	var config, state, plan *DataPlaneUpdateResourceModel
+	response.Diagnostics.Append(request.Config.Get(ctx, &config)...)
+	response.Diagnostics.Append(request.State.Get(ctx, &state)...)
+	response.Diagnostics.Append(request.Plan.Get(ctx, &plan)...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	// destroy doesn't need to modify plan
+	if config == nil {
+		return
+	}
+
+	if state == nil || !plan.ResponseExportValues.Equal(state.ResponseExportValues) || !dynamic.SemanticallyEqual(plan.Body, state.Body) || !plan.Type.Equal(state.Type) {
+		plan.Output = basetypes.NewDynamicUnknown()
+	} else {
+		plan.Output = state.Output
+	}
+
+	response.Diagnostics.Append(response.Plan.Set(ctx, plan)...)
+}
+
+func (r *DataPlaneUpdateResource) Create(ctx context.Context, request resource.CreateRequest, response *resource.CreateResponse) {
+	r.CreateUpdate(ctx, request.Plan, &response.State, response.Private, &response.Diagnostics)
+}
+
+func (r *DataPlaneUpdateResource) Update(ctx context.Context, request resource.UpdateRequest, response *resource.UpdateResponse) {
+	// See if we can skip the external API call (changes are to state only)
+	var plan, state DataPlaneUpdateResourceModel
+	if response.Diagnostics.Append(request.Plan.Get(ctx, &plan)...); response.Diagnostics.HasError() {
+		return
+	}
+	if response.Diagnostics.Append(request.State.Get(ctx, &state)...); response.Diagnostics.HasError() {
+		return
+	}
+	if skip.CanSkipExternalRequest(plan, state, "update") {
+		tflog.Debug(ctx, "azapi_data_plane_update_resource.CreateUpdate skipping external request as no unskippable changes were detected")
+		response.Diagnostics.Append(response.State.Set(ctx, plan)...)
+		return
+	}
+	tflog.Debug(ctx, "azapi_data_plane_update_resource.CreateUpdate proceeding with external request as no skippable changes were detected")
+
+	r.CreateUpdate(ctx, request.Plan, &response.State, response.Private, &response.Diagnostics)
+}
+
+func (r *DataPlaneUpdateResource) CreateUpdate(ctx context.Context, plan tfsdk.Plan, state *tfsdk.State, private privateState, diagnostics *diag.Diagnostics) {
+	var model DataPlaneUpdateResourceModel
+	if diagnostics.Append(plan.Get(ctx, &model)...); diagnostics.HasError() {
+		return
+	}
+
+	isNewResource := state == nil || state.Raw.IsNull()
+
+	var timeout time.Duration
+	var diags diag.Diagnostics
+	if isNewResource {
+		timeout, diags = model.Timeouts.Create(ctx, 30*time.Minute)
+		if diagnostics.Append(diags...); diagnostics.HasError() {
+			return
+		}
+	} else {
+		timeout, diags = model.Timeouts.Update(ctx, 30*time.Minute)
+		if diagnostics.Append(diags...); diagnostics.HasError() {
+			return
+		}
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	id, err := parse.NewDataPlaneResourceId(model.Name.ValueString(), model.ParentID.ValueString(), model.Type.ValueString())
+	if err != nil {
+		diagnostics.AddError("Invalid configuration", err.Error())
+		return
+	}
+	if id.AzureResourceId == "" {
+		diagnostics.AddError("Invalid configuration", fmt.Sprintf("the resource type %q is not supported", id.AzureResourceType))
+		return
+	}
+
+	ctx = tflog.SetField(ctx, "resource_id", id.ID())
+	ctx = clients.WithOperation(ctx, id.ID())
+
+	// Ensure the context deadline has been set before calling ConfigureClientWithCustomRetry().
+	client := r.ProviderData.DataPlaneClient.ConfigureClientWithCustomRetry(ctx, model.Retry, false)
+
+	target := newDataPlaneUpdateTarget(client, id)
+	if diagnostics.Append(applyUpdate(ctx, target, model.updateOptions(), private, isNewResource)...); diagnostics.HasError() {
+		return
+	}
+
+	responseBody, err := client.Get(ctx, id, clients.NewRequestOptions(AsMapOfString(model.ReadHeaders), AsMapOfLists(model.ReadQueryParameters)))
+	if err != nil {
+		if utils.ResponseErrorWasNotFound(err) {
+			tflog.Info(ctx, fmt.Sprintf("Error reading %q - removing from state", id.ID()))
+			state.RemoveResource(ctx)
+			return
+		}
+		diagnostics.AddError("Failed to retrieve resource", fmt.Errorf("reading %s: %+v", id, err).Error())
+		return
+	}
+
+	model.ID = basetypes.NewStringValue(id.ID())
+
+	output, err := flattenUpdateOutput(target, responseBody, model.ResponseExportValues, r.ProviderData.Features)
+	if err != nil {
+		diagnostics.AddError("Failed to build output", err.Error())
+		return
+	}
+	model.Output = output
+
+	diagnostics.Append(state.Set(ctx, model)...)
+}
+
+func (r *DataPlaneUpdateResource) Read(ctx context.Context, request resource.ReadRequest, response *resource.ReadResponse) {
+	var model DataPlaneUpdateResourceModel
+	if response.Diagnostics.Append(request.State.Get(ctx, &model)...); response.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := model.Timeouts.Read(ctx, 5*time.Minute)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	id, err := parse.DataPlaneResourceIDWithResourceType(model.ID.ValueString(), model.Type.ValueString())
+	if err != nil {
+		response.Diagnostics.AddError("Error parsing ID", err.Error())
+		return
+	}
+
+	ctx = tflog.SetField(ctx, "resource_id", id.ID())
+	ctx = clients.WithOperation(ctx, id.ID())
+
+	// Ensure the context deadline has been set before calling ConfigureClientWithCustomRetry().
+	client := r.ProviderData.DataPlaneClient.ConfigureClientWithCustomRetry(ctx, model.Retry, false)
+
+	responseBody, err := client.Get(ctx, id, clients.NewRequestOptions(AsMapOfString(model.ReadHeaders), AsMapOfLists(model.ReadQueryParameters)))
+	if err != nil {
+		if utils.ResponseErrorWasNotFound(err) {
+			tflog.Info(ctx, fmt.Sprintf("[INFO] Error reading %q - removing from state", id.ID()))
+			response.State.RemoveResource(ctx)
+			return
+		}
+		response.Diagnostics.AddError("Failed to retrieve resource", fmt.Errorf("reading %s: %+v", id, err).Error())
+		return
+	}
+
+	state := model
+	state.Name = basetypes.NewStringValue(id.Name)
+	state.ParentID = basetypes.NewStringValue(id.ParentId)
+	state.Type = basetypes.NewStringValue(fmt.Sprintf("%s@%s", id.AzureResourceType, id.ApiVersion))
+
+	target := newDataPlaneUpdateTarget(client, id)
+	output, err := flattenUpdateOutput(target, responseBody, model.ResponseExportValues, r.ProviderData.Features)
+	if err != nil {
+		response.Diagnostics.AddError("Failed to build output", err.Error())
+		return
+	}
+	state.Output = output
+
+	body, diags := flattenUpdateBody(ctx, target, model.updateOptions(), responseBody)
+	if response.Diagnostics.Append(diags...); response.Diagnostics.HasError() {
+		return
+	}
+	state.Body = body
+
+	response.Diagnostics.Append(response.State.Set(ctx, state)...)
+}
+
+func (r *DataPlaneUpdateResource) Delete(ctx context.Context, request resource.DeleteRequest, response *resource.DeleteResponse) {
+	var model DataPlaneUpdateResourceModel
+	if response.Diagnostics.Append(request.State.Get(ctx, &model)...); response.Diagnostics.HasError() {
+		return
+	}
+
+	if !model.RestoreOnDestroy.ValueBool() {
+		return
+	}
+
+	deleteTimeout, diags := model.Timeouts.Delete(ctx, 30*time.Minute)
+	if response.Diagnostics.Append(diags...); response.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	id, err := parse.DataPlaneResourceIDWithResourceType(model.ID.ValueString(), model.Type.ValueString())
+	if err != nil {
+		response.Diagnostics.AddError("Error parsing ID", err.Error())
+		return
+	}
+
+	ctx = tflog.SetField(ctx, "resource_id", id.ID())
+	ctx = clients.WithOperation(ctx, id.ID())
+
+	// Ensure the context deadline has been set before calling ConfigureClientWithCustomRetry().
+	client := r.ProviderData.DataPlaneClient.ConfigureClientWithCustomRetry(ctx, model.Retry, false)
+
+	response.Diagnostics.Append(restoreUpdate(ctx, newDataPlaneUpdateTarget(client, id), model.updateOptions(), request.Private)...)
+}
+
+func (r *DataPlaneUpdateResource) ImportState(ctx context.Context, request resource.ImportStateRequest, response *resource.ImportStateResponse) {
+	tflog.Debug(ctx, fmt.Sprintf("Importing Resource - parsing %q", request.ID))
+
+	id, err := parseDataPlaneImportId(request.ID)
+	if err != nil {
+		response.Diagnostics.AddError("Invalid Import ID", err.Error())
+		return
+	}
+
+	client := r.ProviderData.DataPlaneClient
+
+	state := r.defaultDataPlaneUpdateResourceModel()
+	state.ID = types.StringValue(id.ID())
+	state.Name = types.StringValue(id.Name)
+	state.ParentID = types.StringValue(id.ParentId)
+	state.Type = types.StringValue(fmt.Sprintf("%s@%s", id.AzureResourceType, id.ApiVersion))
+
+	responseBody, err := client.Get(ctx, id, clients.NewRequestOptions(AsMapOfString(state.ReadHeaders), AsMapOfLists(state.ReadQueryParameters)))
+	if err != nil {
+		if utils.ResponseErrorWasNotFound(err) {
+			tflog.Info(ctx, fmt.Sprintf("[INFO] Error reading %q - removing from state", id.ID()))
+			response.State.RemoveResource(ctx)
+			return
+		}
+		response.Diagnostics.AddError("Failed to retrieve resource", fmt.Errorf("reading %s: %+v", id, err).Error())
+		return
+	}
+
+	tflog.Info(ctx, fmt.Sprintf("resource %q is imported", id.ID()))
+	payload, err := flattenDataPlaneBody(responseBody, id.ResourceDef)
+	if err != nil {
+		response.Diagnostics.AddError("Invalid body", err.Error())
+		return
+	}
+	state.Body = payload
+
+	output, err := flattenUpdateOutput(newDataPlaneUpdateTarget(client, id), responseBody, state.ResponseExportValues, r.ProviderData.Features)
+	if err != nil {
+		response.Diagnostics.AddError("Failed to build output", err.Error())
+		return
+	}
+	state.Output = output
+
+	response.Diagnostics.Append(response.State.Set(ctx, state)...)
+}
+
+func (r *DataPlaneUpdateResource) defaultDataPlaneUpdateResourceModel() DataPlaneUpdateResourceModel {
+	return DataPlaneUpdateResourceModel{
+		ID:                    types.StringNull(),
+		Name:                  types.StringNull(),
+		ParentID:              types.StringNull(),
+		Type:                  types.StringNull(),
+		Body:                  types.Dynamic{},
+		IgnoreCasing:          types.BoolValue(false),
+		IgnoreMissingProperty: types.BoolValue(true),
+		ArrayItemKeys:         types.MapNull(types.StringType),
+		ResponseExportValues:  types.DynamicNull(),
+		Locks:                 types.ListNull(types.StringType),
+		SharedLocks:           types.ListNull(types.StringType),
+		Output:                types.DynamicNull(),
+		Timeouts: timeouts.Value{
+			Object: types.ObjectNull(map[string]attr.Type{
+				"create": types.StringType,
+				"update": types.StringType,
+				"read":   types.StringType,
+				"delete": types.StringType,
+			}),
+		},
+		Retry:                 retry.RetryValue{},
+		UpdateHeaders:         types.MapNull(types.StringType),
+		UpdateQueryParameters: types.MapNull(types.ListType{ElemType: types.StringType}),
+		ReadHeaders:           types.MapNull(types.StringType),
+		ReadQueryParameters:   types.MapNull(types.ListType{ElemType: types.StringType}),
+		RestoreOnDestroy:      types.BoolValue(false),
+		MergeStrategy:         types.StringValue(utils.MergeStrategyDeep),
+	}
+}
+
+// updateOptions returns the arguments shared with azapi_update_resource.
+func (model DataPlaneUpdateResourceModel) updateOptions() updateOptions {
+	return updateOptions{
+		Body:                  model.Body,
+		IgnoreCasing:          model.IgnoreCasing.ValueBool(),
+		IgnoreMissingProperty: model.IgnoreMissingProperty.ValueBool(),
+		ArrayItemKeys:         model.ArrayItemKeys,
+		MergeStrategy:         model.MergeStrategy.ValueString(),
+		RestoreOnDestroy:      model.RestoreOnDestroy.ValueBool(),
+		ResponseExportValues:  model.ResponseExportValues,
+		Locks:                 AsStringList(model.Locks),
+		SharedLocks:           AsStringList(model.SharedLocks),
+		ReadOptions:           clients.NewRequestOptions(AsMapOfString(model.ReadHeaders), AsMapOfLists(model.ReadQueryParameters)),
+		UpdateOptions:         clients.NewRequestOptions(AsMapOfString(model.UpdateHeaders), AsMapOfLists(model.UpdateQueryParameters)),
+	}
+}
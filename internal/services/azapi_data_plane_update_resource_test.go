@@ -0,0 +1,179 @@
+package services_test
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/Azure/terraform-provider-azapi/internal/acceptance"
+	"github.com/Azure/terraform-provider-azapi/internal/acceptance/check"
+	"github.com/Azure/terraform-provider-azapi/internal/clients"
+	"github.com/Azure/terraform-provider-azapi/internal/services/parse"
+	"github.com/Azure/terraform-provider-azapi/utils"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+type DataPlaneUpdateResource struct{}
+
+func TestAccDataPlaneUpdateResource_appConfigKeyValues(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azapi_data_plane_update_resource", "test")
+	r := DataPlaneUpdateResource{}
+
+	data.ResourceTest(t, r, []resource.TestStep{
+		{
+			Config:            r.appConfigKeyValues(data),
+			ExternalProviders: externalProvidersAzurerm(),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+			),
+		},
+		{
+			ResourceName:            data.ResourceName,
+			ExternalProviders:       externalProvidersAzurerm(),
+			ImportState:             true,
+			ImportStateVerify:       true,
+			ImportStateIdFunc:       r.ImportIdFunc,
+			ImportStateVerifyIgnore: defaultIgnores(),
+		},
+	})
+}
+
+func TestAccDataPlaneUpdateResource_restoreOnDestroy(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azapi_data_plane_update_resource", "test")
+	r := DataPlaneUpdateResource{}
+
+	data.ResourceTest(t, r, []resource.TestStep{
+		{
+			Config:            r.restoreOnDestroy(data),
+			ExternalProviders: externalProvidersAzurerm(),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+			),
+		},
+		{
+			Config:            r.restoreOnDestroyRemoved(data),
+			ExternalProviders: externalProvidersAzurerm(),
+			Check: resource.ComposeTestCheckFunc(
+				check.That("data.azapi_data_plane_resource.test").Key("output.value").HasValue("myvalue"),
+			),
+		},
+	})
+}
+
+func (DataPlaneUpdateResource) ImportIdFunc(tfState *terraform.State) (string, error) {
+	state := tfState.RootModule().Resources["azapi_data_plane_update_resource.test"].Primary
+	return fmt.Sprintf("%s?type=%s", state.ID, url.QueryEscape(state.Attributes["type"])), nil
+}
+
+func (DataPlaneUpdateResource) Exists(ctx context.Context, client *clients.Client, state *terraform.InstanceState) (*bool, error) {
+	resourceType := state.Attributes["type"]
+	id, err := parse.DataPlaneResourceIDWithResourceType(state.ID, resourceType)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = client.DataPlaneClient.Get(ctx, id, clients.DefaultRequestOptions())
+	if err == nil {
+		b := true
+		return &b, nil
+	}
+	if utils.ResponseErrorWasNotFound(err) {
+		b := false
+		return &b, nil
+	}
+	return nil, fmt.Errorf("checking for presence of existing %s: %+v", id, err)
+}
+
+func (r DataPlaneUpdateResource) appConfigKeyValues(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+%s
+
+resource "azapi_data_plane_update_resource" "test" {
+  type      = azapi_data_plane_resource.test.type
+  parent_id = azapi_data_plane_resource.test.parent_id
+  name      = azapi_data_plane_resource.test.name
+  body = {
+    tags = {
+      environment = "test"
+    }
+  }
+}
+`, DataPlaneResource{}.appConfigKeyValues(data))
+}
+
+func (r DataPlaneUpdateResource) restoreOnDestroy(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+%s
+
+resource "azapi_data_plane_update_resource" "test" {
+  type               = azapi_data_plane_resource.test.type
+  parent_id          = azapi_data_plane_resource.test.parent_id
+  name               = azapi_data_plane_resource.test.name
+  restore_on_destroy = true
+  body = {
+    value = "updated"
+  }
+}
+`, r.appConfigKeyValuesIgnoreValue(data))
+}
+
+func (r DataPlaneUpdateResource) restoreOnDestroyRemoved(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+%s
+
+data "azapi_data_plane_resource" "test" {
+  type                   = azapi_data_plane_resource.test.type
+  parent_id              = azapi_data_plane_resource.test.parent_id
+  name                   = azapi_data_plane_resource.test.name
+  response_export_values = ["value"]
+}
+`, r.appConfigKeyValuesIgnoreValue(data))
+}
+
+func (r DataPlaneUpdateResource) appConfigKeyValuesIgnoreValue(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+provider "azurerm" {
+  features {}
+}
+
+resource "azurerm_resource_group" "example" {
+  name     = "acctest%[2]s"
+  location = "%[1]s"
+}
+
+resource "azurerm_app_configuration" "appconf" {
+  name                = "acctest%[2]s"
+  resource_group_name = azurerm_resource_group.example.name
+  location            = azurerm_resource_group.example.location
+  sku                 = "standard"
+}
+
+data "azurerm_client_config" "current" {}
+
+resource "azurerm_role_assignment" "test" {
+  scope                = azurerm_app_configuration.appconf.id
+  role_definition_name = "App Configuration Data Owner"
+  principal_id         = data.azurerm_client_config.current.object_id
+}
+
+resource "azapi_data_plane_resource" "test" {
+  type      = "Microsoft.AppConfiguration/configurationStores/keyValues@1.0"
+  parent_id = replace(azurerm_app_configuration.appconf.endpoint, "https://", "")
+  name      = "mykey"
+  body = {
+    content_type = ""
+    value        = "myvalue"
+  }
+
+  lifecycle {
+    ignore_changes = [body]
+  }
+
+  depends_on = [
+    azurerm_role_assignment.test,
+  ]
+}
+`, data.LocationPrimary, data.RandomString)
+}
@@ -5,7 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
-	"slices"
+	"regexp"
 	"strings"
 	"time"
 
@@ -29,6 +29,7 @@ import (
 	"github.com/Azure/terraform-provider-azapi/internal/services/preflight"
 	"github.com/Azure/terraform-provider-azapi/internal/skip"
 	"github.com/Azure/terraform-provider-azapi/internal/tf"
+	"github.com/Azure/terraform-provider-azapi/internal/tracing"
 	"github.com/Azure/terraform-provider-azapi/utils"
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
@@ -55,8 +56,12 @@ type AzapiResourceModel struct {
 	Identity                      types.List       `tfsdk:"identity"`
 	IgnoreCasing                  types.Bool       `tfsdk:"ignore_casing"`
 	IgnoreMissingProperty         types.Bool       `tfsdk:"ignore_missing_property"`
+	ArrayItemKeys                 types.Map        `tfsdk:"array_item_keys"`
 	Location                      types.String     `tfsdk:"location"`
 	Locks                         types.List       `tfsdk:"locks"`
+	SharedLocks                   types.List       `tfsdk:"shared_locks"`
+	AutoLockParent                types.Bool       `tfsdk:"auto_lock_parent" skip_on:"update"`
+	ManagementLockBehavior        types.String     `tfsdk:"management_lock_behavior" skip_on:"update"`
 	Name                          types.String     `tfsdk:"name"`
 	Output                        types.Dynamic    `tfsdk:"output"`
 	ParentID                      types.String     `tfsdk:"parent_id"`
@@ -225,6 +230,12 @@ func (r *AzapiResource) Schema(ctx context.Context, _ resource.SchemaRequest, re
 				MarkdownDescription: docstrings.IgnoreMissingProperty(),
 			},
 
+			"array_item_keys": schema.MapAttribute{
+				ElementType:         types.StringType,
+				Optional:            true,
+				MarkdownDescription: docstrings.ArrayItemKeys(),
+			},
+
 			"response_export_values": schema.DynamicAttribute{
 				Optional: true,
 				PlanModifiers: []planmodifier.Dynamic{
@@ -242,6 +253,28 @@ func (r *AzapiResource) Schema(ctx context.Context, _ resource.SchemaRequest, re
 				MarkdownDescription: docstrings.Locks(),
 			},
 
+			"auto_lock_parent": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: docstrings.AutoLockParent(),
+			},
+
+			"management_lock_behavior": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(locks.ManagementLockBehaviorNone, locks.ManagementLockBehaviorLiftAndRestore),
+				},
+				MarkdownDescription: docstrings.ManagementLockBehavior(),
+			},
+
+			"shared_locks": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(myvalidator.StringIsNotEmpty()),
+				},
+				MarkdownDescription: docstrings.SharedLocks(),
+			},
+
 			"schema_validation_enabled": schema.BoolAttribute{
 				Optional:            true,
 				Computed:            true,
@@ -423,6 +456,13 @@ func (r *AzapiResource) ModifyPlan(ctx context.Context, request resource.ModifyP
 		return
 	}
 
+	resourceId := ""
+	if state != nil {
+		resourceId = state.ID.ValueString()
+	}
+	ctx, span := tracing.Start(ctx, "azapi_resource.ModifyPlan", resourceId)
+	defer tracing.EndWithDiagnostics(span, &response.Diagnostics)
+
 	defer func() {
 		response.Plan.Set(ctx, plan)
 	}()
@@ -511,7 +551,9 @@ func (r *AzapiResource) ModifyPlan(ctx context.Context, request resource.ModifyP
 				return
 			}
 			body["name"] = plan.Name.ValueString()
+			_, validationSpan := tracing.Start(ctx, "azapi_resource.SchemaValidation", resourceId)
 			err = schemaValidation(azureResourceType, apiVersion, resourceDef, body)
+			tracing.End(validationSpan, err)
 			if err != nil {
 				response.Diagnostics.AddError("Invalid configuration", err.Error())
 				return
@@ -560,27 +602,184 @@ func (r *AzapiResource) ModifyPlan(ctx context.Context, request resource.ModifyP
 		}
 	}
 
-	if r.ProviderData.Features.EnablePreflight && isNewResource && preflight.IsSupported(plan.Type.ValueString(), plan.ParentID.ValueString()) {
-		parentId := plan.ParentID.ValueString()
-		if parentId == "" {
-			placeholder, err := preflight.ParentIdPlaceholder(resourceDef, r.ProviderData.Account.GetSubscriptionId())
-			if err != nil {
-				return
+	// the new resources and the updates of the body are validated
+	if r.ProviderData.Features.EnablePreflight && (isNewResource || !dynamic.SemanticallyEqual(plan.Body, state.Body)) && preflight.IsSupported(plan.Type.ValueString(), plan.ParentID.ValueString()) {
+		preflightCtx, preflightSpan := tracing.Start(ctx, "azapi_resource.Preflight", resourceId)
+		err = preflight.Validate(preflightCtx, r.ProviderData.ResourceClient, r.ProviderData.Account.GetSubscriptionId(), plan.Type.ValueString(), plan.ParentID.ValueString(), plan.Name.ValueString(), plan.Location.ValueString(), plan.Body, plan.Identity)
+		tracing.End(preflightSpan, err)
+		if err != nil {
+			response.Diagnostics.AddError("Preflight Validation: Invalid configuration", err.Error())
+			return
+		}
+	}
+
+	if r.ProviderData.Features.EnablePolicyCheck && (isNewResource || !dynamic.SemanticallyEqual(plan.Body, state.Body) || !plan.Location.Equal(state.Location) || !plan.Tags.Equal(state.Tags)) &&
+		dynamic.IsFullyKnown(plan.Body) && preflight.IsPolicyCheckSupported(plan.ParentID.ValueString()) {
+		response.Diagnostics.Append(r.checkPolicyRestrictions(ctx, plan)...)
+		if response.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if r.ProviderData.Features.EnableWhatIf && !isNewResource && len(response.RequiresReplace) == 0 && dynamic.IsFullyKnown(plan.Body) &&
+		!dynamic.SemanticallyEqual(plan.Body, state.Body) && preflight.IsWhatIfSupported(state.ID.ValueString()) {
+		response.Diagnostics.Append(r.whatIf(ctx, plan, state)...)
+	}
+}
+
+// checkPolicyRestrictions evaluates the Azure Policies for the planned resource, the non-compliance with the deny policies is returned as errors,
+// and the other restrictions are returned as warnings.
+func (r *AzapiResource) checkPolicyRestrictions(ctx context.Context, plan *AzapiResourceModel) diag.Diagnostics {
+	diags := diag.Diagnostics{}
+
+	body := make(map[string]interface{})
+	if err := unmarshalBody(plan.Body, &body); err != nil {
+		tflog.Warn(ctx, fmt.Sprintf("Skipping policy check for resource %s because the body is invalid: %v", plan.Type.ValueString(), err))
+		return diags
+	}
+	if diags = expandBody(body, *plan); diags.HasError() {
+		return diags
+	}
+
+	name := ""
+	if !plan.Name.IsUnknown() {
+		name = plan.Name.ValueString()
+	}
+	restrictions, err := preflight.CheckPolicyRestrictions(ctx, r.ProviderData.ResourceClient, plan.Type.ValueString(), plan.ParentID.ValueString(), name, body)
+	if err != nil {
+		diags.AddWarning("Policy Check: Unable to check the policy restrictions", fmt.Sprintf("checking the policy restrictions of %s: %+v", plan.Type.ValueString(), err))
+		return diags
+	}
+
+	for _, restriction := range restrictions {
+		attributePath := policyFieldPath(restriction.Field, plan)
+		if restriction.Denied {
+			diags.AddAttributeError(attributePath, "Policy Check: Request disallowed by policy", restriction.Message)
+		} else {
+			diags.AddAttributeWarning(attributePath, "Policy Check: Policy restriction", restriction.Message)
+		}
+	}
+	return diags
+}
+
+// policyFieldPath returns the path of the attribute which is restricted by the policy field.
+// The fields in the `body` are mapped to the nested paths, for example, `properties.minimumTlsVersion` is mapped to `body.properties.minimumTlsVersion`.
+func policyFieldPath(field string, model *AzapiResourceModel) path.Path {
+	segment, _, _ := strings.Cut(strings.ToLower(field), ".")
+	segment, _, _ = strings.Cut(segment, "[")
+	switch {
+	case segment == "name":
+		return path.Root("name")
+	case segment == "location" && !model.Location.IsNull():
+		return path.Root("location")
+	case segment == "tags" && !model.Tags.IsNull():
+		return path.Root("tags")
+	case segment == "identity" && !model.Identity.IsNull():
+		return path.Root("identity")
+	}
+
+	res := path.Root("body")
+	// the aliases like `Microsoft.Storage/storageAccounts/minimumTlsVersion` can't be mapped to the body
+	if strings.Contains(field, "/") {
+		return res
+	}
+	var body interface{}
+	if err := unmarshalBody(model.Body, &body); err != nil {
+		return res
+	}
+	// the path points to the deepest property in the body, the property names in the policy fields are case-insensitive
+	for _, name := range policyFieldSegments(field) {
+		bodyMap, ok := body.(map[string]interface{})
+		if !ok {
+			break
+		}
+		found := false
+		for key, value := range bodyMap {
+			if strings.EqualFold(key, name) {
+				res, body, found = res.AtName(key), value, true
+				break
 			}
-			parentId = placeholder
 		}
+		if !found {
+			break
+		}
+	}
+	return res
+}
 
-		name := plan.Name.ValueString()
-		if name == "" {
-			name = preflight.NamePlaceholder()
+var policyFieldIndexRegex = regexp.MustCompile(`^\[['"]?([^'"\]]*)['"]?\]$`)
+
+// policyFieldSegments splits the policy field into the property names, for example, `tags['environment']` is split into `tags` and `environment`.
+// The segments after an array index like `[*]` are dropped, because the index of the array item is unknown.
+func policyFieldSegments(field string) []string {
+	res := make([]string, 0)
+	for _, part := range strings.Split(field, ".") {
+		name, index, hasIndex := strings.Cut(part, "[")
+		res = append(res, name)
+		if !hasIndex {
+			continue
 		}
+		matches := policyFieldIndexRegex.FindStringSubmatch("[" + index)
+		if len(matches) != 2 || matches[1] == "*" || matches[1] == "" {
+			break
+		}
+		res = append(res, matches[1])
+	}
+	return res
+}
 
-		err = preflight.Validate(ctx, r.ProviderData.ResourceClient, plan.Type.ValueString(), parentId, name, plan.Location.ValueString(), plan.Body, plan.Identity)
-		if err != nil {
-			response.Diagnostics.AddError("Preflight Validation: Invalid configuration", err.Error())
-			return
+// whatIf previews the update of the resource with the deployment What-If operation, and returns the predicted changes as warnings.
+func (r *AzapiResource) whatIf(ctx context.Context, plan *AzapiResourceModel, state *AzapiResourceModel) diag.Diagnostics {
+	diags := diag.Diagnostics{}
+
+	body := make(map[string]interface{})
+	stateBody := make(map[string]interface{})
+	if err := unmarshalBody(plan.Body, &body); err != nil {
+		tflog.Warn(ctx, fmt.Sprintf("Skipping What-If preview for resource %s because the body is invalid: %v", state.ID.ValueString(), err))
+		return diags
+	}
+	if err := unmarshalBody(state.Body, &stateBody); err != nil {
+		tflog.Warn(ctx, fmt.Sprintf("Skipping What-If preview for resource %s because the body in the state is invalid: %v", state.ID.ValueString(), err))
+		return diags
+	}
+	if expandDiags := expandBody(body, *plan); expandDiags.HasError() {
+		tflog.Warn(ctx, fmt.Sprintf("Skipping What-If preview for resource %s because the body can't be expanded: %s", state.ID.ValueString(), expandDiags.Errors()[0].Detail()))
+		return diags
+	}
+	if expandDiags := expandBody(stateBody, *state); expandDiags.HasError() {
+		tflog.Warn(ctx, fmt.Sprintf("Skipping What-If preview for resource %s because the body in the state can't be expanded: %s", state.ID.ValueString(), expandDiags.Errors()[0].Detail()))
+		return diags
+	}
+
+	changes, err := preflight.WhatIf(ctx, r.ProviderData.ResourceClient, state.ID.ValueString(), plan.Type.ValueString(), body)
+	if err != nil {
+		diags.AddWarning("What-If: Unable to preview the changes", fmt.Sprintf("previewing the changes of %s: %+v", state.ID.ValueString(), err))
+		return diags
+	}
+	if len(changes) == 0 {
+		return diags
+	}
+
+	plannedPaths := preflight.ChangedPaths(stateBody, body)
+	planned := make([]string, 0)
+	unplanned := make([]string, 0)
+	for _, change := range changes {
+		if change.IsPlanned(plannedPaths) {
+			planned = append(planned, "  "+change.String())
+		} else {
+			unplanned = append(unplanned, "  "+change.String())
 		}
 	}
+
+	detail := fmt.Sprintf("The following changes of %s are predicted by the What-If operation:\n%s", state.ID.ValueString(), strings.Join(planned, "\n"))
+	if len(planned) == 0 {
+		detail = fmt.Sprintf("No changes of %s in the Terraform plan are predicted by the What-If operation.", state.ID.ValueString())
+	}
+	if len(unplanned) != 0 {
+		detail += fmt.Sprintf("\n\nThe following changes are not in the Terraform plan, they might be caused by the default values or the normalization of the resource provider:\n%s", strings.Join(unplanned, "\n"))
+	}
+	diags.AddWarning(fmt.Sprintf("What-If: %d predicted property changes", len(changes)), detail)
+	return diags
 }
 
 func (r *AzapiResource) Create(ctx context.Context, request resource.CreateRequest, response *resource.CreateResponse) {
@@ -620,9 +819,16 @@ func (r *AzapiResource) CreateUpdate(ctx context.Context, requestPlan tfsdk.Plan
 	}
 
 	ctx = tflog.SetField(ctx, "resource_id", id.ID())
+	ctx = clients.WithOperation(ctx, id.ID())
 
 	isNewResource := responseState == nil || responseState.Raw.IsNull()
 	ctx = tflog.SetField(ctx, "is_new_resource", isNewResource)
+	spanName := "azapi_resource.Update"
+	if isNewResource {
+		spanName = "azapi_resource.Create"
+	}
+	ctx, span := tracing.Start(ctx, spanName, id.ID())
+	defer tracing.EndWithDiagnostics(span, diagnostics)
 	var timeout time.Duration
 	var diags diag.Diagnostics
 	if isNewResource {
@@ -679,11 +885,24 @@ func (r *AzapiResource) CreateUpdate(ctx context.Context, requestPlan tfsdk.Plan
 	}
 
 	// create/update the resource
-	lockIds := AsStringList(plan.Locks)
-	slices.Sort(lockIds)
-	for _, lockId := range lockIds {
-		locks.ByID(lockId)
-		defer locks.UnlockByID(lockId)
+	unlock, err := locks.Acquire(ctx, id.ID(), exclusiveLockIds(plan.Locks, plan.AutoLockParent, r.ProviderData.Features, id.AzureResourceType, id.ParentId), AsStringList(plan.SharedLocks))
+	if err != nil {
+		diagnostics.AddError("Failed to acquire locks", err.Error())
+		return
+	}
+	defer unlock()
+
+	if !isNewResource && plan.ManagementLockBehavior.ValueString() == locks.ManagementLockBehaviorLiftAndRestore {
+		restore, err := locks.LiftManagementLocks(ctx, r.ProviderData.ResourceClient, id.ID(), id.AzureResourceId, false)
+		if err != nil {
+			diagnostics.AddError("Failed to lift management locks", err.Error())
+			return
+		}
+		defer func() {
+			if err := restore(nil); err != nil {
+				diagnostics.AddError("Failed to restore management locks", err.Error())
+			}
+		}()
 	}
 
 	options := clients.NewRequestOptions(AsMapOfString(plan.CreateHeaders), AsMapOfLists(plan.CreateQueryParameters))
@@ -799,6 +1018,9 @@ func (r *AzapiResource) Read(ctx context.Context, request resource.ReadRequest,
 	}
 
 	ctx = tflog.SetField(ctx, "resource_id", id.ID())
+	ctx = clients.WithOperation(ctx, id.ID())
+	ctx, span := tracing.Start(ctx, "azapi_resource.Read", id.ID())
+	defer tracing.EndWithDiagnostics(span, &response.Diagnostics)
 
 	client := r.ProviderData.ResourceClient.ConfigureClientWithCustomRetry(ctx, model.Retry, false)
 
@@ -870,6 +1092,7 @@ func (r *AzapiResource) Read(ctx context.Context, request resource.ReadRequest,
 	option := utils.UpdateJsonOption{
 		IgnoreCasing:          model.IgnoreCasing.ValueBool(),
 		IgnoreMissingProperty: model.IgnoreMissingProperty.ValueBool(),
+		ArrayItemKeys:         arrayItemKeys(id.AzureResourceType, id.ResourceDef, requestBody, model.ArrayItemKeys),
 	}
 	body := utils.UpdateObject(requestBody, responseBody, option)
 
@@ -928,6 +1151,9 @@ func (r *AzapiResource) Delete(ctx context.Context, request resource.DeleteReque
 	}
 
 	ctx = tflog.SetField(ctx, "resource_id", id.ID())
+	ctx = clients.WithOperation(ctx, id.ID())
+	ctx, span := tracing.Start(ctx, "azapi_resource.Delete", id.ID())
+	defer tracing.EndWithDiagnostics(span, &response.Diagnostics)
 
 	deleteTimeout, diags := model.Timeouts.Delete(ctx, 30*time.Minute)
 	response.Diagnostics.Append(diags...)
@@ -940,11 +1166,28 @@ func (r *AzapiResource) Delete(ctx context.Context, request resource.DeleteReque
 	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
 	defer cancel()
 
-	lockIds := AsStringList(model.Locks)
-	slices.Sort(lockIds)
-	for _, lockId := range lockIds {
-		locks.ByID(lockId)
-		defer locks.UnlockByID(lockId)
+	unlock, err := locks.Acquire(ctx, id.ID(), exclusiveLockIds(model.Locks, model.AutoLockParent, r.ProviderData.Features, id.AzureResourceType, id.ParentId), AsStringList(model.SharedLocks))
+	if err != nil {
+		response.Diagnostics.AddError("Failed to acquire locks", err.Error())
+		return
+	}
+	defer unlock()
+
+	if model.ManagementLockBehavior.ValueString() == locks.ManagementLockBehaviorLiftAndRestore {
+		restore, err := locks.LiftManagementLocks(ctx, r.ProviderData.ResourceClient, id.ID(), id.AzureResourceId, true)
+		if err != nil {
+			response.Diagnostics.AddError("Failed to lift management locks", err.Error())
+			return
+		}
+		defer func() {
+			// the locks at the scope of the deleted resource are deleted together with the resource, so they're not restored
+			keep := func(lock locks.ManagementLock) bool {
+				return response.Diagnostics.HasError() || !strings.EqualFold(locks.ManagementLockScope(lock.ID), id.AzureResourceId)
+			}
+			if err := restore(keep); err != nil {
+				response.Diagnostics.AddError("Failed to restore management locks", err.Error())
+			}
+		}()
 	}
 
 	_, err = client.Delete(ctx, id.AzureResourceId, id.ApiVersion, clients.NewRequestOptions(AsMapOfString(model.DeleteHeaders), AsMapOfLists(model.DeleteQueryParameters)))
@@ -1145,7 +1388,11 @@ func (r *AzapiResource) defaultAzapiResourceModel() AzapiResourceModel {
 		Identity:                      types.ListNull(identity.Model{}.ModelType()),
 		IgnoreCasing:                  types.BoolValue(false),
 		IgnoreMissingProperty:         types.BoolValue(true),
+		ArrayItemKeys:                 types.MapNull(types.StringType),
 		Locks:                         types.ListNull(types.StringType),
+		AutoLockParent:                types.BoolNull(),
+		ManagementLockBehavior:        types.StringNull(),
+		SharedLocks:                   types.ListNull(types.StringType),
 		Output:                        types.DynamicNull(),
 		ReplaceTriggersExternalValues: types.DynamicNull(),
 		ReplaceTriggersRefs:           types.ListNull(types.StringType),
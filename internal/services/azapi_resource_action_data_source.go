@@ -168,6 +168,7 @@ func (r *ResourceActionDataSource) Read(ctx context.Context, request datasource.
 	}
 
 	ctx = tflog.SetField(ctx, "resource_id", id.ID())
+	ctx = clients.WithOperation(ctx, id.ID())
 
 	var requestBody interface{}
 	if err := unmarshalBody(model.Body, &requestBody); err != nil {
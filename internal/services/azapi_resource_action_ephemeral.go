@@ -3,7 +3,6 @@ package services
 import (
 	"context"
 	"fmt"
-	"slices"
 	"time"
 
 	"github.com/Azure/terraform-provider-azapi/internal/clients"
@@ -31,6 +30,7 @@ type ActionEphemeralModel struct {
 	Method               types.String     `tfsdk:"method"`
 	Body                 types.Dynamic    `tfsdk:"body"`
 	Locks                types.List       `tfsdk:"locks"`
+	SharedLocks          types.List       `tfsdk:"shared_locks"`
 	ResponseExportValues types.Dynamic    `tfsdk:"response_export_values"`
 	Output               types.Dynamic    `tfsdk:"output"`
 	Timeouts             timeouts.Value   `tfsdk:"timeouts"`
@@ -113,6 +113,15 @@ func (r *ActionEphemeral) Schema(ctx context.Context, request ephemeral.SchemaRe
 				MarkdownDescription: docstrings.Locks(),
 			},
 
+			"shared_locks": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(myvalidator.StringIsNotEmpty()),
+				},
+				MarkdownDescription: docstrings.SharedLocks(),
+			},
+
 			"response_export_values": schema.DynamicAttribute{
 				Optional:            true,
 				MarkdownDescription: docstrings.ResponseExportValues(),
@@ -169,6 +178,7 @@ func (r *ActionEphemeral) Open(ctx context.Context, request ephemeral.OpenReques
 	}
 
 	ctx = tflog.SetField(ctx, "resource_id", id.ID())
+	ctx = clients.WithOperation(ctx, id.ID())
 
 	var requestBody interface{}
 	if err := unmarshalBody(model.Body, &requestBody); err != nil {
@@ -181,12 +191,12 @@ func (r *ActionEphemeral) Open(ctx context.Context, request ephemeral.OpenReques
 		method = "POST"
 	}
 
-	lockIds := AsStringList(model.Locks)
-	slices.Sort(lockIds)
-	for _, lockId := range lockIds {
-		locks.ByID(lockId)
-		defer locks.UnlockByID(lockId)
+	unlock, err := locks.Acquire(ctx, id.ID(), AsStringList(model.Locks), AsStringList(model.SharedLocks))
+	if err != nil {
+		response.Diagnostics.AddError("Failed to acquire locks", err.Error())
+		return
 	}
+	defer unlock()
 
 	// Ensure the context deadline has been set before calling ConfigureClientWithCustomRetry().
 	client := r.ProviderData.ResourceClient.ConfigureClientWithCustomRetry(ctx, model.Retry, false)
@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 	"slices"
+	"strings"
 	"time"
 
+	"github.com/Azure/terraform-provider-azapi/internal/azure"
 	"github.com/Azure/terraform-provider-azapi/internal/clients"
 	"github.com/Azure/terraform-provider-azapi/internal/docstrings"
 	"github.com/Azure/terraform-provider-azapi/internal/locks"
@@ -17,9 +19,11 @@ import (
 	"github.com/Azure/terraform-provider-azapi/internal/services/myvalidator"
 	"github.com/Azure/terraform-provider-azapi/internal/services/parse"
 	"github.com/Azure/terraform-provider-azapi/internal/skip"
+	"github.com/Azure/terraform-provider-azapi/utils"
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -41,6 +45,7 @@ type ActionResourceModel struct {
 	Body                          types.Dynamic    `tfsdk:"body"`
 	When                          types.String     `tfsdk:"when"`
 	Locks                         types.List       `tfsdk:"locks"`
+	SharedLocks                   types.List       `tfsdk:"shared_locks"`
 	ResponseExportValues          types.Dynamic    `tfsdk:"response_export_values"`
 	SensitiveResponseExportValues types.Dynamic    `tfsdk:"sensitive_response_export_values"`
 	Output                        types.Dynamic    `tfsdk:"output"`
@@ -59,6 +64,7 @@ var _ resource.Resource = &ActionResource{}
 var _ resource.ResourceWithConfigure = &ActionResource{}
 var _ resource.ResourceWithModifyPlan = &ActionResource{}
 var _ resource.ResourceWithUpgradeState = &ActionResource{}
+var _ resource.ResourceWithImportState = &ActionResource{}
 
 func (r *ActionResource) Configure(ctx context.Context, request resource.ConfigureRequest, response *resource.ConfigureResponse) {
 	if v, ok := request.ProviderData.(*clients.Client); ok {
@@ -159,6 +165,15 @@ func (r *ActionResource) Schema(ctx context.Context, request resource.SchemaRequ
 				MarkdownDescription: docstrings.Locks(),
 			},
 
+			"shared_locks": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(myvalidator.StringIsNotEmpty()),
+				},
+				MarkdownDescription: docstrings.SharedLocks(),
+			},
+
 			"response_export_values": schema.DynamicAttribute{
 				Optional: true,
 				PlanModifiers: []planmodifier.Dynamic{
@@ -349,6 +364,7 @@ func (r *ActionResource) Action(ctx context.Context, model ActionResourceModel,
 	}
 
 	ctx = tflog.SetField(ctx, "resource_id", id.ID())
+	ctx = clients.WithOperation(ctx, id.ID())
 
 	var requestBody interface{}
 	if err := unmarshalBody(model.Body, &requestBody); err != nil {
@@ -356,12 +372,12 @@ func (r *ActionResource) Action(ctx context.Context, model ActionResourceModel,
 		return
 	}
 
-	lockIds := AsStringList(model.Locks)
-	slices.Sort(lockIds)
-	for _, lockId := range lockIds {
-		locks.ByID(lockId)
-		defer locks.UnlockByID(lockId)
+	unlock, err := locks.Acquire(ctx, id.ID(), AsStringList(model.Locks), AsStringList(model.SharedLocks))
+	if err != nil {
+		diagnostics.AddError("Failed to acquire locks", err.Error())
+		return
 	}
+	defer unlock()
 
 	// Ensure the context deadline has been set before calling ConfigureClientWithCustomRetry().
 	client := r.ProviderData.ResourceClient.ConfigureClientWithCustomRetry(ctx, model.Retry, false)
@@ -394,3 +410,106 @@ func (r *ActionResource) Action(ctx context.Context, model ActionResourceModel,
 
 	diagnostics.Append(state.Set(ctx, model)...)
 }
+
+// ImportState imports an action resource, the import ID is in a format like `<resource-id>?type=<resource-type>&api-version=<api-version>&action=<action>&method=<method>`.
+// The response of an action can't be retrieved without invoking it again, so the `body` is only populated when the action updates the resource itself by using `PUT` or `PATCH` method.
+func (r *ActionResource) ImportState(ctx context.Context, request resource.ImportStateRequest, response *resource.ImportStateResponse) {
+	tflog.Debug(ctx, fmt.Sprintf("Importing Resource - parsing %q", request.ID))
+
+	importId, err := parse.ParseImportId(request.ID)
+	if err != nil {
+		response.Diagnostics.AddError("Invalid Import ID", fmt.Errorf("parsing Import ID %q: %+v", request.ID, err).Error())
+		return
+	}
+
+	resourceType := importId.ResourceType
+	if resourceType == "" {
+		resourceType = utils.GetResourceType(importId.Id)
+	}
+	if importId.ApiVersion == "" {
+		apiVersions := azure.GetApiVersions(resourceType)
+		if len(apiVersions) == 0 {
+			response.Diagnostics.AddError("Invalid Import ID", fmt.Sprintf("the api-version is not specified in the Import ID %q and no api-version is found for the resource type %q", request.ID, resourceType))
+			return
+		}
+		importId.ApiVersion = apiVersions[len(apiVersions)-1]
+	}
+
+	id, err := parse.ResourceIDWithResourceType(importId.Id, fmt.Sprintf("%s@%s", resourceType, importId.ApiVersion))
+	if err != nil {
+		response.Diagnostics.AddError("Invalid Resource ID", fmt.Errorf("parsing Resource ID %q: %+v", importId.Id, err).Error())
+		return
+	}
+
+	method := "POST"
+	if v := importId.Query.Get("method"); v != "" {
+		method = strings.ToUpper(v)
+	}
+	if !slices.Contains([]string{"POST", "PATCH", "PUT", "DELETE", "GET", "HEAD"}, method) {
+		response.Diagnostics.AddError("Invalid Import ID", fmt.Sprintf("the method %q is invalid, value must be one of: `POST`, `PATCH`, `PUT`, `DELETE`, `GET`, `HEAD`", method))
+		return
+	}
+	actionName := importId.Query.Get("action")
+
+	state := r.defaultActionResourceModel()
+	state.ID = types.StringValue(id.ID())
+	if actionName != "" {
+		state.ID = types.StringValue(fmt.Sprintf("%s/%s", id.ID(), actionName))
+		state.Action = types.StringValue(actionName)
+	}
+	state.Type = types.StringValue(fmt.Sprintf("%s@%s", id.AzureResourceType, id.ApiVersion))
+	state.ResourceId = types.StringValue(id.AzureResourceId)
+	state.Method = types.StringValue(method)
+
+	if actionName == "" && (method == "PUT" || method == "PATCH") {
+		client := r.ProviderData.ResourceClient
+		responseBody, err := client.Get(ctx, id.AzureResourceId, id.ApiVersion, clients.NewRequestOptions(AsMapOfString(state.Headers), AsMapOfLists(state.QueryParameters)))
+		if err != nil {
+			if utils.ResponseErrorWasNotFound(err) {
+				tflog.Info(ctx, fmt.Sprintf("[INFO] Error reading %q - removing from state", id.ID()))
+				response.State.RemoveResource(ctx)
+				return
+			}
+			response.Diagnostics.AddError("Failed to retrieve resource", fmt.Errorf("reading %s: %+v", id, err).Error())
+			return
+		}
+		payload, err := flattenBody(responseBody, id.ResourceDef)
+		if err != nil {
+			response.Diagnostics.AddError("Invalid body", err.Error())
+			return
+		}
+		state.Body = payload
+	}
+
+	tflog.Info(ctx, fmt.Sprintf("resource %q is imported", state.ID.ValueString()))
+	response.Diagnostics.Append(response.State.Set(ctx, state)...)
+}
+
+func (r *ActionResource) defaultActionResourceModel() ActionResourceModel {
+	return ActionResourceModel{
+		ID:                            types.StringNull(),
+		Type:                          types.StringNull(),
+		ResourceId:                    types.StringNull(),
+		Action:                        types.StringNull(),
+		Method:                        types.StringValue("POST"),
+		Body:                          types.DynamicNull(),
+		When:                          types.StringValue("apply"),
+		Locks:                         types.ListNull(types.StringType),
+		SharedLocks:                   types.ListNull(types.StringType),
+		ResponseExportValues:          types.DynamicNull(),
+		SensitiveResponseExportValues: types.DynamicNull(),
+		Output:                        types.DynamicNull(),
+		SensitiveOutput:               types.DynamicNull(),
+		Timeouts: timeouts.Value{
+			Object: types.ObjectNull(map[string]attr.Type{
+				"create": types.StringType,
+				"update": types.StringType,
+				"read":   types.StringType,
+				"delete": types.StringType,
+			}),
+		},
+		Retry:           retry.RetryValue{},
+		Headers:         types.MapNull(types.StringType),
+		QueryParameters: types.MapNull(types.ListType{ElemType: types.StringType}),
+	}
+}
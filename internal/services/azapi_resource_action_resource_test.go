@@ -3,6 +3,7 @@ package services_test
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"os"
 	"testing"
 
@@ -28,6 +29,7 @@ func TestAccActionResource_basic(t *testing.T) {
 			Config: r.basic(data),
 			Check:  resource.ComposeTestCheckFunc(),
 		},
+		data.ImportStepWithImportStateIdFunc(r.ImportIdFunc, "body", "output", "sensitive_output"),
 	})
 }
 
@@ -133,6 +135,11 @@ func TestAccActionResource_sensitiveOutput(t *testing.T) {
 	})
 }
 
+func (ActionResource) ImportIdFunc(tfState *terraform.State) (string, error) {
+	state := tfState.RootModule().Resources["azapi_resource_action.test"].Primary
+	return fmt.Sprintf("%s?type=%s&action=%s", state.Attributes["resource_id"], url.QueryEscape(state.Attributes["type"]), url.QueryEscape(state.Attributes["action"])), nil
+}
+
 func (r ActionResource) basic(data acceptance.TestData) string {
 	return fmt.Sprintf(`
 %[1]s
@@ -131,6 +131,7 @@ func (r *ResourceListDataSource) Read(ctx context.Context, request datasource.Re
 	}
 
 	ctx = tflog.SetField(ctx, "resource_id", id.ID())
+	ctx = clients.WithOperation(ctx, id.ID())
 
 	listUrl := strings.TrimSuffix(id.AzureResourceId, "/")
 
@@ -90,6 +90,36 @@ func TestAccGenericResource_preflightWithIdentity(t *testing.T) {
 	})
 }
 
+func TestAccGenericResource_preflightChildResourceValidation(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azapi_resource", "test")
+	r := GenericResource{}
+	data.ResourceTest(t, r, []resource.TestStep{
+		{
+			Config: r.preflightChildResource(data, "10.0.0.0/16", ""),
+		},
+		{
+			Config:      r.preflightChildResource(data, "10.0.0.0/16", "10.0.2.0/240"),
+			PlanOnly:    true,
+			ExpectError: regexp.MustCompile("InvalidAddressPrefixFormat"),
+		},
+	})
+}
+
+func TestAccGenericResource_preflightUpdateValidation(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azapi_resource", "test")
+	r := GenericResource{}
+	data.ResourceTest(t, r, []resource.TestStep{
+		{
+			Config: r.preflightChildResource(data, "10.0.0.0/16", ""),
+		},
+		{
+			Config:      r.preflightChildResource(data, "10.0.0.0/160", ""),
+			PlanOnly:    true,
+			ExpectError: regexp.MustCompile("InvalidAddressPrefixFormat"),
+		},
+	})
+}
+
 func (r GenericResource) preflightMockPropertyValue(data acceptance.TestData) string {
 	return fmt.Sprintf(`
 provider "azapi" {
@@ -206,3 +236,47 @@ resource "azapi_resource" "aksCluster" {
 }
 `, r.template(data))
 }
+
+func (r GenericResource) preflightChildResource(data acceptance.TestData, vnetAddressPrefix string, subnetAddressPrefix string) string {
+	subnet := ""
+	if subnetAddressPrefix != "" {
+		subnet = fmt.Sprintf(`
+resource "azapi_resource" "subnet" {
+  type      = "Microsoft.Network/virtualNetworks/subnets@2023-09-01"
+  parent_id = azapi_resource.virtualNetwork.id
+  name      = "acctest%[1]s"
+  body = {
+    properties = {
+      addressPrefix = "%[2]s"
+    }
+  }
+  schema_validation_enabled = false
+}
+`, data.RandomString, subnetAddressPrefix)
+	}
+	return fmt.Sprintf(`
+provider "azapi" {
+  enable_preflight = true
+}
+
+%[1]s
+
+resource "azapi_resource" "virtualNetwork" {
+  type      = "Microsoft.Network/virtualNetworks@2023-09-01"
+  parent_id = azapi_resource.resourceGroup.id
+  name      = "acctest%[2]s"
+  location  = azapi_resource.resourceGroup.location
+  body = {
+    properties = {
+      addressSpace = {
+        addressPrefixes = [
+          "%[3]s"
+        ]
+      }
+    }
+  }
+  schema_validation_enabled = false
+}
+%[4]s
+`, r.template(data), data.RandomString, vnetAddressPrefix, subnet)
+}
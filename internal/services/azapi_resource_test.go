@@ -386,6 +386,56 @@ func TestAccGenericResource_locks(t *testing.T) {
 	})
 }
 
+func TestAccGenericResource_sharedLocks(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azapi_resource", "test")
+	r := GenericResource{}
+
+	data.ResourceTest(t, r, []resource.TestStep{
+		{
+			Config: r.sharedLocks(data),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+			),
+		},
+		data.ImportStepWithImportStateIdFunc(r.ImportIdFunc, defaultIgnores()...),
+	})
+}
+
+func TestAccGenericResource_managementLockBehavior(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azapi_resource", "test")
+	r := GenericResource{}
+
+	data.ResourceTest(t, r, []resource.TestStep{
+		{
+			Config: r.managementLockBehavior(data, "first"),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+			),
+		},
+		{
+			Config: r.managementLockBehavior(data, "second"),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+			),
+		},
+	})
+}
+
+func TestAccGenericResource_autoLockParent(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azapi_resource", "test")
+	r := GenericResource{}
+
+	data.ResourceTest(t, r, []resource.TestStep{
+		{
+			Config: r.autoLockParent(data),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+			),
+		},
+		data.ImportStepWithImportStateIdFunc(r.ImportIdFunc, defaultIgnores()...),
+	})
+}
+
 func TestAccGenericResource_secretsInAsterisks(t *testing.T) {
 	data := acceptance.BuildTestData(t, "azapi_resource", "test")
 	clientId := os.Getenv("ARM_CLIENT_ID")
@@ -1469,6 +1519,143 @@ resource "azapi_resource" "test2" {
 `, r.template(data), data.RandomInteger, data.RandomString)
 }
 
+func (r GenericResource) sharedLocks(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+%[1]s
+
+resource "azapi_resource" "routeTable" {
+  type      = "Microsoft.Network/routeTables@2024-01-01"
+  parent_id = azapi_resource.resourceGroup.id
+  name      = "acctestrt%[2]d"
+  location  = azapi_resource.resourceGroup.location
+  body = {
+    properties = {
+      disableBgpRoutePropagation = false
+    }
+  }
+  lifecycle {
+    ignore_changes = [body.properties.routes]
+  }
+}
+
+resource "azapi_resource" "test" {
+  type      = "Microsoft.Network/routeTables/routes@2023-09-01"
+  name      = "first%[2]d"
+  parent_id = azapi_resource.routeTable.id
+  body = {
+    properties = {
+      nextHopType   = "VnetLocal"
+      addressPrefix = "10.1.0.0/16"
+    }
+  }
+
+  locks        = [azapi_resource.routeTable.id]
+  shared_locks = [azapi_resource.resourceGroup.id]
+}
+
+resource "azapi_resource" "test2" {
+  type      = "Microsoft.Network/routeTables/routes@2023-09-01"
+  name      = "second%[2]d"
+  parent_id = azapi_resource.routeTable.id
+  body = {
+    properties = {
+      nextHopType   = "VnetLocal"
+      addressPrefix = "10.3.0.0/16"
+    }
+  }
+
+  locks        = [azapi_resource.routeTable.id]
+  shared_locks = [azapi_resource.resourceGroup.id]
+}
+`, r.template(data), data.RandomInteger)
+}
+
+func (r GenericResource) managementLockBehavior(data acceptance.TestData, tag string) string {
+	return fmt.Sprintf(`
+%[1]s
+
+resource "azapi_resource" "test" {
+  type      = "Microsoft.Automation/automationAccounts@2023-11-01"
+  parent_id = azapi_resource.resourceGroup.id
+  name      = "acctest%[2]d"
+  location  = azapi_resource.resourceGroup.location
+  body = {
+    properties = {
+      sku = {
+        name = "Basic"
+      }
+    }
+  }
+  tags = {
+    key = "%[3]s"
+  }
+
+  management_lock_behavior = "lift_and_restore"
+}
+
+resource "azapi_resource" "lock" {
+  type      = "Microsoft.Authorization/locks@2020-05-01"
+  parent_id = azapi_resource.test.id
+  name      = "acctestlock%[2]d"
+  body = {
+    properties = {
+      level = "ReadOnly"
+      notes = "Managed by the acceptance test"
+    }
+  }
+}
+`, r.template(data), data.RandomInteger, tag)
+}
+
+func (r GenericResource) autoLockParent(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+%[1]s
+
+resource "azapi_resource" "virtualNetwork" {
+  type      = "Microsoft.Network/virtualNetworks@2024-01-01"
+  parent_id = azapi_resource.resourceGroup.id
+  name      = "acctestvnet%[2]d"
+  location  = azapi_resource.resourceGroup.location
+  body = {
+    properties = {
+      addressSpace = {
+        addressPrefixes = ["10.0.0.0/16"]
+      }
+    }
+  }
+  lifecycle {
+    ignore_changes = [body.properties.subnets]
+  }
+}
+
+resource "azapi_resource" "test" {
+  type      = "Microsoft.Network/virtualNetworks/subnets@2024-01-01"
+  parent_id = azapi_resource.virtualNetwork.id
+  name      = "first"
+  body = {
+    properties = {
+      addressPrefix = "10.0.1.0/24"
+    }
+  }
+
+  auto_lock_parent = true
+}
+
+resource "azapi_resource" "test2" {
+  type      = "Microsoft.Network/virtualNetworks/subnets@2024-01-01"
+  parent_id = azapi_resource.virtualNetwork.id
+  name      = "second"
+  body = {
+    properties = {
+      addressPrefix = "10.0.2.0/24"
+    }
+  }
+
+  auto_lock_parent = true
+}
+`, r.template(data), data.RandomInteger)
+}
+
 func (GenericResource) template(data acceptance.TestData) string {
 	return fmt.Sprintf(`
 resource "azapi_resource" "resourceGroup" {
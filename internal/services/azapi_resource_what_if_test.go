@@ -0,0 +1,55 @@
+package services_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Azure/terraform-provider-azapi/internal/acceptance"
+	"github.com/Azure/terraform-provider-azapi/internal/acceptance/check"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccGenericResource_whatIf(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azapi_resource", "test")
+	r := GenericResource{}
+	data.ResourceTest(t, r, []resource.TestStep{
+		{
+			Config: r.whatIf(data, "TLS1_0"),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+			),
+		},
+		{
+			// the predicted changes are shown as warnings, they don't block the plan
+			Config:             r.whatIf(data, "TLS1_2"),
+			PlanOnly:           true,
+			ExpectNonEmptyPlan: true,
+		},
+	})
+}
+
+func (r GenericResource) whatIf(data acceptance.TestData, minimumTlsVersion string) string {
+	return fmt.Sprintf(`
+provider "azapi" {
+  enable_what_if = true
+}
+
+%[1]s
+
+resource "azapi_resource" "test" {
+  type      = "Microsoft.Storage/storageAccounts@2023-05-01"
+  parent_id = azapi_resource.resourceGroup.id
+  name      = "acctest%[2]s"
+  location  = azapi_resource.resourceGroup.location
+  body = {
+    kind = "StorageV2"
+    properties = {
+      minimumTlsVersion = "%[3]s"
+    }
+    sku = {
+      name = "Standard_LRS"
+    }
+  }
+}
+`, r.template(data), data.RandomString, minimumTlsVersion)
+}
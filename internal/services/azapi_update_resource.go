@@ -2,25 +2,21 @@ package services
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"slices"
 	"time"
 
+	"github.com/Azure/terraform-provider-azapi/internal/azure"
 	"github.com/Azure/terraform-provider-azapi/internal/clients"
 	"github.com/Azure/terraform-provider-azapi/internal/docstrings"
-	"github.com/Azure/terraform-provider-azapi/internal/locks"
 	"github.com/Azure/terraform-provider-azapi/internal/retry"
-	"github.com/Azure/terraform-provider-azapi/internal/services/defaults"
 	"github.com/Azure/terraform-provider-azapi/internal/services/dynamic"
 	"github.com/Azure/terraform-provider-azapi/internal/services/migration"
-	"github.com/Azure/terraform-provider-azapi/internal/services/myplanmodifier"
 	"github.com/Azure/terraform-provider-azapi/internal/services/myvalidator"
 	"github.com/Azure/terraform-provider-azapi/internal/services/parse"
 	"github.com/Azure/terraform-provider-azapi/internal/skip"
 	"github.com/Azure/terraform-provider-azapi/utils"
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
-	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -42,8 +38,11 @@ type AzapiUpdateResourceModel struct {
 	Body                  types.Dynamic    `tfsdk:"body"`
 	IgnoreCasing          types.Bool       `tfsdk:"ignore_casing"`
 	IgnoreMissingProperty types.Bool       `tfsdk:"ignore_missing_property"`
+	ArrayItemKeys         types.Map        `tfsdk:"array_item_keys"`
 	ResponseExportValues  types.Dynamic    `tfsdk:"response_export_values"`
 	Locks                 types.List       `tfsdk:"locks"`
+	SharedLocks           types.List       `tfsdk:"shared_locks"`
+	AutoLockParent        types.Bool       `tfsdk:"auto_lock_parent" skip_on:"update"`
 	Output                types.Dynamic    `tfsdk:"output"`
 	Timeouts              timeouts.Value   `tfsdk:"timeouts" skip_on:"update"`
 	Retry                 retry.RetryValue `tfsdk:"retry" skip_on:"update"`
@@ -51,6 +50,8 @@ type AzapiUpdateResourceModel struct {
 	UpdateQueryParameters types.Map        `tfsdk:"update_query_parameters"`
 	ReadHeaders           types.Map        `tfsdk:"read_headers" skip_on:"update"`
 	ReadQueryParameters   types.Map        `tfsdk:"read_query_parameters" skip_on:"update"`
+	RestoreOnDestroy      types.Bool       `tfsdk:"restore_on_destroy" skip_on:"update"`
+	MergeStrategy         types.String     `tfsdk:"merge_strategy"`
 }
 
 type AzapiUpdateResource struct {
@@ -62,6 +63,7 @@ var _ resource.ResourceWithConfigure = &AzapiUpdateResource{}
 var _ resource.ResourceWithValidateConfig = &AzapiUpdateResource{}
 var _ resource.ResourceWithModifyPlan = &AzapiUpdateResource{}
 var _ resource.ResourceWithUpgradeState = &AzapiUpdateResource{}
+var _ resource.ResourceWithImportState = &AzapiUpdateResource{}
 
 func (r *AzapiUpdateResource) Configure(ctx context.Context, request resource.ConfigureRequest, response *resource.ConfigureResponse) {
 	if v, ok := request.ProviderData.(*clients.Client); ok {
@@ -83,7 +85,7 @@ func (r *AzapiUpdateResource) UpgradeState(ctx context.Context) map[int64]resour
 func (r *AzapiUpdateResource) Schema(ctx context.Context, request resource.SchemaRequest, response *resource.SchemaResponse) {
 	response.Schema = schema.Schema{
 		MarkdownDescription: "This resource can manage a subset of any existing Azure resource manager resource's properties.\n\n" +
-			"-> **Note** This resource is used to add or modify properties on an existing resource. When delete `azapi_update_resource`, no operation will be performed, and these properties will stay unchanged. If you want to restore the modified properties to some values, you must apply the restored properties before deleting.",
+			"-> **Note** This resource is used to add or modify properties on an existing resource. When delete `azapi_update_resource`, no operation will be performed, and these properties will stay unchanged, unless `restore_on_destroy` is enabled. If you want to restore the modified properties to some values, you must apply the restored properties before deleting, or enable `restore_on_destroy` to restore their original values.",
 		Description: "This resource can manage a subset of any existing Azure resource manager resource's properties.",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
@@ -133,90 +135,9 @@ func (r *AzapiUpdateResource) Schema(ctx context.Context, request resource.Schem
 				MarkdownDescription: "The ID of an existing Azure source.",
 			},
 
-			"type": schema.StringAttribute{
-				Required: true,
-				Validators: []validator.String{
-					myvalidator.StringIsResourceType(),
-				},
-				MarkdownDescription: docstrings.Type(),
-			},
-
-			// The body attribute is a dynamic attribute that only allows users to specify the resource body as an HCL object
-			"body": schema.DynamicAttribute{
-				Optional: true,
-				PlanModifiers: []planmodifier.Dynamic{
-					myplanmodifier.DynamicUseStateWhen(dynamic.SemanticallyEqual),
-				},
-				MarkdownDescription: docstrings.Body(),
-				Validators: []validator.Dynamic{
-					myvalidator.DynamicIsNotStringValidator(),
-				},
-			},
-
-			"ignore_casing": schema.BoolAttribute{
-				Optional:            true,
-				Computed:            true,
-				Default:             defaults.BoolDefault(false),
-				MarkdownDescription: docstrings.IgnoreCasing(),
-			},
-
-			"ignore_missing_property": schema.BoolAttribute{
-				Optional:            true,
-				Computed:            true,
-				Default:             defaults.BoolDefault(true),
-				MarkdownDescription: docstrings.IgnoreMissingProperty(),
-			},
-
-			"response_export_values": schema.DynamicAttribute{
-				Optional: true,
-				PlanModifiers: []planmodifier.Dynamic{
-					myplanmodifier.DynamicUseStateWhen(dynamic.SemanticallyEqual),
-				},
-				MarkdownDescription: docstrings.ResponseExportValues(),
-			},
-
-			"locks": schema.ListAttribute{
-				ElementType: types.StringType,
-				Optional:    true,
-				Validators: []validator.List{
-					listvalidator.ValueStringsAre(myvalidator.StringIsNotEmpty()),
-				},
-				MarkdownDescription: docstrings.Locks(),
-			},
-
-			"output": schema.DynamicAttribute{
-				Computed:            true,
-				MarkdownDescription: docstrings.Output("azapi_update_resource"),
-			},
-
-			"retry": retry.RetrySchema(ctx),
-
-			"update_headers": schema.MapAttribute{
-				ElementType:         types.StringType,
-				Optional:            true,
-				MarkdownDescription: "A mapping of headers to be sent with the update request.",
-			},
-
-			"update_query_parameters": schema.MapAttribute{
-				ElementType: types.ListType{
-					ElemType: types.StringType,
-				},
-				Optional:            true,
-				MarkdownDescription: "A mapping of query parameters to be sent with the update request.",
-			},
-
-			"read_headers": schema.MapAttribute{
-				ElementType:         types.StringType,
+			"auto_lock_parent": schema.BoolAttribute{
 				Optional:            true,
-				MarkdownDescription: "A mapping of headers to be sent with the read request.",
-			},
-
-			"read_query_parameters": schema.MapAttribute{
-				ElementType: types.ListType{
-					ElemType: types.StringType,
-				},
-				Optional:            true,
-				MarkdownDescription: "A mapping of query parameters to be sent with the read request.",
+				MarkdownDescription: docstrings.AutoLockParent(),
 			},
 		},
 
@@ -231,6 +152,9 @@ func (r *AzapiUpdateResource) Schema(ctx context.Context, request resource.Schem
 
 		Version: 2,
 	}
+	for name, attribute := range updateResourceAttributes(ctx, "azapi_update_resource") {
+		response.Schema.Attributes[name] = attribute
+	}
 }
 
 func (r *AzapiUpdateResource) ValidateConfig(ctx context.Context, request resource.ValidateConfigRequest, response *resource.ValidateConfigResponse) {
@@ -298,7 +222,7 @@ func (r *AzapiUpdateResource) ModifyPlan(ctx context.Context, request resource.M
 }
 
 func (r *AzapiUpdateResource) Create(ctx context.Context, request resource.CreateRequest, response *resource.CreateResponse) {
-	r.CreateUpdate(ctx, request.Plan, &response.State, &response.Diagnostics)
+	r.CreateUpdate(ctx, request.Plan, &response.State, response.Private, &response.Diagnostics)
 }
 
 func (r *AzapiUpdateResource) Update(ctx context.Context, request resource.UpdateRequest, response *resource.UpdateResponse) {
@@ -317,10 +241,10 @@ func (r *AzapiUpdateResource) Update(ctx context.Context, request resource.Updat
 	}
 	tflog.Debug(ctx, "azapi_resource.CreateUpdate proceeding with external request as no skippable changes were detected")
 
-	r.CreateUpdate(ctx, request.Plan, &response.State, &response.Diagnostics)
+	r.CreateUpdate(ctx, request.Plan, &response.State, response.Private, &response.Diagnostics)
 }
 
-func (r *AzapiUpdateResource) CreateUpdate(ctx context.Context, plan tfsdk.Plan, state *tfsdk.State, diagnostics *diag.Diagnostics) {
+func (r *AzapiUpdateResource) CreateUpdate(ctx context.Context, plan tfsdk.Plan, state *tfsdk.State, private privateState, diagnostics *diag.Diagnostics) {
 	var model AzapiUpdateResourceModel
 	if diagnostics.Append(plan.Get(ctx, &model)...); diagnostics.HasError() {
 		return
@@ -365,42 +289,14 @@ func (r *AzapiUpdateResource) CreateUpdate(ctx context.Context, plan tfsdk.Plan,
 	}
 
 	ctx = tflog.SetField(ctx, "resource_id", id.ID())
+	ctx = clients.WithOperation(ctx, id.ID())
 
 	// Ensure the context deadline has been set before calling ConfigureClientWithCustomRetry().
 	client := r.ProviderData.ResourceClient.ConfigureClientWithCustomRetry(ctx, model.Retry, false)
 
-	existing, err := client.Get(ctx, id.AzureResourceId, id.ApiVersion, clients.NewRequestOptions(AsMapOfString(model.ReadHeaders), AsMapOfLists(model.ReadQueryParameters)))
-	if err != nil {
-		diagnostics.AddError("Failed to retrieve resource", fmt.Errorf("checking for presence of existing %s: %+v", id, err).Error())
-		return
-	}
-	if utils.GetId(existing) == nil {
-		diagnostics.AddError("Failed to retrieve resource", fmt.Errorf("update target does not exist %s", id).Error())
-		return
-	}
-
-	var requestBody interface{}
-	if err := unmarshalBody(model.Body, &requestBody); err != nil {
-		diagnostics.AddError("Invalid body", fmt.Sprintf(`The argument "body" is invalid: err: %+v`, err))
-		return
-	}
-
-	requestBody = utils.MergeObject(existing, requestBody)
-
-	if id.ResourceDef != nil {
-		requestBody = (*id.ResourceDef).GetWriteOnly(utils.NormalizeObject(requestBody))
-	}
-
-	lockIds := AsStringList(model.Locks)
-	slices.Sort(lockIds)
-	for _, lockId := range lockIds {
-		locks.ByID(lockId)
-		defer locks.UnlockByID(lockId)
-	}
-
-	_, err = client.CreateOrUpdate(ctx, id.AzureResourceId, id.ApiVersion, requestBody, clients.NewRequestOptions(AsMapOfString(model.UpdateHeaders), AsMapOfLists(model.UpdateQueryParameters)))
-	if err != nil {
-		diagnostics.AddError("Failed to update resource", fmt.Errorf("updating %q: %+v", id, err).Error())
+	target := newResourceManagerUpdateTarget(client, id)
+	options := model.updateOptions(exclusiveLockIds(model.Locks, model.AutoLockParent, r.ProviderData.Features, id.AzureResourceType, id.ParentId))
+	if diagnostics.Append(applyUpdate(ctx, target, options, private, isNewResource)...); diagnostics.HasError() {
 		return
 	}
 
@@ -420,12 +316,7 @@ func (r *AzapiUpdateResource) CreateUpdate(ctx context.Context, plan tfsdk.Plan,
 	model.ParentID = basetypes.NewStringValue(id.ParentId)
 	model.ResourceID = basetypes.NewStringValue(id.AzureResourceId)
 
-	var defaultOutput interface{}
-	if !r.ProviderData.Features.DisableDefaultOutput {
-		defaultOutput = id.ResourceDef.GetReadOnly(responseBody)
-		defaultOutput = utils.RemoveFields(defaultOutput, volatileFieldList())
-	}
-	output, err := buildOutputFromBody(responseBody, model.ResponseExportValues, defaultOutput)
+	output, err := flattenUpdateOutput(target, responseBody, model.ResponseExportValues, r.ProviderData.Features)
 	if err != nil {
 		diagnostics.AddError("Failed to build output", err.Error())
 		return
@@ -457,6 +348,7 @@ func (r *AzapiUpdateResource) Read(ctx context.Context, request resource.ReadReq
 	}
 
 	ctx = tflog.SetField(ctx, "resource_id", id.ID())
+	ctx = clients.WithOperation(ctx, id.ID())
 
 	// Ensure the context deadline has been set before calling ConfigureClientWithCustomRetry().
 	client := r.ProviderData.ResourceClient.ConfigureClientWithCustomRetry(ctx, model.Retry, false)
@@ -479,52 +371,176 @@ func (r *AzapiUpdateResource) Read(ctx context.Context, request resource.ReadReq
 	state.ResourceID = basetypes.NewStringValue(id.AzureResourceId)
 	state.Type = basetypes.NewStringValue(fmt.Sprintf("%s@%s", id.AzureResourceType, id.ApiVersion))
 
-	requestBody := make(map[string]interface{})
-	if err := unmarshalBody(model.Body, &requestBody); err != nil {
-		response.Diagnostics.AddError("Invalid body", fmt.Sprintf(`The argument "body" is invalid: %s`, err.Error()))
+	target := newResourceManagerUpdateTarget(client, id)
+	output, err := flattenUpdateOutput(target, responseBody, model.ResponseExportValues, r.ProviderData.Features)
+	if err != nil {
+		response.Diagnostics.AddError("Failed to build output", err.Error())
 		return
 	}
+	state.Output = output
 
-	option := utils.UpdateJsonOption{
-		IgnoreCasing:          model.IgnoreCasing.ValueBool(),
-		IgnoreMissingProperty: model.IgnoreMissingProperty.ValueBool(),
+	body, diags := flattenUpdateBody(ctx, target, model.updateOptions(nil), responseBody)
+	if response.Diagnostics.Append(diags...); response.Diagnostics.HasError() {
+		return
 	}
-	body := utils.UpdateObject(requestBody, responseBody, option)
+	state.Body = body
 
-	data, err := json.Marshal(body)
-	if err != nil {
-		response.Diagnostics.AddError("Invalid body", err.Error())
+	response.Diagnostics.Append(response.State.Set(ctx, state)...)
+}
+
+func (r *AzapiUpdateResource) Delete(ctx context.Context, request resource.DeleteRequest, response *resource.DeleteResponse) {
+	var model AzapiUpdateResourceModel
+	if response.Diagnostics.Append(request.State.Get(ctx, &model)...); response.Diagnostics.HasError() {
+		return
+	}
+
+	if !model.RestoreOnDestroy.ValueBool() {
 		return
 	}
 
-	var defaultOutput interface{}
-	if !r.ProviderData.Features.DisableDefaultOutput {
-		defaultOutput = id.ResourceDef.GetReadOnly(responseBody)
-		defaultOutput = utils.RemoveFields(defaultOutput, volatileFieldList())
+	deleteTimeout, diags := model.Timeouts.Delete(ctx, 30*time.Minute)
+	if response.Diagnostics.Append(diags...); response.Diagnostics.HasError() {
+		return
 	}
-	output, err := buildOutputFromBody(responseBody, model.ResponseExportValues, defaultOutput)
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	id, err := parse.ResourceIDWithResourceType(model.ID.ValueString(), model.Type.ValueString())
 	if err != nil {
-		response.Diagnostics.AddError("Failed to build output", err.Error())
+		response.Diagnostics.AddError("Invalid resource id", err.Error())
 		return
 	}
-	state.Output = output
 
-	if !model.Body.IsNull() {
-		payload, err := dynamic.FromJSON(data, model.Body.UnderlyingValue().Type(ctx))
-		if err != nil {
-			tflog.Warn(ctx, fmt.Sprintf("Failed to parse payload: %s", err.Error()))
-			payload, err = dynamic.FromJSONImplied(data)
-			if err != nil {
-				response.Diagnostics.AddError("Invalid payload", err.Error())
+	ctx = tflog.SetField(ctx, "resource_id", id.ID())
+	ctx = clients.WithOperation(ctx, id.ID())
+
+	// Ensure the context deadline has been set before calling ConfigureClientWithCustomRetry().
+	client := r.ProviderData.ResourceClient.ConfigureClientWithCustomRetry(ctx, model.Retry, false)
+
+	options := model.updateOptions(exclusiveLockIds(model.Locks, model.AutoLockParent, r.ProviderData.Features, id.AzureResourceType, id.ParentId))
+	response.Diagnostics.Append(restoreUpdate(ctx, newResourceManagerUpdateTarget(client, id), options, request.Private)...)
+}
+
+func (r *AzapiUpdateResource) ImportState(ctx context.Context, request resource.ImportStateRequest, response *resource.ImportStateResponse) {
+	tflog.Debug(ctx, fmt.Sprintf("Importing Resource - parsing %q", request.ID))
+
+	importId, err := parse.ParseImportId(request.ID)
+	if err != nil {
+		response.Diagnostics.AddError("Invalid Import ID", fmt.Errorf("parsing Import ID %q: %+v", request.ID, err).Error())
+		return
+	}
+
+	// the resource type is required to import a resource whose type can't be determined from the resource id, e.g. an extension resource
+	var id parse.ResourceId
+	switch {
+	case importId.ResourceType == "":
+		input := importId.Id
+		if importId.ApiVersion != "" {
+			input = fmt.Sprintf("%s?api-version=%s", importId.Id, importId.ApiVersion)
+		}
+		id, err = parse.ResourceID(input)
+	default:
+		if importId.ApiVersion == "" {
+			apiVersions := azure.GetApiVersions(importId.ResourceType)
+			if len(apiVersions) == 0 {
+				response.Diagnostics.AddError("Invalid Import ID", fmt.Sprintf("the api-version is not specified in the Import ID %q and no api-version is found for the resource type %q", request.ID, importId.ResourceType))
 				return
 			}
+			importId.ApiVersion = apiVersions[len(apiVersions)-1]
 		}
-		state.Body = payload
+		id, err = parse.ResourceIDWithResourceType(importId.Id, importId.TypeWithApiVersion())
+	}
+	if err != nil {
+		response.Diagnostics.AddError("Invalid Resource ID", fmt.Errorf("parsing Resource ID %q: %+v", importId.Id, err).Error())
+		return
+	}
+
+	client := r.ProviderData.ResourceClient
+
+	state := r.defaultAzapiUpdateResourceModel()
+	state.ID = types.StringValue(id.ID())
+	state.Name = types.StringValue(id.Name)
+	state.ParentID = types.StringValue(id.ParentId)
+	state.ResourceID = types.StringValue(id.AzureResourceId)
+	state.Type = types.StringValue(fmt.Sprintf("%s@%s", id.AzureResourceType, id.ApiVersion))
+
+	responseBody, err := client.Get(ctx, id.AzureResourceId, id.ApiVersion, clients.NewRequestOptions(AsMapOfString(state.ReadHeaders), AsMapOfLists(state.ReadQueryParameters)))
+	if err != nil {
+		if utils.ResponseErrorWasNotFound(err) {
+			tflog.Info(ctx, fmt.Sprintf("[INFO] Error reading %q - removing from state", id.ID()))
+			response.State.RemoveResource(ctx)
+			return
+		}
+		response.Diagnostics.AddError("Failed to retrieve resource", fmt.Errorf("reading %s: %+v", id, err).Error())
+		return
 	}
 
+	tflog.Info(ctx, fmt.Sprintf("resource %q is imported", id.ID()))
+	payload, err := flattenBody(responseBody, id.ResourceDef)
+	if err != nil {
+		response.Diagnostics.AddError("Invalid body", err.Error())
+		return
+	}
+	state.Body = payload
+
+	output, err := flattenUpdateOutput(newResourceManagerUpdateTarget(client, id), responseBody, state.ResponseExportValues, r.ProviderData.Features)
+	if err != nil {
+		response.Diagnostics.AddError("Failed to build output", err.Error())
+		return
+	}
+	state.Output = output
+
 	response.Diagnostics.Append(response.State.Set(ctx, state)...)
 }
 
-func (r *AzapiUpdateResource) Delete(ctx context.Context, request resource.DeleteRequest, response *resource.DeleteResponse) {
+func (r *AzapiUpdateResource) defaultAzapiUpdateResourceModel() AzapiUpdateResourceModel {
+	return AzapiUpdateResourceModel{
+		ID:                    types.StringNull(),
+		Name:                  types.StringNull(),
+		ParentID:              types.StringNull(),
+		ResourceID:            types.StringNull(),
+		Type:                  types.StringNull(),
+		Body:                  types.Dynamic{},
+		IgnoreCasing:          types.BoolValue(false),
+		IgnoreMissingProperty: types.BoolValue(true),
+		ArrayItemKeys:         types.MapNull(types.StringType),
+		ResponseExportValues:  types.DynamicNull(),
+		Locks:                 types.ListNull(types.StringType),
+		AutoLockParent:        types.BoolNull(),
+		SharedLocks:           types.ListNull(types.StringType),
+		Output:                types.DynamicNull(),
+		Timeouts: timeouts.Value{
+			Object: types.ObjectNull(map[string]attr.Type{
+				"create": types.StringType,
+				"update": types.StringType,
+				"read":   types.StringType,
+				"delete": types.StringType,
+			}),
+		},
+		Retry:                 retry.RetryValue{},
+		UpdateHeaders:         types.MapNull(types.StringType),
+		UpdateQueryParameters: types.MapNull(types.ListType{ElemType: types.StringType}),
+		ReadHeaders:           types.MapNull(types.StringType),
+		ReadQueryParameters:   types.MapNull(types.ListType{ElemType: types.StringType}),
+		RestoreOnDestroy:      types.BoolValue(false),
+		MergeStrategy:         types.StringValue(utils.MergeStrategyDeep),
+	}
+}
 
+// updateOptions returns the arguments shared with azapi_data_plane_update_resource, the exclusive locks include the parent when it's locked automatically.
+func (model AzapiUpdateResourceModel) updateOptions(exclusiveLocks []string) updateOptions {
+	return updateOptions{
+		Body:                  model.Body,
+		IgnoreCasing:          model.IgnoreCasing.ValueBool(),
+		IgnoreMissingProperty: model.IgnoreMissingProperty.ValueBool(),
+		ArrayItemKeys:         model.ArrayItemKeys,
+		MergeStrategy:         model.MergeStrategy.ValueString(),
+		RestoreOnDestroy:      model.RestoreOnDestroy.ValueBool(),
+		ResponseExportValues:  model.ResponseExportValues,
+		Locks:                 exclusiveLocks,
+		SharedLocks:           AsStringList(model.SharedLocks),
+		ReadOptions:           clients.NewRequestOptions(AsMapOfString(model.ReadHeaders), AsMapOfLists(model.ReadQueryParameters)),
+		UpdateOptions:         clients.NewRequestOptions(AsMapOfString(model.UpdateHeaders), AsMapOfLists(model.UpdateQueryParameters)),
+	}
 }
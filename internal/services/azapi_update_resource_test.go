@@ -30,6 +30,7 @@ func TestAccGenericUpdateResource_automationAccount(t *testing.T) {
 				check.That(data.ResourceName).Key("name").Exists(),
 			),
 		},
+		data.ImportStepWithImportStateIdFunc(r.ImportIdFunc, defaultIgnores()...),
 	})
 }
 
@@ -98,6 +99,21 @@ func TestAccGenericUpdateResource_ignoreOrderInArray(t *testing.T) {
 	})
 }
 
+func TestAccGenericUpdateResource_arrayByKey(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azapi_update_resource", "test")
+	r := GenericUpdateResource{}
+
+	data.ResourceTest(t, r, []resource.TestStep{
+		{
+			Config: r.arrayByKey(data),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That("data.azapi_resource.vnet").Key("output.properties.subnets.#").HasValue("2"),
+			),
+		},
+	})
+}
+
 func TestAccGenericUpdateResource_timeouts(t *testing.T) {
 	data := acceptance.BuildTestData(t, "azapi_update_resource", "test")
 	r := GenericUpdateResource{}
@@ -132,6 +148,26 @@ func TestAccGenericUpdateResource_ignoreIDCasing(t *testing.T) {
 	})
 }
 
+func TestAccGenericUpdateResource_restoreOnDestroy(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azapi_update_resource", "test")
+	r := GenericUpdateResource{}
+
+	data.ResourceTest(t, r, []resource.TestStep{
+		{
+			Config: r.restoreOnDestroy(data),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+			),
+		},
+		{
+			Config: r.restoreOnDestroyRemoved(data),
+			Check: resource.ComposeTestCheckFunc(
+				check.That("data.azapi_resource.automationAccount").Key("output.properties.disableLocalAuth").HasValue("false"),
+			),
+		},
+	})
+}
+
 func TestAccGenericUpdateResource_headers(t *testing.T) {
 	data := acceptance.BuildTestData(t, "azapi_update_resource", "test")
 	r := GenericUpdateResource{}
@@ -160,6 +196,15 @@ func TestAccGenericUpdateResource_queryParameters(t *testing.T) {
 	})
 }
 
+func (GenericUpdateResource) ImportIdFunc(tfState *terraform.State) (string, error) {
+	state := tfState.RootModule().Resources["azapi_update_resource.test"].Primary
+	id, err := parse.ResourceIDWithResourceType(state.ID, state.Attributes["type"])
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s?api-version=%s", id.AzureResourceId, id.ApiVersion), nil
+}
+
 func (r GenericUpdateResource) Exists(ctx context.Context, client *clients.Client, state *terraform.InstanceState) (*bool, error) {
 	resourceType := state.Attributes["type"]
 	id, err := parse.ResourceIDWithResourceType(state.ID, resourceType)
@@ -209,6 +254,63 @@ resource "azapi_update_resource" "test" {
 `, r.template(data), data.RandomString)
 }
 
+func (r GenericUpdateResource) restoreOnDestroy(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+%[1]s
+
+resource "azapi_resource" "automationAccount" {
+  type      = "Microsoft.Automation/automationAccounts@2023-11-01"
+  name      = "acctest-%[2]s"
+  parent_id = azapi_resource.resourceGroup.id
+  location  = azapi_resource.resourceGroup.location
+  body = {
+    properties = {
+      sku = {
+        name = "Basic"
+      }
+    }
+  }
+}
+
+resource "azapi_update_resource" "test" {
+  type               = "Microsoft.Automation/automationAccounts@2023-11-01"
+  resource_id        = azapi_resource.automationAccount.id
+  restore_on_destroy = true
+  body = {
+    properties = {
+      disableLocalAuth = true
+    }
+  }
+}
+`, r.template(data), data.RandomString)
+}
+
+func (r GenericUpdateResource) restoreOnDestroyRemoved(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+%[1]s
+
+resource "azapi_resource" "automationAccount" {
+  type      = "Microsoft.Automation/automationAccounts@2023-11-01"
+  name      = "acctest-%[2]s"
+  parent_id = azapi_resource.resourceGroup.id
+  location  = azapi_resource.resourceGroup.location
+  body = {
+    properties = {
+      sku = {
+        name = "Basic"
+      }
+    }
+  }
+}
+
+data "azapi_resource" "automationAccount" {
+  type                   = "Microsoft.Automation/automationAccounts@2023-11-01"
+  resource_id            = azapi_resource.automationAccount.id
+  response_export_values = ["properties.disableLocalAuth"]
+}
+`, r.template(data), data.RandomString)
+}
+
 func (r GenericUpdateResource) automationAccountWithNameParentId(data acceptance.TestData) string {
 	return fmt.Sprintf(`
 %[1]s
@@ -421,6 +523,71 @@ resource "azapi_update_resource" "test" {
 `, r.template(data), data.RandomInteger)
 }
 
+func (r GenericUpdateResource) arrayByKey(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+%[1]s
+
+resource "azapi_resource" "vnet" {
+  type      = "Microsoft.Network/virtualNetworks@2023-09-01"
+  parent_id = azapi_resource.resourceGroup.id
+  name      = "acctest%[2]d"
+  location  = azapi_resource.resourceGroup.location
+  body = {
+    properties = {
+      addressSpace = {
+        addressPrefixes = [
+          "10.0.0.0/16",
+        ]
+      }
+      subnets = [
+        {
+          name = "first"
+          properties = {
+            addressPrefix = "10.0.3.0/24"
+          }
+        },
+        {
+          name = "second"
+          properties = {
+            addressPrefix = "10.0.4.0/24"
+          }
+        }
+      ]
+    }
+  }
+  lifecycle {
+    ignore_changes = [body.properties.subnets]
+  }
+}
+
+resource "azapi_update_resource" "test" {
+  type           = "Microsoft.Network/virtualNetworks@2023-09-01"
+  resource_id    = azapi_resource.vnet.id
+  merge_strategy = "array_by_key"
+  body = {
+    properties = {
+      subnets = [
+        {
+          name = "second"
+          properties = {
+            defaultOutboundAccess = false
+          }
+        }
+      ]
+    }
+  }
+}
+
+data "azapi_resource" "vnet" {
+  type        = "Microsoft.Network/virtualNetworks@2023-09-01"
+  resource_id = azapi_resource.vnet.id
+
+  response_export_values = ["properties.subnets"]
+  depends_on             = [azapi_update_resource.test]
+}
+`, r.template(data), data.RandomInteger)
+}
+
 func (r GenericUpdateResource) ignoreIDCasing(data acceptance.TestData) string {
 	return fmt.Sprintf(`
 %[1]s
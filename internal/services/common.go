@@ -69,3 +69,24 @@ func volatileFieldList() []string {
 		"daysTrialRemaining",
 	}
 }
+
+// dataPlaneReadOnlyFieldList returns the fields which are managed by the data plane services and can't be set in the request body.
+func dataPlaneReadOnlyFieldList() []string {
+	return append(volatileFieldList(),
+		"created",
+		"createdBy",
+		"createdOn",
+		"createdAt",
+		"createdDateTime",
+		"lastModified",
+		"lastModifiedOn",
+		"lastModifiedDateTime",
+		"last_modified",
+		"systemData",
+		"kid",
+		"sid",
+		"recoveryLevel",
+		"recoverableDays",
+		"provisioningState",
+	)
+}
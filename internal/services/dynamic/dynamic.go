@@ -3,7 +3,6 @@ package dynamic
 import (
 	"encoding/json"
 	"fmt"
-	"math/big"
 
 	"github.com/Azure/terraform-provider-azapi/utils"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
@@ -64,8 +63,7 @@ func attrValueToJSON(val attr.Value, handler UnknownValueHandler) ([]byte, error
 	case types.Float64:
 		return json.Marshal(value.ValueFloat64())
 	case types.Number:
-		v, _ := value.ValueBigFloat().Float64()
-		return json.Marshal(v)
+		return json.Marshal(json.Number(utils.FormatNumber(value.ValueBigFloat())))
 	case types.List:
 		l, err := attrListToJSON(value.Elements(), handler)
 		if err != nil {
@@ -167,11 +165,15 @@ func attrValueFromJSON(b []byte, typ attr.Type) (attr.Value, error) {
 		if b == nil || string(b) == "null" {
 			return types.NumberNull(), nil
 		}
-		var v float64
+		var v json.Number
 		if err := json.Unmarshal(b, &v); err != nil {
 			return nil, err
 		}
-		return types.NumberValue(big.NewFloat(v)), nil
+		f, err := utils.ParseNumber(v.String())
+		if err != nil {
+			return nil, err
+		}
+		return types.NumberValue(f), nil
 	case basetypes.ListType:
 		if b == nil || string(b) == "null" {
 			return types.ListNull(typ.ElemType), nil
@@ -285,7 +287,7 @@ func attrValueFromJSON(b []byte, typ attr.Type) (attr.Value, error) {
 // FromJSONImplied is similar to FromJSON, while it is for typeless case.
 // In which case, the following type conversion rules are applied (Go -> TF):
 // - bool: bool
-// - float64: number
+// - json.Number: number
 // - string: string
 // - []interface{}: tuple
 // - map[string]interface{}: object
@@ -345,15 +347,19 @@ func attrValueFromJSONImplied(b []byte) (attr.Type, attr.Value, error) {
 
 	// Primitives
 	var v interface{}
-	if err := json.Unmarshal(b, &v); err != nil {
+	if err := utils.UnmarshalJSON(b, &v); err != nil {
 		return nil, nil, fmt.Errorf("failed to unmarshal %s: %v", string(b), err)
 	}
 
 	switch v := v.(type) {
 	case bool:
 		return types.BoolType, types.BoolValue(v), nil
-	case float64:
-		return types.NumberType, types.NumberValue(big.NewFloat(v)), nil
+	case json.Number:
+		f, err := utils.ParseNumber(v.String())
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse number %s: %v", v, err)
+		}
+		return types.NumberType, types.NumberValue(f), nil
 	case string:
 		return types.StringType, types.StringValue(v), nil
 	case nil:
@@ -400,7 +400,7 @@ func TestFromJSON(t *testing.T) {
 						"int64_null":   types.Int64Null(),
 						"float64":      types.Float64Value(1.23),
 						"float64_null": types.Float64Null(),
-						"number":       types.NumberValue(big.NewFloat(1.23)),
+						"number":       types.NumberValue(mustParseNumber("1.23")),
 						"number_null":  types.NumberNull(),
 						"list": types.ListValueMust(
 							types.BoolType,
@@ -611,11 +611,11 @@ func TestFromJSONImplied(t *testing.T) {
 						"bool_null":    types.DynamicNull(),
 						"string":       types.StringValue("a"),
 						"string_null":  types.DynamicNull(),
-						"int64":        types.NumberValue(big.NewFloat(123)),
+						"int64":        types.NumberValue(mustParseNumber("123")),
 						"int64_null":   types.DynamicNull(),
-						"float64":      types.NumberValue(big.NewFloat(1.23)),
+						"float64":      types.NumberValue(mustParseNumber("1.23")),
 						"float64_null": types.DynamicNull(),
-						"number":       types.NumberValue(big.NewFloat(1.23)),
+						"number":       types.NumberValue(mustParseNumber("1.23")),
 						"number_null":  types.DynamicNull(),
 						"list": types.TupleValueMust(
 							[]attr.Type{
@@ -684,3 +684,26 @@ func TestFromJSONImplied(t *testing.T) {
 		})
 	}
 }
+
+func TestNumberPrecision(t *testing.T) {
+	input := `{"int":9007199254740993,"float":0.1,"exp":1e+21,"negative":-12345678901234567890}`
+
+	actual, err := FromJSONImplied([]byte(input))
+	require.NoError(t, err)
+
+	obj := actual.UnderlyingValue().(types.Object)
+	require.Equal(t, "9007199254740993", obj.Attributes()["int"].(types.Number).ValueBigFloat().Text('f', 0))
+
+	output, err := ToJSON(actual)
+	require.NoError(t, err)
+	require.Contains(t, string(output), `"int":9007199254740993`)
+	require.JSONEq(t, `{"int":9007199254740993,"float":0.1,"exp":1000000000000000000000,"negative":-12345678901234567890}`, string(output))
+}
+
+func mustParseNumber(s string) *big.Float {
+	f, _, err := big.ParseFloat(s, 10, 512, big.ToNearestEven)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
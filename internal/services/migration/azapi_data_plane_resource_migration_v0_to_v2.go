@@ -97,6 +97,7 @@ func AzapiDataPlaneResourceMigrationV0ToV2(ctx context.Context) resource.StateUp
 				ResponseExportValues          types.Dynamic       `tfsdk:"response_export_values"`
 				Retry                         retry.RetryValue    `tfsdk:"retry"`
 				Locks                         types.List          `tfsdk:"locks"`
+				SharedLocks                   types.List          `tfsdk:"shared_locks"`
 				Output                        types.Dynamic       `tfsdk:"output"`
 				Timeouts                      timeouts.Value      `tfsdk:"timeouts"`
 				CreateHeaders                 map[string]string   `tfsdk:"create_headers"`
@@ -107,6 +108,8 @@ func AzapiDataPlaneResourceMigrationV0ToV2(ctx context.Context) resource.StateUp
 				DeleteQueryParameters         map[string][]string `tfsdk:"delete_query_parameters"`
 				ReadHeaders                   map[string]string   `tfsdk:"read_headers"`
 				ReadQueryParameters           map[string][]string `tfsdk:"read_query_parameters"`
+				ArrayItemKeys                 map[string]string   `tfsdk:"array_item_keys"`
+				SchemaValidationEnabled       types.Bool          `tfsdk:"schema_validation_enabled"`
 			}
 
 			var oldState OldModel
@@ -140,6 +143,7 @@ func AzapiDataPlaneResourceMigrationV0ToV2(ctx context.Context) resource.StateUp
 				Type:                          oldState.Type,
 				Body:                          bodyVal,
 				Locks:                         oldState.Locks,
+				SharedLocks:                   types.ListNull(types.StringType),
 				IgnoreCasing:                  oldState.IgnoreCasing,
 				IgnoreMissingProperty:         oldState.IgnoreMissingProperty,
 				ResponseExportValues:          responseExportValues,
@@ -148,6 +152,7 @@ func AzapiDataPlaneResourceMigrationV0ToV2(ctx context.Context) resource.StateUp
 				Retry:                         retry.NewRetryValueNull(),
 				Output:                        outputVal,
 				Timeouts:                      oldState.Timeouts,
+				SchemaValidationEnabled:       types.BoolValue(true),
 			}
 
 			response.Diagnostics.Append(response.State.Set(ctx, newState)...)
@@ -91,6 +91,7 @@ func AzapiResourceActionMigrationV0ToV2(ctx context.Context) resource.StateUpgra
 				Body                          types.Dynamic       `tfsdk:"body"`
 				When                          types.String        `tfsdk:"when"`
 				Locks                         types.List          `tfsdk:"locks"`
+				SharedLocks                   types.List          `tfsdk:"shared_locks"`
 				ResponseExportValues          types.Dynamic       `tfsdk:"response_export_values"`
 				SensitiveResponseExportValues types.Dynamic       `tfsdk:"sensitive_response_export_values"`
 				Output                        types.Dynamic       `tfsdk:"output"`
@@ -147,6 +148,7 @@ func AzapiResourceActionMigrationV0ToV2(ctx context.Context) resource.StateUpgra
 				Body:                          bodyVal,
 				When:                          when,
 				Locks:                         oldState.Locks,
+				SharedLocks:                   types.ListNull(types.StringType),
 				ResponseExportValues:          responseExportValues,
 				SensitiveResponseExportValues: types.DynamicNull(),
 				Output:                        outputVal,
@@ -149,6 +149,9 @@ func AzapiResourceMigrationV1ToV2(ctx context.Context) resource.StateUpgrader {
 				Identity                      types.List          `tfsdk:"identity"`
 				Body                          types.Dynamic       `tfsdk:"body"`
 				Locks                         types.List          `tfsdk:"locks"`
+				SharedLocks                   types.List          `tfsdk:"shared_locks"`
+				AutoLockParent                types.Bool          `tfsdk:"auto_lock_parent"`
+				ManagementLockBehavior        types.String        `tfsdk:"management_lock_behavior"`
 				SchemaValidationEnabled       types.Bool          `tfsdk:"schema_validation_enabled"`
 				IgnoreCasing                  types.Bool          `tfsdk:"ignore_casing"`
 				IgnoreMissingProperty         types.Bool          `tfsdk:"ignore_missing_property"`
@@ -167,6 +170,7 @@ func AzapiResourceMigrationV1ToV2(ctx context.Context) resource.StateUpgrader {
 				DeleteQueryParameters         map[string][]string `tfsdk:"delete_query_parameters"`
 				ReadHeaders                   map[string]string   `tfsdk:"read_headers"`
 				ReadQueryParameters           map[string][]string `tfsdk:"read_query_parameters"`
+				ArrayItemKeys                 map[string]string   `tfsdk:"array_item_keys"`
 			}
 
 			var oldState OldModel
@@ -200,6 +204,9 @@ func AzapiResourceMigrationV1ToV2(ctx context.Context) resource.StateUpgrader {
 				Identity:                      oldState.Identity,
 				Body:                          bodyVal,
 				Locks:                         oldState.Locks,
+				SharedLocks:                   types.ListNull(types.StringType),
+				AutoLockParent:                types.BoolNull(),
+				ManagementLockBehavior:        types.StringNull(),
 				SchemaValidationEnabled:       oldState.SchemaValidationEnabled,
 				IgnoreCasing:                  oldState.IgnoreCasing,
 				IgnoreMissingProperty:         oldState.IgnoreMissingProperty,
@@ -109,6 +109,8 @@ func AzapiUpdateResourceMigrationV0ToV2(ctx context.Context) resource.StateUpgra
 				IgnoreMissingProperty types.Bool          `tfsdk:"ignore_missing_property"`
 				ResponseExportValues  types.Dynamic       `tfsdk:"response_export_values"`
 				Locks                 types.List          `tfsdk:"locks"`
+				SharedLocks           types.List          `tfsdk:"shared_locks"`
+				AutoLockParent        types.Bool          `tfsdk:"auto_lock_parent"`
 				Output                types.Dynamic       `tfsdk:"output"`
 				Timeouts              timeouts.Value      `tfsdk:"timeouts"`
 				Retry                 retry.RetryValue    `tfsdk:"retry"`
@@ -116,6 +118,9 @@ func AzapiUpdateResourceMigrationV0ToV2(ctx context.Context) resource.StateUpgra
 				UpdateQueryParameters map[string][]string `tfsdk:"update_query_parameters"`
 				ReadHeaders           map[string]string   `tfsdk:"read_headers"`
 				ReadQueryParameters   map[string][]string `tfsdk:"read_query_parameters"`
+				ArrayItemKeys         map[string]string   `tfsdk:"array_item_keys"`
+				RestoreOnDestroy      types.Bool          `tfsdk:"restore_on_destroy"`
+				MergeStrategy         types.String        `tfsdk:"merge_strategy"`
 			}
 
 			var oldState OldModel
@@ -150,12 +155,16 @@ func AzapiUpdateResourceMigrationV0ToV2(ctx context.Context) resource.StateUpgra
 				Type:                  oldState.Type,
 				Body:                  bodyVal,
 				Locks:                 oldState.Locks,
+				SharedLocks:           types.ListNull(types.StringType),
+				AutoLockParent:        types.BoolNull(),
 				IgnoreCasing:          oldState.IgnoreCasing,
 				IgnoreMissingProperty: oldState.IgnoreMissingProperty,
 				ResponseExportValues:  responseExportValues,
 				Output:                outputVal,
 				Timeouts:              oldState.Timeouts,
 				Retry:                 retry.NewRetryValueNull(),
+				RestoreOnDestroy:      types.BoolValue(false),
+				MergeStrategy:         types.StringValue("deep"),
 			}
 
 			response.Diagnostics.Append(response.State.Set(ctx, newState)...)
@@ -2,8 +2,11 @@ package parse
 
 import (
 	"fmt"
+	"log"
 	"strings"
 
+	"github.com/Azure/terraform-provider-azapi/internal/azure"
+	"github.com/Azure/terraform-provider-azapi/internal/azure/types"
 	"github.com/Azure/terraform-provider-azapi/utils"
 )
 
@@ -13,6 +16,7 @@ type DataPlaneResourceId struct {
 	AzureResourceType string
 	Name              string
 	ParentId          string
+	ResourceDef       *types.ResourceType
 }
 
 func NewDataPlaneResourceId(name, parentId, resourceType string) (DataPlaneResourceId, error) {
@@ -20,6 +24,10 @@ func NewDataPlaneResourceId(name, parentId, resourceType string) (DataPlaneResou
 	if err != nil {
 		return DataPlaneResourceId{}, err
 	}
+	resourceDef, err := azure.GetDataPlaneResourceDefinition(azureResourceType, apiVersion)
+	if err != nil {
+		log.Printf("[DEBUG] load embedded data plane schema: %+v\n", err)
+	}
 
 	azureResourceId := ""
 	if apiPath := findApiPathByResourceType(azureResourceType); apiPath != nil {
@@ -49,6 +57,7 @@ func NewDataPlaneResourceId(name, parentId, resourceType string) (DataPlaneResou
 		AzureResourceType: azureResourceType,
 		Name:              name,
 		ParentId:          parentId,
+		ResourceDef:       resourceDef,
 	}, nil
 }
 
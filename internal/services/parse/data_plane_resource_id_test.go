@@ -173,3 +173,58 @@ func Test_DataPlaneResourceIDWithResourceType(t *testing.T) {
 		}
 	}
 }
+
+func Test_DataPlaneResourceIdWithCustomApiPaths(t *testing.T) {
+	parse.SetCustomApiPaths([]parse.ApiPath{
+		{
+			UrlFormat:    "{parentId}/indexes/{name}",
+			ResourceType: "Microsoft.Search/searchServices/indexes",
+		},
+		{
+			UrlFormat:    "{parentId}/kv2/{name}",
+			ResourceType: "Microsoft.AppConfiguration/configurationStores/keyValues",
+		},
+	})
+	defer parse.SetCustomApiPaths(nil)
+
+	testData := []struct {
+		Name         string
+		ParentId     string
+		ResourceType string
+		Expected     string
+	}{
+		{
+			Name:         "hotels",
+			ParentId:     "foo.search.windows.net",
+			ResourceType: "Microsoft.Search/searchServices/indexes@2024-07-01",
+			Expected:     "foo.search.windows.net/indexes/hotels",
+		},
+		{
+			// the custom types take precedence over the embedded ones
+			Name:         "test",
+			ParentId:     "xxx.xxx.xxx",
+			ResourceType: "Microsoft.AppConfiguration/configurationStores/keyValues@1.0",
+			Expected:     "xxx.xxx.xxx/kv2/test",
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q %q %q", v.Name, v.ParentId, v.ResourceType)
+
+		actual, err := parse.NewDataPlaneResourceId(v.Name, v.ParentId, v.ResourceType)
+		if err != nil {
+			t.Fatalf("Expect a value but got an error: %s", err)
+		}
+		if actual.AzureResourceId != v.Expected {
+			t.Fatalf("Expected %q but got %q for AzureResourceId", v.Expected, actual.AzureResourceId)
+		}
+
+		parsed, err := parse.DataPlaneResourceIDWithResourceType(actual.AzureResourceId, v.ResourceType)
+		if err != nil {
+			t.Fatalf("Expect a value but got an error: %s", err)
+		}
+		if parsed.Name != v.Name || parsed.ParentId != v.ParentId {
+			t.Fatalf("Expected name %q and parent ID %q but got %q and %q", v.Name, v.ParentId, parsed.Name, parsed.ParentId)
+		}
+	}
+}
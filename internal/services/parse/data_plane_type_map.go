@@ -3,6 +3,7 @@ package parse
 import (
 	"encoding/json"
 	"strings"
+	"sync"
 )
 
 type ApiPath struct {
@@ -14,6 +15,12 @@ type ApiPath struct {
 
 var apiPaths = make([]ApiPath, 0)
 
+// customApiPaths are the data plane resource types defined in the provider configuration,
+// they take precedence over the embedded ones.
+var customApiPaths = make([]ApiPath, 0)
+
+var customApiPathsMutex = &sync.RWMutex{}
+
 func init() {
 	err := json.Unmarshal([]byte(raw), &apiPaths)
 	if err != nil {
@@ -21,7 +28,22 @@ func init() {
 	}
 }
 
+// SetCustomApiPaths replaces the data plane resource types defined in the provider configuration.
+func SetCustomApiPaths(paths []ApiPath) {
+	customApiPathsMutex.Lock()
+	defer customApiPathsMutex.Unlock()
+	customApiPaths = make([]ApiPath, len(paths))
+	copy(customApiPaths, paths)
+}
+
 func findApiPathByResourceType(resourceType string) *ApiPath {
+	customApiPathsMutex.RLock()
+	defer customApiPathsMutex.RUnlock()
+	for _, apiPath := range customApiPaths {
+		if strings.EqualFold(apiPath.ResourceType, resourceType) {
+			return &apiPath
+		}
+	}
 	for _, apiPath := range apiPaths {
 		if strings.EqualFold(apiPath.ResourceType, resourceType) {
 			return &apiPath
@@ -194,6 +216,18 @@ const raw = `
     "ParentIDExample": "{vaultName}.vault.azure.net",
     "Url": "/certificates/issuers/{issuer-name}"
   },
+  {
+    "UrlFormat": "{parentId}/keys/{name}",
+    "ResourceType": "Microsoft.KeyVault/vaults/keys",
+    "ParentIDExample": "{vaultName}.vault.azure.net",
+    "Url": "/keys/{key-name}"
+  },
+  {
+    "UrlFormat": "{parentId}/secrets/{name}",
+    "ResourceType": "Microsoft.KeyVault/vaults/secrets",
+    "ParentIDExample": "{vaultName}.vault.azure.net",
+    "Url": "/secrets/{secret-name}"
+  },
   {
     "UrlFormat": "{parentId}/storage/{name}",
     "ResourceType": "Microsoft.KeyVault/vaults/storage",
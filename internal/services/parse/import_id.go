@@ -0,0 +1,49 @@
+package parse
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ImportId is the ID used to import a resource, it's in a format like `<id>?api-version=<api-version>&type=<resource-type>`.
+type ImportId struct {
+	Id           string
+	ResourceType string
+	ApiVersion   string
+	Query        url.Values
+}
+
+// ParseImportId parses the import ID, the `type` query parameter could also contain the api-version, e.g. `type=Microsoft.AppConfiguration/configurationStores/keyValues@1.0`.
+func ParseImportId(input string) (ImportId, error) {
+	id, rawQuery, _ := strings.Cut(input, "?")
+	if id == "" {
+		return ImportId{}, fmt.Errorf("the ID is empty")
+	}
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return ImportId{}, fmt.Errorf("parsing the query of %q: %+v", input, err)
+	}
+
+	resourceType := query.Get("type")
+	apiVersion := query.Get("api-version")
+	if before, after, found := strings.Cut(resourceType, "@"); found {
+		if apiVersion != "" && apiVersion != after {
+			return ImportId{}, fmt.Errorf("the api-version %q doesn't match the api-version %q in the type %q", apiVersion, after, resourceType)
+		}
+		resourceType = before
+		apiVersion = after
+	}
+
+	return ImportId{
+		Id:           id,
+		ResourceType: resourceType,
+		ApiVersion:   apiVersion,
+		Query:        query,
+	}, nil
+}
+
+// TypeWithApiVersion returns the resource type in a format like `<resource-type>@<api-version>`.
+func (id ImportId) TypeWithApiVersion() string {
+	return fmt.Sprintf("%s@%s", id.ResourceType, id.ApiVersion)
+}
@@ -0,0 +1,71 @@
+package parse_test
+
+import (
+	"testing"
+
+	"github.com/Azure/terraform-provider-azapi/internal/services/parse"
+)
+
+func Test_ParseImportId(t *testing.T) {
+	testData := []struct {
+		Input    string
+		Error    bool
+		Expected parse.ImportId
+	}{
+		{
+			Input: "mystore.azconfig.io/kv/mykey?api-version=1.0&type=Microsoft.AppConfiguration/configurationStores/keyValues",
+			Expected: parse.ImportId{
+				Id:           "mystore.azconfig.io/kv/mykey",
+				ResourceType: "Microsoft.AppConfiguration/configurationStores/keyValues",
+				ApiVersion:   "1.0",
+			},
+		},
+		{
+			Input: "mystore.azconfig.io/kv/mykey?type=Microsoft.AppConfiguration/configurationStores/keyValues@1.0",
+			Expected: parse.ImportId{
+				Id:           "mystore.azconfig.io/kv/mykey",
+				ResourceType: "Microsoft.AppConfiguration/configurationStores/keyValues",
+				ApiVersion:   "1.0",
+			},
+		},
+		{
+			Input: "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1?api-version=2023-07-01",
+			Expected: parse.ImportId{
+				Id:         "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1",
+				ApiVersion: "2023-07-01",
+			},
+		},
+		{
+			Input: "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1",
+			Expected: parse.ImportId{
+				Id: "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1",
+			},
+		},
+		{
+			Input: "mystore.azconfig.io/kv/mykey?api-version=2.0&type=Microsoft.AppConfiguration/configurationStores/keyValues@1.0",
+			Error: true,
+		},
+		{
+			Input: "?api-version=1.0",
+			Error: true,
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q", v.Input)
+
+		actual, err := parse.ParseImportId(v.Input)
+		if v.Error {
+			if err == nil {
+				t.Fatalf("expected an error but got none")
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %+v", err)
+		}
+		if actual.Id != v.Expected.Id || actual.ResourceType != v.Expected.ResourceType || actual.ApiVersion != v.Expected.ApiVersion {
+			t.Fatalf("expected %+v but got %+v", v.Expected, actual)
+		}
+	}
+}
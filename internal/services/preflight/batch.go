@@ -0,0 +1,231 @@
+package preflight
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Azure/terraform-provider-azapi/internal/clients"
+	"github.com/Azure/terraform-provider-azapi/utils"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// defaultBatchWindow is the duration to wait for other resources in the same scope before sending the batch when other validations are in progress
+	defaultBatchWindow = 200 * time.Millisecond
+
+	// defaultMaxBatchSize is the max number of resources in a single validateResources request
+	defaultMaxBatchSize = 50
+)
+
+var defaultBatcher = NewBatcher(defaultBatchWindow, defaultMaxBatchSize)
+
+// Batcher collects the preflight validations which are requested concurrently, e.g. from the ModifyPlan calls of a large plan,
+// and sends the resources which share the same scope in a single validateResources request.
+// If no other validation is in progress, the resource is sent immediately, otherwise it waits for the other resources in the same scope.
+// The results are cached by the hash of the validated resource, so the unchanged resources won't be validated again.
+// The successful results are also persisted in the cache directory if it's set, so they're reused by the later Terraform runs.
+type Batcher struct {
+	window       time.Duration
+	maxBatchSize int
+
+	mu      sync.Mutex
+	batches map[string]*batch
+	// sending is the number of batches whose requests are in progress
+	sending int
+	cache   map[string]error
+	files   *fileCache
+}
+
+type batch struct {
+	client  clients.Requester
+	payload RequestBodyModel
+	items   []*batchItem
+}
+
+type batchItem struct {
+	ctx      context.Context
+	cacheKey string
+	payload  RequestBodyModel
+	resource map[string]interface{}
+	done     chan error
+}
+
+func NewBatcher(window time.Duration, maxBatchSize int) *Batcher {
+	return &Batcher{
+		window:       window,
+		maxBatchSize: maxBatchSize,
+		batches:      make(map[string]*batch),
+		cache:        make(map[string]error),
+	}
+}
+
+// SetCacheDirectory persists the successful validation results in the directory for the ttl, the results are only cached in memory if the directory is empty.
+func (b *Batcher) SetCacheDirectory(dir string, ttl time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.files = nil
+	if dir != "" {
+		b.files = &fileCache{dir: dir, ttl: ttl}
+	}
+}
+
+// Cached returns the cached validation result of the resource, the second return value is false if the result isn't cached.
+func (b *Batcher) Cached(ctx context.Context, cacheKey string) (error, bool) {
+	b.mu.Lock()
+	err, ok := b.cache[cacheKey]
+	files := b.files
+	b.mu.Unlock()
+	if ok || files == nil {
+		return err, ok
+	}
+	if !files.get(ctx, cacheKey) {
+		return nil, false
+	}
+	b.mu.Lock()
+	b.cache[cacheKey] = nil
+	b.mu.Unlock()
+	return nil, true
+}
+
+// Validate validates the resource in a batch with the other resources which are deployed at the same scope,
+// it blocks until the result of the resource is available or the context is done.
+func (b *Batcher) Validate(ctx context.Context, client clients.Requester, payload RequestBodyModel, resource map[string]interface{}, cacheKey string) error {
+	if err, ok := b.Cached(ctx, cacheKey); ok {
+		tflog.Debug(ctx, fmt.Sprintf("Using the cached preflight validation result for resource %s/%s", payload.Provider, payload.Type))
+		return err
+	}
+
+	b.mu.Lock()
+
+	item := &batchItem{
+		ctx:      ctx,
+		cacheKey: cacheKey,
+		payload:  payload,
+		resource: resource,
+		done:     make(chan error, 1),
+	}
+
+	// there's nothing to wait for if no other validation is in progress
+	idle := len(b.batches) == 0 && b.sending == 0
+
+	key := batchKey(client, payload)
+	current, ok := b.batches[key]
+	if !ok {
+		current = &batch{
+			client:  client,
+			payload: payload,
+		}
+		b.batches[key] = current
+		if !idle {
+			time.AfterFunc(b.window, func() {
+				b.flush(key, current)
+			})
+		}
+	}
+	current.items = append(current.items, item)
+	if idle || len(current.items) >= b.maxBatchSize {
+		b.dispatch(key, current)
+	}
+	b.mu.Unlock()
+
+	select {
+	case err := <-item.done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// flush sends the batch if it's not sent yet
+func (b *Batcher) flush(key string, target *batch) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if current, ok := b.batches[key]; !ok || current != target {
+		return
+	}
+	b.dispatch(key, target)
+}
+
+// dispatch removes the batch from the pending batches and sends it in the background, the caller must hold the lock.
+func (b *Batcher) dispatch(key string, target *batch) {
+	delete(b.batches, key)
+	b.sending++
+	go b.send(target)
+}
+
+// send validates all resources in the batch with one request. If the request fails, the resources are validated one by one,
+// because the error of the batch request can't be attributed to a specific resource.
+func (b *Batcher) send(target *batch) {
+	defer func() {
+		b.mu.Lock()
+		b.sending--
+		b.mu.Unlock()
+	}()
+
+	// the batch is shared by multiple callers, it shouldn't be cancelled when one of them is cancelled
+	ctx := context.WithoutCancel(target.items[0].ctx)
+
+	resources := make([]map[string]interface{}, 0, len(target.items))
+	for _, item := range target.items {
+		resources = append(resources, item.resource)
+	}
+	err := validateResources(ctx, target.client, target.payload, resources)
+	if err == nil || len(target.items) == 1 {
+		for _, item := range target.items {
+			b.complete(item, err)
+		}
+		return
+	}
+
+	tflog.Info(ctx, fmt.Sprintf("Preflight validation of %d resources in scope %s failed, validating them one by one", len(target.items), target.payload.Scope))
+	for _, item := range target.items {
+		b.complete(item, validateResources(ctx, target.client, item.payload, []map[string]interface{}{item.resource}))
+	}
+}
+
+func (b *Batcher) complete(item *batchItem, err error) {
+	// only the validation results are cached, the other errors like throttling or network errors might be transient
+	if err == nil || utils.ResponseErrorWasStatusCode(err, http.StatusBadRequest) {
+		b.mu.Lock()
+		b.cache[item.cacheKey] = err
+		files := b.files
+		b.mu.Unlock()
+		if err == nil && files != nil {
+			files.set(item.ctx, item.cacheKey)
+		}
+	}
+	item.done <- err
+}
+
+func validateResources(ctx context.Context, client clients.Requester, payload RequestBodyModel, resources []map[string]interface{}) error {
+	payload.Resources = resources
+	_, err := client.Action(ctx, "/providers/Microsoft.Resources", "validateResources", "2020-10-01", "POST", payload, clients.DefaultRequestOptions())
+	return err
+}
+
+// batchKey returns the key of the batch, the resources in the same batch share the same client, scope and location.
+// The resources of different types can be validated in the same batch, because each resource has its own type.
+func batchKey(client clients.Requester, payload RequestBodyModel) string {
+	return fmt.Sprintf("%p|%s|%s", client, payload.Scope, payload.Location)
+}
+
+// cacheKey returns the hash of the configuration of the resource. The key is stable across the Terraform runs, because the client is
+// identified by its Azure Resource Manager endpoint, the clients which don't expose the endpoint are identified by the instance.
+func cacheKey(client clients.Requester, input ...interface{}) string {
+	data, err := json.Marshal(input)
+	if err != nil {
+		data = []byte(fmt.Sprintf("%v", input))
+	}
+	identity := fmt.Sprintf("%p", client)
+	if v, ok := client.(interface{ Host() string }); ok {
+		identity = v.Host()
+	}
+	hash := sha256.Sum256(append([]byte(identity+"|"), data...))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,260 @@
+package preflight
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/terraform-provider-azapi/internal/clients"
+)
+
+type fakeValidateClient struct {
+	clients.Requester
+
+	mu    sync.Mutex
+	calls [][]string
+	// invalid is the name of the resource which fails the validation
+	invalid string
+	// statusCode is the status code returned when the validation fails
+	statusCode int
+	// delay is the duration of each request
+	delay time.Duration
+}
+
+func (c *fakeValidateClient) Action(_ context.Context, _ string, _ string, _ string, _ string, body interface{}, _ clients.RequestOptions) (interface{}, error) {
+	payload := body.(RequestBodyModel)
+	names := make([]string, 0)
+	for _, resource := range payload.Resources {
+		names = append(names, resource["name"].(string))
+	}
+	c.mu.Lock()
+	c.calls = append(c.calls, names)
+	c.mu.Unlock()
+	time.Sleep(c.delay)
+	for _, name := range names {
+		if name == c.invalid {
+			return nil, &azcore.ResponseError{StatusCode: c.statusCode, ErrorCode: "InvalidTemplate"}
+		}
+	}
+	return nil, nil
+}
+
+type fakeHostClient struct {
+	fakeValidateClient
+	host string
+}
+
+func (c *fakeHostClient) Host() string {
+	return c.host
+}
+
+func (c *fakeValidateClient) callCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.calls)
+}
+
+func validateConcurrently(b *Batcher, client clients.Requester, names []string) map[string]error {
+	payload := RequestBodyModel{
+		Provider: "Microsoft.Network",
+		Type:     "virtualNetworks",
+		Scope:    "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg",
+	}
+	res := make(map[string]error)
+	mu := sync.Mutex{}
+	wg := sync.WaitGroup{}
+	for _, name := range names {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			resource := map[string]interface{}{"name": name}
+			err := b.Validate(context.Background(), client, payload, resource, cacheKey(client, name))
+			mu.Lock()
+			res[name] = err
+			mu.Unlock()
+		}(name)
+	}
+	wg.Wait()
+	return res
+}
+
+func Test_BatcherValidate(t *testing.T) {
+	names := []string{"a", "b", "c", "d"}
+
+	t.Run("resources are validated in one batch while another validation is in progress", func(t *testing.T) {
+		client := &fakeValidateClient{delay: 100 * time.Millisecond}
+		b := NewBatcher(50*time.Millisecond, 10)
+		for name, err := range validateConcurrently(b, client, names) {
+			if err != nil {
+				t.Fatalf("expect no error for resource %s, got %v", name, err)
+			}
+		}
+		// the first resource is sent immediately, the others are sent in one batch
+		if len(client.calls) != 2 || len(client.calls[0]) != 1 || len(client.calls[1]) != len(names)-1 {
+			t.Fatalf("expect 2 requests with 1 and %d resources, got %v", len(names)-1, client.calls)
+		}
+
+		// the results are cached
+		validateConcurrently(b, client, names)
+		if count := client.callCount(); count != 2 {
+			t.Fatalf("expect the cached results are used, got %d requests", count)
+		}
+	})
+
+	t.Run("resource is sent immediately if no other validation is in progress", func(t *testing.T) {
+		client := &fakeValidateClient{}
+		b := NewBatcher(time.Hour, 10)
+		done := make(chan error, 1)
+		go func() {
+			done <- validateConcurrently(b, client, []string{"a"})["a"]
+		}()
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatalf("expect the resource is validated without waiting for the batch window")
+		}
+	})
+
+	t.Run("resources of different types are validated in the same batch", func(t *testing.T) {
+		client := &fakeValidateClient{}
+		payload := RequestBodyModel{
+			Provider: "Microsoft.Network",
+			Type:     "virtualNetworks",
+			Scope:    "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg",
+		}
+		other := payload
+		other.Type = "networkSecurityGroups"
+		if batchKey(client, payload) != batchKey(client, other) {
+			t.Fatalf("expect the same batch key for the resources in the same scope")
+		}
+		other.Scope = "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg2"
+		if batchKey(client, payload) == batchKey(client, other) {
+			t.Fatalf("expect different batch keys for the resources in different scopes")
+		}
+	})
+
+	t.Run("batches are split by the max batch size", func(t *testing.T) {
+		client := &fakeValidateClient{delay: 100 * time.Millisecond}
+		b := NewBatcher(time.Second, 2)
+		validateConcurrently(b, client, names)
+		// the first resource is sent immediately, the others are split into batches of 2
+		if count := client.callCount(); count != 3 {
+			t.Fatalf("expect 3 requests, got %v", client.calls)
+		}
+	})
+
+	t.Run("errors are returned to the invalid resource", func(t *testing.T) {
+		client := &fakeValidateClient{invalid: "c", statusCode: http.StatusBadRequest}
+		b := NewBatcher(50*time.Millisecond, 10)
+		for name, err := range validateConcurrently(b, client, names) {
+			if name == "c" && err == nil {
+				t.Fatalf("expect an error for resource %s", name)
+			}
+			if name != "c" && err != nil {
+				t.Fatalf("expect no error for resource %s, got %v", name, err)
+			}
+		}
+
+		// the validation error is cached
+		count := client.callCount()
+		if err := validateConcurrently(b, client, []string{"c"})["c"]; err == nil {
+			t.Fatalf("expect the cached error for resource c")
+		}
+		if client.callCount() != count {
+			t.Fatalf("expect the cached results are used, got %v", client.calls)
+		}
+	})
+
+	t.Run("transient errors are not cached", func(t *testing.T) {
+		client := &fakeValidateClient{invalid: "a", statusCode: http.StatusTooManyRequests}
+		b := NewBatcher(time.Millisecond, 10)
+		for i := 0; i < 2; i++ {
+			if err := validateConcurrently(b, client, []string{"a"})["a"]; err == nil {
+				t.Fatalf("expect an error for resource a")
+			}
+		}
+		if count := client.callCount(); count != 2 {
+			t.Fatalf("expect 2 requests, got %v", client.calls)
+		}
+	})
+}
+
+func Test_CacheKey(t *testing.T) {
+	client := &fakeValidateClient{}
+	body := map[string]interface{}{"properties": map[string]interface{}{"foo": "bar"}}
+	key := cacheKey(client, "Microsoft.Network/virtualNetworks@2024-01-01", "", "vnet", "westus", body)
+	if key != cacheKey(client, "Microsoft.Network/virtualNetworks@2024-01-01", "", "vnet", "westus", body) {
+		t.Fatalf("expect the same cache key for the same configuration")
+	}
+	changed := map[string]interface{}{"properties": map[string]interface{}{"foo": "baz"}}
+	if key == cacheKey(client, "Microsoft.Network/virtualNetworks@2024-01-01", "", "vnet", "westus", changed) {
+		t.Fatalf("expect different cache keys for different bodies")
+	}
+	if key == cacheKey(&fakeValidateClient{}, "Microsoft.Network/virtualNetworks@2024-01-01", "", "vnet", "westus", body) {
+		t.Fatalf("expect different cache keys for different clients")
+	}
+	host := "https://management.azure.com"
+	if cacheKey(&fakeHostClient{host: host}, body) != cacheKey(&fakeHostClient{host: host}, body) {
+		t.Fatalf("expect the same cache key for the clients of the same endpoint")
+	}
+	if cacheKey(&fakeHostClient{host: host}, body) == cacheKey(&fakeHostClient{host: "https://management.chinacloudapi.cn"}, body) {
+		t.Fatalf("expect different cache keys for the clients of different endpoints")
+	}
+}
+
+func Test_BatcherFileCache(t *testing.T) {
+	newBatcher := func(dir string) *Batcher {
+		b := NewBatcher(time.Millisecond, 10)
+		b.SetCacheDirectory(dir, time.Hour)
+		return b
+	}
+
+	t.Run("successful results are reused by another batcher", func(t *testing.T) {
+		dir := t.TempDir()
+		client := &fakeHostClient{host: "https://management.azure.com"}
+		if err := validateConcurrently(newBatcher(dir), client, []string{"a"})["a"]; err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+		if err := validateConcurrently(newBatcher(dir), client, []string{"a"})["a"]; err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+		if count := client.callCount(); count != 1 {
+			t.Fatalf("expect 1 request, got %v", client.calls)
+		}
+	})
+
+	t.Run("expired results are validated again", func(t *testing.T) {
+		dir := t.TempDir()
+		client := &fakeHostClient{host: "https://management.azure.com"}
+		validateConcurrently(newBatcher(dir), client, []string{"a"})
+		expired := time.Now().Add(-2 * time.Hour)
+		if err := os.Chtimes(filepath.Join(dir, cacheKey(client, "a")), expired, expired); err != nil {
+			t.Fatal(err)
+		}
+		validateConcurrently(newBatcher(dir), client, []string{"a"})
+		if count := client.callCount(); count != 2 {
+			t.Fatalf("expect 2 requests, got %v", client.calls)
+		}
+	})
+
+	t.Run("failed results are not persisted", func(t *testing.T) {
+		dir := t.TempDir()
+		client := &fakeHostClient{fakeValidateClient: fakeValidateClient{invalid: "a", statusCode: http.StatusBadRequest}, host: "https://management.azure.com"}
+		for i := 0; i < 2; i++ {
+			if err := validateConcurrently(newBatcher(dir), client, []string{"a"})["a"]; err == nil {
+				t.Fatalf("expect an error for resource a")
+			}
+		}
+		if count := client.callCount(); count != 2 {
+			t.Fatalf("expect 2 requests, got %v", client.calls)
+		}
+	})
+}
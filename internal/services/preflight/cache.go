@@ -0,0 +1,78 @@
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// defaultCacheTTL is the duration for which the successful validation results are reused by the later Terraform runs
+const defaultCacheTTL = time.Hour
+
+// DefaultCacheDirectory returns the default directory of the preflight validation cache
+func DefaultCacheDirectory() string {
+	return filepath.Join(os.TempDir(), "terraform-provider-azapi-preflight-cache")
+}
+
+// SetCacheDirectory persists the successful validation results in the directory, so the unchanged resources aren't validated again
+// by the later Terraform runs on the same host. The results are cached in memory only if the directory is empty.
+func SetCacheDirectory(dir string) {
+	defaultBatcher.SetCacheDirectory(dir, defaultCacheTTL)
+}
+
+// fileCache stores the cache keys of the successful validations as files, a result expires after the ttl since the file is written.
+// The failed validations aren't persisted, because the errors can't be restored and they're expected to be fixed before the next run.
+type fileCache struct {
+	dir string
+	ttl time.Duration
+}
+
+func (c *fileCache) get(ctx context.Context, key string) bool {
+	info, err := os.Stat(c.path(key))
+	if err != nil {
+		return false
+	}
+	if time.Since(info.ModTime()) > c.ttl {
+		if err := os.Remove(c.path(key)); err != nil && !os.IsNotExist(err) {
+			tflog.Debug(ctx, fmt.Sprintf("Failed to remove the expired preflight validation cache %q: %+v", c.path(key), err))
+		}
+		return false
+	}
+	return true
+}
+
+func (c *fileCache) set(ctx context.Context, key string) {
+	if err := c.write(key); err != nil {
+		tflog.Debug(ctx, fmt.Sprintf("Failed to write the preflight validation cache in %q: %+v", c.dir, err))
+	}
+}
+
+// write creates the file atomically, so the concurrent Terraform runs don't see a partially written file
+func (c *fileCache) write(key string) error {
+	if err := os.MkdirAll(c.dir, 0o700); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(c.dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = file.WriteString(time.Now().UTC().Format(time.RFC3339))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), c.path(key))
+	}
+	if err != nil {
+		_ = os.Remove(file.Name())
+	}
+	return err
+}
+
+func (c *fileCache) path(key string) string {
+	return filepath.Join(c.dir, key)
+}
@@ -0,0 +1,286 @@
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/terraform-provider-azapi/internal/clients"
+	"github.com/Azure/terraform-provider-azapi/utils"
+)
+
+const policyCheckApiVersion = "2022-03-01"
+
+// PolicyRestriction is a restriction of the Azure Policy which applies to the planned resource
+type PolicyRestriction struct {
+	// Field is the field which the restriction applies to, for example, `location` or `tags.environment`.
+	// It's empty if the restriction applies to the whole resource.
+	Field string
+	// Denied is true if the resource is non-compliant with a policy whose effect is deny, the request will be disallowed by the policy
+	Denied  bool
+	Message string
+}
+
+// IsPolicyCheckSupported checks if the policy restrictions of the resource can be checked,
+// the checkPolicyRestrictions API only supports the resources which are deployed in a subscription or a resource group.
+func IsPolicyCheckSupported(parentId string) bool {
+	_, err := policyCheckScope(parentId)
+	return err == nil
+}
+
+// CheckPolicyRestrictions checks the planned resource against the policies assigned to the scope where it's deployed,
+// it returns the restrictions which apply to the resource. The name is optional, it's treated as a pending field if it's not specified.
+func CheckPolicyRestrictions(ctx context.Context, client clients.Requester, resourceType string, parentId string, name string, body map[string]interface{}) ([]PolicyRestriction, error) {
+	azureResourceType, apiVersion, err := utils.GetAzureResourceTypeApiVersion(resourceType)
+	if err != nil {
+		return nil, err
+	}
+
+	scope, err := policyCheckScope(parentId)
+	if err != nil {
+		return nil, err
+	}
+
+	resourceContent := make(map[string]interface{})
+	for k, v := range body {
+		resourceContent[k] = v
+	}
+	resourceContent["type"] = azureResourceType
+	pendingFields := make([]interface{}, 0)
+	if name != "" {
+		resourceContent["name"] = name
+	} else {
+		pendingFields = append(pendingFields, map[string]interface{}{"field": "name"})
+	}
+
+	payload := map[string]interface{}{
+		"resourceDetails": map[string]interface{}{
+			"resourceContent": resourceContent,
+			"apiVersion":      apiVersion,
+			"scope":           parentId,
+		},
+		"pendingFields":      pendingFields,
+		"includeAuditEffect": true,
+	}
+
+	responseBody, err := client.Action(ctx, scope+"/providers/Microsoft.PolicyInsights", "checkPolicyRestrictions", policyCheckApiVersion, "POST", payload, clients.DefaultRequestOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	return flattenPolicyRestrictions(responseBody, resourceContent), nil
+}
+
+// policyCheckScope returns the subscription or the resource group where the resource is deployed
+func policyCheckScope(parentId string) (string, error) {
+	id, err := arm.ParseResourceID(parentId)
+	if err != nil {
+		return "", err
+	}
+	if id.SubscriptionID == "" {
+		return "", fmt.Errorf("the resource is not deployed in a subscription")
+	}
+	if id.ResourceGroupName != "" {
+		return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s", id.SubscriptionID, id.ResourceGroupName), nil
+	}
+	return fmt.Sprintf("/subscriptions/%s", id.SubscriptionID), nil
+}
+
+func flattenPolicyRestrictions(responseBody interface{}, resourceContent map[string]interface{}) []PolicyRestriction {
+	res := make([]PolicyRestriction, 0)
+	responseMap, ok := responseBody.(map[string]interface{})
+	if !ok {
+		return res
+	}
+
+	// the evaluations of the resource content, only the non-compliant evaluations of the deny and audit policies are returned
+	if contentResult, ok := responseMap["contentEvaluationResult"].(map[string]interface{}); ok {
+		evaluations, _ := contentResult["policyEvaluations"].([]interface{})
+		for _, evaluation := range evaluations {
+			evaluationMap, ok := evaluation.(map[string]interface{})
+			if !ok || !strings.EqualFold(stringValue(evaluationMap["evaluationResult"]), "NonCompliant") {
+				continue
+			}
+			effect := ""
+			if effectDetails, ok := evaluationMap["effectDetails"].(map[string]interface{}); ok {
+				effect = stringValue(effectDetails["policyEffect"])
+			}
+			denied := strings.EqualFold(effect, "Deny")
+			if !denied && !strings.HasPrefix(strings.ToLower(effect), "audit") {
+				continue
+			}
+			field, expressions := flattenEvaluatedExpressions(evaluationMap["evaluationDetails"])
+			message := fmt.Sprintf("The resource is non-compliant with the policy %s, the policy effect is %s.", policyName(evaluationMap["policyInfo"]), effect)
+			if len(expressions) != 0 {
+				message += fmt.Sprintf(" The evaluated expressions are:\n%s", strings.Join(expressions, "\n"))
+			}
+			res = append(res, PolicyRestriction{
+				Field:   field,
+				Denied:  denied,
+				Message: message,
+			})
+		}
+	}
+
+	// the restrictions of the fields, they are returned as warnings because they don't necessarily block the request,
+	// except the deny restrictions whose denied values contain the configured value
+	fieldRestrictions, _ := responseMap["fieldRestrictions"].([]interface{})
+	for _, fieldRestriction := range fieldRestrictions {
+		fieldRestrictionMap, ok := fieldRestriction.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		field := stringValue(fieldRestrictionMap["field"])
+		restrictions, _ := fieldRestrictionMap["restrictions"].([]interface{})
+		for _, restriction := range restrictions {
+			restrictionMap, ok := restriction.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			message, denied := fieldRestrictionMessage(field, restrictionMap, resourceContent)
+			if message == "" {
+				continue
+			}
+			res = append(res, PolicyRestriction{
+				Field:   field,
+				Denied:  denied,
+				Message: message,
+			})
+		}
+	}
+	return res
+}
+
+// fieldRestrictionMessage returns the message of the field restriction, it returns an empty string if the configured value satisfies the restriction.
+// The second return value is true if the configured value is one of the denied values, the request will be disallowed by the policy.
+func fieldRestrictionMessage(field string, restriction map[string]interface{}, resourceContent map[string]interface{}) (string, bool) {
+	values, _ := restriction["values"].([]interface{})
+	current, found := fieldValue(resourceContent, field)
+	policy := policyName(restriction["policy"])
+
+	var message string
+	denied := false
+	switch result := stringValue(restriction["result"]); result {
+	case "Required":
+		if found && (len(values) == 0 || containsValue(values, current)) {
+			return "", false
+		}
+		message = fmt.Sprintf("The field %s is required by the policy %s", field, policy)
+		if len(values) != 0 {
+			message += fmt.Sprintf(", the allowed values are %s", formatValue(values))
+		}
+		if defaultValue, ok := restriction["defaultValue"]; ok && defaultValue != nil {
+			message += fmt.Sprintf(", the default value is %s", formatValue(defaultValue))
+		}
+	case "Removed":
+		if !found {
+			return "", false
+		}
+		message = fmt.Sprintf("The field %s will be removed by the policy %s", field, policy)
+	case "Deny":
+		if found && len(values) != 0 && !containsValue(values, current) {
+			return "", false
+		}
+		denied = found && len(values) != 0
+		message = fmt.Sprintf("The field %s is denied by the policy %s", field, policy)
+		if len(values) != 0 {
+			message += fmt.Sprintf(", the denied values are %s", formatValue(values))
+		}
+	case "Audit":
+		message = fmt.Sprintf("The field %s is audited by the policy %s", field, policy)
+	default:
+		message = fmt.Sprintf("The field %s is restricted by the policy %s, the restriction is %s", field, policy, result)
+	}
+	if reason := stringValue(restriction["reason"]); reason != "" {
+		message += fmt.Sprintf(", reason: %s", reason)
+	}
+	return message + ".", denied
+}
+
+// flattenEvaluatedExpressions returns the field of the first evaluated expression which isn't about the resource type, and the descriptions of the evaluated expressions
+func flattenEvaluatedExpressions(input interface{}) (string, []string) {
+	details, ok := input.(map[string]interface{})
+	if !ok {
+		return "", nil
+	}
+	expressions, _ := details["evaluatedExpressions"].([]interface{})
+	field := ""
+	res := make([]string, 0)
+	for _, expression := range expressions {
+		expressionMap, ok := expression.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		path := stringValue(expressionMap["path"])
+		if field == "" && path != "" && !strings.EqualFold(path, "type") {
+			field = path
+		}
+		res = append(res, fmt.Sprintf("  %s %s %s: actual value is %s", stringValue(expressionMap["expression"]), stringValue(expressionMap["operator"]),
+			formatValue(expressionMap["targetValue"]), formatValue(expressionMap["expressionValue"])))
+	}
+	return field, res
+}
+
+var tagFieldRegex = regexp.MustCompile(`^tags\[['"]?([^'"\]]+)['"]?\]$`)
+
+// fieldValue returns the value of the field in the resource content, the field is a policy field like `location`, `tags.environment`, `tags['environment']` or `properties.minimumTlsVersion`.
+// The aliases like `Microsoft.Storage/storageAccounts/minimumTlsVersion` are not supported.
+func fieldValue(resourceContent map[string]interface{}, field string) (interface{}, bool) {
+	if strings.Contains(field, "/") {
+		return nil, false
+	}
+	if matches := tagFieldRegex.FindStringSubmatch(field); len(matches) == 2 {
+		field = "tags." + matches[1]
+	}
+
+	var current interface{} = resourceContent
+	for _, segment := range strings.Split(field, ".") {
+		currentMap, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		found := false
+		for k, v := range currentMap {
+			if strings.EqualFold(k, segment) {
+				current, found = v, true
+				break
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if strings.EqualFold(formatValue(v), formatValue(value)) {
+			return true
+		}
+	}
+	return false
+}
+
+// policyName returns the policy assignment ID, or the policy definition ID if the assignment ID is not available
+func policyName(input interface{}) string {
+	policy, ok := input.(map[string]interface{})
+	if !ok {
+		return "unknown"
+	}
+	for _, key := range []string{"policyAssignmentId", "policyDefinitionId"} {
+		if v := stringValue(policy[key]); v != "" {
+			return v
+		}
+	}
+	return "unknown"
+}
+
+func stringValue(input interface{}) string {
+	if v, ok := input.(string); ok {
+		return v
+	}
+	return ""
+}
@@ -0,0 +1,221 @@
+package preflight
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func Test_PolicyCheckScope(t *testing.T) {
+	testcases := []struct {
+		ParentId      string
+		ExpectedScope string
+		ExpectedErr   bool
+	}{
+		{
+			ParentId:      "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg",
+			ExpectedScope: "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg",
+		},
+		{
+			ParentId:      "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet",
+			ExpectedScope: "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg",
+		},
+		{
+			ParentId:      "/subscriptions/00000000-0000-0000-0000-000000000000",
+			ExpectedScope: "/subscriptions/00000000-0000-0000-0000-000000000000",
+		},
+		{
+			ParentId:    "/providers/Microsoft.Management/managementGroups/mg",
+			ExpectedErr: true,
+		},
+		{
+			ParentId:    "/",
+			ExpectedErr: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		scope, err := policyCheckScope(tc.ParentId)
+		if tc.ExpectedErr != (err != nil) {
+			t.Fatalf("parentId %s: expect error %v, got %v", tc.ParentId, tc.ExpectedErr, err)
+		}
+		if scope != tc.ExpectedScope {
+			t.Fatalf("parentId %s: expect scope %s, got %s", tc.ParentId, tc.ExpectedScope, scope)
+		}
+	}
+}
+
+func Test_FlattenPolicyRestrictions(t *testing.T) {
+	response := `
+{
+  "fieldRestrictions": [
+    {
+      "field": "tags['environment']",
+      "restrictions": [
+        {
+          "result": "Required",
+          "values": ["dev", "prod"],
+          "policy": {
+            "policyAssignmentId": "/subscriptions/00000000-0000-0000-0000-000000000000/providers/Microsoft.Authorization/policyAssignments/requireEnvTag"
+          }
+        }
+      ]
+    },
+    {
+      "field": "properties.allowBlobPublicAccess",
+      "restrictions": [
+        {
+          "result": "Deny",
+          "values": [true],
+          "policy": {
+            "policyAssignmentId": "/subscriptions/00000000-0000-0000-0000-000000000000/providers/Microsoft.Authorization/policyAssignments/denyPublicAccess"
+          }
+        }
+      ]
+    },
+    {
+      "field": "properties.supportsHttpsTrafficOnly",
+      "restrictions": [
+        {
+          "result": "Deny",
+          "values": [false],
+          "policy": {
+            "policyAssignmentId": "/subscriptions/00000000-0000-0000-0000-000000000000/providers/Microsoft.Authorization/policyAssignments/denyHttp"
+          }
+        }
+      ]
+    },
+    {
+      "field": "location",
+      "restrictions": [
+        {
+          "result": "Required",
+          "values": ["westus", "eastus"],
+          "policy": {
+            "policyAssignmentId": "/subscriptions/00000000-0000-0000-0000-000000000000/providers/Microsoft.Authorization/policyAssignments/allowedLocations"
+          }
+        }
+      ]
+    }
+  ],
+  "contentEvaluationResult": {
+    "policyEvaluations": [
+      {
+        "policyInfo": {
+          "policyDefinitionId": "/providers/Microsoft.Authorization/policyDefinitions/minimumTls"
+        },
+        "evaluationResult": "NonCompliant",
+        "evaluationDetails": {
+          "evaluatedExpressions": [
+            {
+              "expression": "type",
+              "path": "type",
+              "expressionValue": "Microsoft.Storage/storageAccounts",
+              "targetValue": "Microsoft.Storage/storageAccounts",
+              "operator": "Equals",
+              "result": "True"
+            },
+            {
+              "expression": "Microsoft.Storage/storageAccounts/minimumTlsVersion",
+              "path": "properties.minimumTlsVersion",
+              "expressionValue": "TLS1_0",
+              "targetValue": "TLS1_2",
+              "operator": "NotEquals",
+              "result": "True"
+            }
+          ]
+        },
+        "effectDetails": {
+          "policyEffect": "Deny"
+        }
+      },
+      {
+        "policyInfo": {
+          "policyDefinitionId": "/providers/Microsoft.Authorization/policyDefinitions/auditHttps"
+        },
+        "evaluationResult": "NonCompliant",
+        "effectDetails": {
+          "policyEffect": "Audit"
+        }
+      },
+      {
+        "policyInfo": {
+          "policyDefinitionId": "/providers/Microsoft.Authorization/policyDefinitions/compliant"
+        },
+        "evaluationResult": "Compliant",
+        "effectDetails": {
+          "policyEffect": "Deny"
+        }
+      }
+    ]
+  }
+}
+`
+	var responseBody interface{}
+	if err := json.Unmarshal([]byte(response), &responseBody); err != nil {
+		t.Fatal(err)
+	}
+	resourceContent := map[string]interface{}{
+		"type":     "Microsoft.Storage/storageAccounts",
+		"location": "WestUS",
+		"tags": map[string]interface{}{
+			"owner": "me",
+		},
+		"properties": map[string]interface{}{
+			"minimumTlsVersion":        "TLS1_0",
+			"allowBlobPublicAccess":    true,
+			"supportsHttpsTrafficOnly": true,
+		},
+	}
+
+	restrictions := flattenPolicyRestrictions(responseBody, resourceContent)
+	if len(restrictions) != 4 {
+		t.Fatalf("expect 4 restrictions, got %d: %v", len(restrictions), restrictions)
+	}
+
+	if !restrictions[0].Denied || restrictions[0].Field != "properties.minimumTlsVersion" {
+		t.Fatalf("expect the deny restriction on properties.minimumTlsVersion, got %+v", restrictions[0])
+	}
+	if restrictions[1].Denied || restrictions[1].Field != "" {
+		t.Fatalf("expect the audit restriction on the resource, got %+v", restrictions[1])
+	}
+	// the location and the https restrictions are satisfied, only the tag restriction and the public access restriction are returned
+	if restrictions[2].Denied || restrictions[2].Field != "tags['environment']" {
+		t.Fatalf("expect the required restriction on tags['environment'], got %+v", restrictions[2])
+	}
+	if !restrictions[3].Denied || restrictions[3].Field != "properties.allowBlobPublicAccess" {
+		t.Fatalf("expect the deny restriction on properties.allowBlobPublicAccess, got %+v", restrictions[3])
+	}
+}
+
+func Test_FieldValue(t *testing.T) {
+	resourceContent := map[string]interface{}{
+		"location": "westus",
+		"tags": map[string]interface{}{
+			"environment": "dev",
+		},
+		"properties": map[string]interface{}{
+			"minimumTlsVersion": "TLS1_2",
+		},
+	}
+
+	testcases := []struct {
+		Field         string
+		ExpectedValue interface{}
+		ExpectedFound bool
+	}{
+		{Field: "location", ExpectedValue: "westus", ExpectedFound: true},
+		{Field: "tags.environment", ExpectedValue: "dev", ExpectedFound: true},
+		{Field: "tags['environment']", ExpectedValue: "dev", ExpectedFound: true},
+		{Field: "tags[environment]", ExpectedValue: "dev", ExpectedFound: true},
+		{Field: "properties.MinimumTlsVersion", ExpectedValue: "TLS1_2", ExpectedFound: true},
+		{Field: "tags.owner", ExpectedFound: false},
+		{Field: "Microsoft.Storage/storageAccounts/minimumTlsVersion", ExpectedFound: false},
+	}
+
+	for _, tc := range testcases {
+		value, found := fieldValue(resourceContent, tc.Field)
+		if found != tc.ExpectedFound || value != tc.ExpectedValue {
+			t.Fatalf("field %s: expect %v (found: %v), got %v (found: %v)", tc.Field, tc.ExpectedValue, tc.ExpectedFound, value, found)
+		}
+	}
+}
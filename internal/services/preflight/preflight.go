@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
@@ -56,25 +57,36 @@ func NamePlaceholder() string {
 }
 
 // IsSupported checks if the resource type is supported for preflight validation
-// The resource type should be a top-level resource type, and the specified parentID should be a resource group, subscription, tenant or management group
-// If the parentID is not specified, the resource type should be able to deploy only at the tenant, management group, subscription or resource group level
+// If the parentID is specified, the resource could be a top-level resource deployed at a resource group, subscription, tenant or management group,
+// a child resource of the parent resource, or an extension resource of the parent resource. The child and extension resource types must be known by the embedded schema.
+// If the parentID is not specified, the resource type should be a top-level resource type which can be deployed only at the tenant, management group, subscription or resource group level
 func IsSupported(resourceType string, parentId string) bool {
 	azureResourceType, apiVersion, err := utils.GetAzureResourceTypeApiVersion(resourceType)
 	if err != nil {
 		return false
 	}
 
-	if !utils.IsTopLevelResourceType(azureResourceType) {
-		return false
+	if parentId != "" {
+		if isDeploymentScope(utils.GetResourceType(parentId)) {
+			return true
+		}
+		// the parentID is a resource, the resource type is either a child resource type or an extension resource type
+		parent, err := arm.ParseResourceID(parentId)
+		if err != nil {
+			return false
+		}
+		resourceDef, err := azure.GetResourceDefinition(azureResourceType, apiVersion)
+		if err != nil || resourceDef == nil {
+			return false
+		}
+		if strings.EqualFold(utils.GetParentType(azureResourceType), parent.ResourceType.String()) {
+			return true
+		}
+		return slices.Contains(resourceDef.ScopeTypes, aztypes.Extension)
 	}
 
-	// if the parentID is specified, it should be a resource group, subscription, tenant or management group
-	if parentId != "" {
-		parentResourceType := utils.GetResourceType(parentId)
-		return strings.EqualFold(arm.ResourceGroupResourceType.String(), parentResourceType) ||
-			strings.EqualFold(arm.SubscriptionResourceType.String(), parentResourceType) ||
-			strings.EqualFold(arm.TenantResourceType.String(), parentResourceType) ||
-			strings.EqualFold("Microsoft.Management/managementGroups", parentResourceType)
+	if !utils.IsTopLevelResourceType(azureResourceType) {
+		return false
 	}
 
 	// if the parentID is not specified, the resource type should be able to deploy only at the tenant, management group, subscription or resource group level
@@ -91,33 +103,128 @@ func IsSupported(resourceType string, parentId string) bool {
 }
 
 // Validate validates the resource using the preflight API
-func Validate(ctx context.Context, client *clients.ResourceClient, resourceType string, parentId string, name string, location string, body types.Dynamic, identity types.List) error {
+// If the parentID or the name is not specified, placeholders are used. The validations of the resources which are deployed at the same scope
+// are sent in batches, and the results are cached by the configuration of the resource.
+func Validate(ctx context.Context, client *clients.ResourceClient, subscriptionId string, resourceType string, parentId string, name string, location string, body types.Dynamic, identity types.List) error {
 	azureResourceType, apiVersion, err := utils.GetAzureResourceTypeApiVersion(resourceType)
 	if err != nil {
 		return err
 	}
 
+	resource := make(map[string]interface{})
+	err = unmarshalPreflightBody(body, identity, &resource)
+	if err != nil {
+		tflog.Warn(ctx, fmt.Sprintf("Skipping preflight validation for resource %s because the body is invalid: %v", resourceType, err))
+		return nil
+	}
+
+	// the cache key is calculated before the placeholders are generated, because the placeholders are random
+	key := cacheKey(client, resourceType, parentId, name, location, resource)
+	if err, ok := defaultBatcher.Cached(ctx, key); ok {
+		tflog.Debug(ctx, fmt.Sprintf("Using the cached preflight validation result for resource %s", resourceType))
+		return err
+	}
+
+	if parentId == "" {
+		resourceDef, _ := azure.GetResourceDefinition(azureResourceType, apiVersion)
+		parentId, err = ParentIdPlaceholder(resourceDef, subscriptionId)
+		if err != nil {
+			tflog.Warn(ctx, fmt.Sprintf("Skipping preflight validation for resource %s because the parentID placeholder can't be generated: %v", resourceType, err))
+			return nil
+		}
+	}
+	if name == "" {
+		name = NamePlaceholder()
+	}
+
+	scope, fullName, err := resolveScope(azureResourceType, parentId, name)
+	if err != nil {
+		tflog.Warn(ctx, fmt.Sprintf("Skipping preflight validation for resource %s because the scope is invalid: %v", resourceType, err))
+		return nil
+	}
+
+	// the child resources and extension resources can't be validated if the parent resource doesn't exist
+	if !isDeploymentScope(utils.GetResourceType(parentId)) && !parentExists(ctx, client, parentId) {
+		tflog.Info(ctx, fmt.Sprintf("Skipping preflight validation for resource %s because the parent resource %s doesn't exist", resourceType, parentId))
+		return nil
+	}
+
 	payload := RequestBodyModel{}
 	payload.Provider, payload.Type, _ = strings.Cut(azureResourceType, "/")
-	payload.Scope = parentId
+	payload.Scope = scope
 	if location != "" {
 		payload.Location = location
 	}
 
-	resource := make(map[string]interface{})
-	err = unmarshalPreflightBody(body, identity, &resource)
+	// the resources of different types are validated in the same batch, so each resource specifies its own type
+	resource["type"] = azureResourceType
+	resource["name"] = fullName
+	resource["apiVersion"] = apiVersion
+
+	return defaultBatcher.Validate(ctx, client, payload, resource, key)
+}
+
+// resolveScope returns the scope which the resource is deployed at and the full name of the resource.
+// For a child resource, the scope is the scope of its top-level parent resource, and the name contains the names of its parent resources, for example, `vnet/subnet`.
+// For an extension resource, the scope is the extended resource.
+func resolveScope(azureResourceType string, parentId string, name string) (string, string, error) {
+	if isDeploymentScope(utils.GetResourceType(parentId)) {
+		return parentId, name, nil
+	}
+
+	parent, err := arm.ParseResourceID(parentId)
 	if err != nil {
-		tflog.Warn(ctx, fmt.Sprintf("Skipping preflight validation for resource %s because the body is invalid: %v", resourceType, err))
-		return nil
+		return "", "", err
 	}
 
-	resource["name"] = name
-	resource["apiVersion"] = apiVersion
+	// extension resource
+	if !strings.EqualFold(utils.GetParentType(azureResourceType), parent.ResourceType.String()) {
+		return parentId, name, nil
+	}
+
+	// child resource
+	names := []string{name}
+	current := parent
+	for current != nil && !isDeploymentScope(current.ResourceType.String()) && strings.EqualFold(current.ResourceType.Namespace, parent.ResourceType.Namespace) {
+		names = append([]string{current.Name}, names...)
+		current = current.Parent
+	}
+	if current == nil {
+		return "", "", fmt.Errorf("failed to find the scope of the parent resource %s", parentId)
+	}
+	scope := current.String()
+	if current.ResourceType.String() == arm.TenantResourceType.String() {
+		scope = "/"
+	}
+	return scope, strings.Join(names, "/"), nil
+}
+
+// parentExists checks if the parent resource exists, it uses the latest stable API version in the embedded schema.
+// It returns true if the existence can't be determined, so the preflight validation will still be performed.
+func parentExists(ctx context.Context, client *clients.ResourceClient, parentId string) bool {
+	apiVersion := latestStableApiVersion(azure.GetApiVersions(utils.GetResourceType(parentId)))
+	if apiVersion == "" {
+		return true
+	}
+	_, err := client.Get(ctx, parentId, apiVersion, clients.DefaultRequestOptions())
+	return !utils.ResponseErrorWasNotFound(err)
+}
 
-	payload.Resources = []map[string]interface{}{resource}
+// latestStableApiVersion returns the latest API version which isn't a preview version, the input API versions are sorted in ascending order.
+func latestStableApiVersion(apiVersions []string) string {
+	for i := len(apiVersions) - 1; i >= 0; i-- {
+		if !strings.Contains(strings.ToLower(apiVersions[i]), "preview") {
+			return apiVersions[i]
+		}
+	}
+	return ""
+}
 
-	_, err = client.Action(ctx, "/providers/Microsoft.Resources", "validateResources", "2020-10-01", "POST", payload, clients.DefaultRequestOptions())
-	return err
+func isDeploymentScope(resourceType string) bool {
+	return strings.EqualFold(arm.ResourceGroupResourceType.String(), resourceType) ||
+		strings.EqualFold(arm.SubscriptionResourceType.String(), resourceType) ||
+		strings.EqualFold(arm.TenantResourceType.String(), resourceType) ||
+		strings.EqualFold("Microsoft.Management/managementGroups", resourceType)
 }
 
 func unmarshalPreflightBody(input types.Dynamic, identityList types.List, out *map[string]interface{}) error {
@@ -135,7 +242,7 @@ func unmarshalPreflightBody(input types.Dynamic, identityList types.List, out *m
 		return fmt.Errorf("marshaling failed: %v", err)
 	}
 
-	if err = json.Unmarshal(data, &out); err != nil {
+	if err = utils.UnmarshalJSON(data, &out); err != nil {
 		return fmt.Errorf(`unmarshaling failed: value: %s, err: %+v`, string(data), err)
 	}
 
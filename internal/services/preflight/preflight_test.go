@@ -165,6 +165,34 @@ func Test_IsSupported(t *testing.T) {
 			ResourceType: "Microsoft.Network/virtualNetworks@2020-06-01",
 			Expected:     true,
 		},
+
+		{
+			// child resource
+			ParentId:     "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/azapifakerg/providers/Microsoft.Network/virtualNetworks/vnet",
+			ResourceType: "Microsoft.Network/virtualNetworks/subnets@2020-06-01",
+			Expected:     true,
+		},
+
+		{
+			// extension resource
+			ParentId:     "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/azapifakerg/providers/Microsoft.Storage/storageAccounts/sa",
+			ResourceType: "Microsoft.Authorization/roleAssignments@2022-04-01",
+			Expected:     true,
+		},
+
+		{
+			// neither a child resource nor an extension resource of the parent resource
+			ParentId:     "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/azapifakerg/providers/Microsoft.Storage/storageAccounts/sa",
+			ResourceType: "Microsoft.Network/virtualNetworks@2020-06-01",
+			Expected:     false,
+		},
+
+		{
+			// unknown resource type
+			ParentId:     "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/azapifakerg/providers/Microsoft.Network/virtualNetworks/vnet",
+			ResourceType: "Microsoft.Network/virtualNetworks/foo@2020-06-01",
+			Expected:     false,
+		},
 	}
 
 	for _, testcase := range testcases {
@@ -174,3 +202,92 @@ func Test_IsSupported(t *testing.T) {
 		}
 	}
 }
+
+func Test_LatestStableApiVersion(t *testing.T) {
+	testcases := []struct {
+		ApiVersions []string
+		Expected    string
+	}{
+		{
+			ApiVersions: []string{"2023-01-01", "2023-05-01", "2024-01-01-preview"},
+			Expected:    "2023-05-01",
+		},
+		{
+			ApiVersions: []string{"2023-01-01-preview"},
+			Expected:    "",
+		},
+		{
+			ApiVersions: nil,
+			Expected:    "",
+		},
+	}
+
+	for _, testcase := range testcases {
+		if actual := latestStableApiVersion(testcase.ApiVersions); actual != testcase.Expected {
+			t.Errorf("Expected %q, but got %q", testcase.Expected, actual)
+		}
+	}
+}
+
+func Test_ResolveScope(t *testing.T) {
+	testcases := []struct {
+		ResourceType  string
+		ParentId      string
+		Name          string
+		ExpectedScope string
+		ExpectedName  string
+		ExpectedErr   bool
+	}{
+		{
+			ResourceType:  "Microsoft.Network/virtualNetworks",
+			ParentId:      "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg",
+			Name:          "vnet",
+			ExpectedScope: "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg",
+			ExpectedName:  "vnet",
+		},
+		{
+			ResourceType:  "Microsoft.Network/virtualNetworks/subnets",
+			ParentId:      "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet",
+			Name:          "subnet",
+			ExpectedScope: "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg",
+			ExpectedName:  "vnet/subnet",
+		},
+		{
+			ResourceType:  "Microsoft.Sql/servers/databases/backupShortTermRetentionPolicies",
+			ParentId:      "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg/providers/Microsoft.Sql/servers/server/databases/db",
+			Name:          "default",
+			ExpectedScope: "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg",
+			ExpectedName:  "server/db/default",
+		},
+		{
+			ResourceType:  "Microsoft.Authorization/roleAssignments",
+			ParentId:      "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg/providers/Microsoft.Storage/storageAccounts/sa",
+			Name:          "00000000-0000-0000-0000-000000000001",
+			ExpectedScope: "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg/providers/Microsoft.Storage/storageAccounts/sa",
+			ExpectedName:  "00000000-0000-0000-0000-000000000001",
+		},
+		{
+			ResourceType: "Microsoft.Network/virtualNetworks/subnets",
+			ParentId:     "invalid",
+			Name:         "subnet",
+			ExpectedErr:  true,
+		},
+	}
+
+	for _, testcase := range testcases {
+		scope, name, err := resolveScope(testcase.ResourceType, testcase.ParentId, testcase.Name)
+		if testcase.ExpectedErr {
+			if err == nil {
+				t.Errorf("Expected error, but got nil")
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Expected no error, but got %v", err)
+			continue
+		}
+		if scope != testcase.ExpectedScope || name != testcase.ExpectedName {
+			t.Errorf("Expected %s and %s, but got %s and %s", testcase.ExpectedScope, testcase.ExpectedName, scope, name)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package preflight
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/terraform-provider-azapi/internal/clients"
+	"github.com/Azure/terraform-provider-azapi/utils"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const whatIfApiVersion = "2021-04-01"
+
+// PropertyChange is a property change predicted by the What-If operation
+type PropertyChange struct {
+	Path       string
+	ChangeType string
+	Before     interface{}
+	After      interface{}
+}
+
+func (c PropertyChange) String() string {
+	switch c.ChangeType {
+	case "Create":
+		return fmt.Sprintf("+ %s: %s", c.Path, formatValue(c.After))
+	case "Delete":
+		return fmt.Sprintf("- %s: %s", c.Path, formatValue(c.Before))
+	default:
+		return fmt.Sprintf("~ %s: %s => %s", c.Path, formatValue(c.Before), formatValue(c.After))
+	}
+}
+
+// IsWhatIfSupported checks if the resource can be previewed by the What-If operation
+// The resource should be deployed in a resource group, and it should not be an extension resource
+func IsWhatIfSupported(resourceId string) bool {
+	_, _, err := whatIfTemplateName(resourceId)
+	return err == nil
+}
+
+// WhatIf predicts the property changes of the resource by running a resource group scoped deployment What-If operation
+// The body is the request body which will be used to update the resource, it's deployed as the only resource in the template.
+func WhatIf(ctx context.Context, client *clients.ResourceClient, resourceId string, resourceType string, body map[string]interface{}) ([]PropertyChange, error) {
+	azureResourceType, apiVersion, err := utils.GetAzureResourceTypeApiVersion(resourceType)
+	if err != nil {
+		return nil, err
+	}
+
+	resourceGroupId, name, err := whatIfTemplateName(resourceId)
+	if err != nil {
+		return nil, err
+	}
+
+	resource := make(map[string]interface{})
+	for k, v := range body {
+		resource[k] = v
+	}
+	resource["type"] = azureResourceType
+	resource["apiVersion"] = apiVersion
+	resource["name"] = name
+
+	payload := map[string]interface{}{
+		"properties": map[string]interface{}{
+			"mode": "Incremental",
+			"template": map[string]interface{}{
+				"$schema":        "https://schema.management.azure.com/schemas/2019-04-01/deploymentTemplate.json#",
+				"contentVersion": "1.0.0.0",
+				"resources":      []interface{}{resource},
+			},
+		},
+	}
+
+	deploymentId := fmt.Sprintf("%s/providers/Microsoft.Resources/deployments/azapi-whatif-%s", resourceGroupId, NamePlaceholder())
+	responseBody, err := client.Action(ctx, deploymentId, "whatIf", whatIfApiVersion, "POST", payload, clients.DefaultRequestOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	return flattenWhatIfChanges(ctx, responseBody, resourceId), nil
+}
+
+// whatIfTemplateName returns the ID of the resource group where the resource is deployed,
+// and the name of the resource in the deployment template, for example, `vnet/subnet` for a subnet.
+func whatIfTemplateName(resourceId string) (string, string, error) {
+	id, err := arm.ParseResourceID(resourceId)
+	if err != nil {
+		return "", "", err
+	}
+	if id.ResourceGroupName == "" {
+		return "", "", fmt.Errorf("the resource %q is not deployed in a resource group", resourceId)
+	}
+
+	names := make([]string, 0)
+	for current := id; current != nil && current.ResourceType.String() != arm.ResourceGroupResourceType.String(); current = current.Parent {
+		if !strings.EqualFold(current.ResourceType.Namespace, id.ResourceType.Namespace) {
+			return "", "", fmt.Errorf("the resource %q is an extension resource", resourceId)
+		}
+		names = append([]string{current.Name}, names...)
+	}
+	if len(names) == 0 {
+		return "", "", fmt.Errorf("the resource %q is a resource group", resourceId)
+	}
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s", id.SubscriptionID, id.ResourceGroupName), strings.Join(names, "/"), nil
+}
+
+func flattenWhatIfChanges(ctx context.Context, responseBody interface{}, resourceId string) []PropertyChange {
+	res := make([]PropertyChange, 0)
+	responseMap, ok := responseBody.(map[string]interface{})
+	if !ok {
+		return res
+	}
+	// the changes might be in the properties bag or at the top level, depending on whether the result is polled
+	if properties, ok := responseMap["properties"].(map[string]interface{}); ok {
+		responseMap = properties
+	}
+	changes, ok := responseMap["changes"].([]interface{})
+	if !ok {
+		return res
+	}
+
+	for _, change := range changes {
+		changeMap, ok := change.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if id, ok := changeMap["resourceId"].(string); !ok || !strings.EqualFold(id, resourceId) {
+			continue
+		}
+		switch changeType := changeMap["changeType"]; changeType {
+		case "Modify":
+			res = append(res, flattenWhatIfDelta(changeMap["delta"], "")...)
+		case "Ignore", "Unsupported":
+			tflog.Info(ctx, fmt.Sprintf("What-If can't predict the changes of resource %s, change type: %v, reason: %v", resourceId, changeType, changeMap["unsupportedReason"]))
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Path < res[j].Path
+	})
+	return res
+}
+
+func flattenWhatIfDelta(input interface{}, parentPath string) []PropertyChange {
+	res := make([]PropertyChange, 0)
+	delta, ok := input.([]interface{})
+	if !ok {
+		return res
+	}
+	for _, item := range delta {
+		itemMap, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		path, _ := itemMap["path"].(string)
+		if parentPath != "" {
+			path = parentPath + "." + path
+		}
+		changeType, _ := itemMap["propertyChangeType"].(string)
+		if changeType == "NoEffect" {
+			continue
+		}
+		if children, ok := itemMap["children"].([]interface{}); ok && len(children) != 0 {
+			res = append(res, flattenWhatIfDelta(children, path)...)
+			continue
+		}
+		res = append(res, PropertyChange{
+			Path:       path,
+			ChangeType: changeType,
+			Before:     itemMap["before"],
+			After:      itemMap["after"],
+		})
+	}
+	return res
+}
+
+func formatValue(input interface{}) string {
+	if input == nil {
+		return "null"
+	}
+	data, err := json.Marshal(input)
+	if err != nil {
+		return fmt.Sprintf("%v", input)
+	}
+	return string(data)
+}
+
+// IsPlanned checks if the property change is covered by the paths which are changed in the terraform plan
+func (c PropertyChange) IsPlanned(plannedPaths []string) bool {
+	for _, p := range plannedPaths {
+		if strings.EqualFold(p, c.Path) || hasPathPrefix(c.Path, p) || hasPathPrefix(p, c.Path) {
+			return true
+		}
+	}
+	return false
+}
+
+// ChangedPaths returns the paths of the properties which are different between the old and the new value.
+// The arrays are compared as a whole, their paths don't contain the indexes of the items.
+func ChangedPaths(old interface{}, new interface{}) []string {
+	res := make([]string, 0)
+	collectChangedPaths(old, new, "", &res)
+	sort.Strings(res)
+	return res
+}
+
+func collectChangedPaths(old interface{}, new interface{}, path string, res *[]string) {
+	oldMap, oldOk := old.(map[string]interface{})
+	newMap, newOk := new.(map[string]interface{})
+	if oldOk && newOk {
+		for key, value := range oldMap {
+			collectChangedPaths(value, newMap[key], joinPath(path, key), res)
+		}
+		for key, value := range newMap {
+			if _, ok := oldMap[key]; !ok {
+				collectChangedPaths(nil, value, joinPath(path, key), res)
+			}
+		}
+		return
+	}
+	if utils.NormalizeJson(formatValue(old)) != utils.NormalizeJson(formatValue(new)) {
+		*res = append(*res, path)
+	}
+}
+
+func hasPathPrefix(path string, prefix string) bool {
+	return len(path) > len(prefix) && strings.EqualFold(path[:len(prefix)], prefix) && path[len(prefix)] == '.'
+}
+
+func joinPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
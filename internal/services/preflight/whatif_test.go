@@ -0,0 +1,140 @@
+package preflight
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func Test_WhatIfTemplateName(t *testing.T) {
+	testcases := []struct {
+		ResourceId              string
+		ExpectedResourceGroupId string
+		ExpectedName            string
+		ExpectedErr             bool
+	}{
+		{
+			ResourceId:              "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet",
+			ExpectedResourceGroupId: "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg",
+			ExpectedName:            "vnet",
+		},
+		{
+			ResourceId:              "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet/subnets/subnet",
+			ExpectedResourceGroupId: "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg",
+			ExpectedName:            "vnet/subnet",
+		},
+		{
+			ResourceId:  "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg/providers/Microsoft.KeyVault/vaults/kv/providers/Microsoft.Authorization/locks/lock",
+			ExpectedErr: true,
+		},
+		{
+			ResourceId:  "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg",
+			ExpectedErr: true,
+		},
+		{
+			ResourceId:  "/subscriptions/00000000-0000-0000-0000-000000000000/providers/Microsoft.Authorization/policyDefinitions/def",
+			ExpectedErr: true,
+		},
+	}
+
+	for _, testcase := range testcases {
+		resourceGroupId, name, err := whatIfTemplateName(testcase.ResourceId)
+		if testcase.ExpectedErr {
+			if err == nil {
+				t.Fatalf("expected an error for %s", testcase.ResourceId)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %+v", testcase.ResourceId, err)
+		}
+		if resourceGroupId != testcase.ExpectedResourceGroupId || name != testcase.ExpectedName {
+			t.Fatalf("expected %s and %s, got %s and %s", testcase.ExpectedResourceGroupId, testcase.ExpectedName, resourceGroupId, name)
+		}
+	}
+}
+
+func Test_FlattenWhatIfChanges(t *testing.T) {
+	resourceId := "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg/providers/Microsoft.Storage/storageAccounts/sa"
+	response := `{
+  "status": "Succeeded",
+  "properties": {
+    "changes": [
+      {
+        "resourceId": "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg/providers/Microsoft.Storage/storageAccounts/SA",
+        "changeType": "Modify",
+        "delta": [
+          {
+            "path": "properties.minimumTlsVersion",
+            "propertyChangeType": "Modify",
+            "before": "TLS1_0",
+            "after": "TLS1_2"
+          },
+          {
+            "path": "properties.networkAcls",
+            "propertyChangeType": "Modify",
+            "children": [
+              {
+                "path": "defaultAction",
+                "propertyChangeType": "Modify",
+                "before": "Allow",
+                "after": "Deny"
+              },
+              {
+                "path": "bypass",
+                "propertyChangeType": "NoEffect",
+                "before": "AzureServices",
+                "after": "azureservices"
+              }
+            ]
+          },
+          {
+            "path": "tags.env",
+            "propertyChangeType": "Delete",
+            "before": "test"
+          }
+        ]
+      },
+      {
+        "resourceId": "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg/providers/Microsoft.Storage/storageAccounts/other",
+        "changeType": "Modify",
+        "delta": [
+          {
+            "path": "properties.minimumTlsVersion",
+            "propertyChangeType": "Modify",
+            "before": "TLS1_0",
+            "after": "TLS1_2"
+          }
+        ]
+      }
+    ]
+  }
+}`
+	var responseBody interface{}
+	_ = json.Unmarshal([]byte(response), &responseBody)
+
+	changes := flattenWhatIfChanges(context.TODO(), responseBody, resourceId)
+	expected := []PropertyChange{
+		{Path: "properties.minimumTlsVersion", ChangeType: "Modify", Before: "TLS1_0", After: "TLS1_2"},
+		{Path: "properties.networkAcls.defaultAction", ChangeType: "Modify", Before: "Allow", After: "Deny"},
+		{Path: "tags.env", ChangeType: "Delete", Before: "test"},
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, changes)
+	}
+
+	plannedPaths := ChangedPaths(
+		map[string]interface{}{"properties": map[string]interface{}{"minimumTlsVersion": "TLS1_0"}, "tags": map[string]interface{}{"env": "test"}},
+		map[string]interface{}{"properties": map[string]interface{}{"minimumTlsVersion": "TLS1_2"}},
+	)
+	if !reflect.DeepEqual(plannedPaths, []string{"properties.minimumTlsVersion", "tags"}) {
+		t.Fatalf("unexpected planned paths: %v", plannedPaths)
+	}
+	if !changes[0].IsPlanned(plannedPaths) || changes[1].IsPlanned(plannedPaths) || !changes[2].IsPlanned(plannedPaths) {
+		t.Fatalf("unexpected planned changes for paths %v", plannedPaths)
+	}
+	if v := changes[2].String(); v != `- tags.env: "test"` {
+		t.Fatalf("unexpected string %s", v)
+	}
+}
@@ -9,6 +9,8 @@ import (
 
 	"github.com/Azure/terraform-provider-azapi/internal/azure"
 	aztypes "github.com/Azure/terraform-provider-azapi/internal/azure/types"
+	"github.com/Azure/terraform-provider-azapi/internal/features"
+	"github.com/Azure/terraform-provider-azapi/internal/locks"
 	"github.com/Azure/terraform-provider-azapi/internal/services/dynamic"
 	"github.com/Azure/terraform-provider-azapi/utils"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
@@ -34,6 +36,21 @@ func schemaValidation(azureResourceType, apiVersion string, resourceDef *aztypes
 		return schemaValidationError(fmt.Sprintf("the argument \"type\"'s api-version is invalid.\n The supported versions are [%s].\n", strings.Join(versions, ", ")))
 	}
 
+	return bodyValidation(resourceDef, body)
+}
+
+func dataPlaneSchemaValidation(azureResourceType, apiVersion string, resourceDef *aztypes.ResourceType, body interface{}) error {
+	log.Printf("[INFO] prepare validation for data plane resource type: %s, api-version: %s", azureResourceType, apiVersion)
+	// the embedded data plane schema only covers part of the data plane resource types and api-versions,
+	// skip the validation if the definition is not found
+	if resourceDef == nil {
+		log.Printf("[INFO] data plane resource type %s api-version %s is not found in the embedded schema, skip the validation", azureResourceType, apiVersion)
+		return nil
+	}
+	return bodyValidation(resourceDef, body)
+}
+
+func bodyValidation(resourceDef *aztypes.ResourceType, body interface{}) error {
 	if resourceDef != nil {
 		errors := (*resourceDef).Validate(utils.NormalizeObject(body), "")
 		if len(errors) != 0 {
@@ -91,6 +108,24 @@ func flattenBody(responseBody interface{}, resourceDef *aztypes.ResourceType) (t
 	return dynamic.FromJSONImplied(data)
 }
 
+// flattenDataPlaneBody returns the writable subset of the data plane response body, unlike `flattenBody`, the top-level fields are kept
+// because data plane resources don't have the ARM envelope properties. When there's no schema for the resource type, the commonly
+// server-managed fields are removed instead.
+func flattenDataPlaneBody(responseBody interface{}, resourceDef *aztypes.ResourceType) (types.Dynamic, error) {
+	body := utils.NormalizeObject(responseBody)
+	if resourceDef != nil {
+		body = (*resourceDef).GetWriteOnly(body)
+	} else {
+		body = utils.RemoveFields(body, dataPlaneReadOnlyFieldList())
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return types.DynamicNull(), err
+	}
+	return dynamic.FromJSONImplied(data)
+}
+
 func flattenOutput(responseBody interface{}, paths []string) attr.Value {
 	for _, path := range paths {
 		if path == "*" {
@@ -150,6 +185,37 @@ func flattenOutputJMES(responseBody interface{}, paths map[string]string) attr.V
 	return out
 }
 
+// arrayItemKeys returns the keys used to identify the array items in the body, the keys marked as identifiers in the schema are used,
+// and the keys configured in the `array_item_keys` are used for the arrays which don't have an identifier in the schema.
+// The identifiers of the known resource types are used for the other arrays.
+func arrayItemKeys(resourceType string, resourceDef *aztypes.ResourceType, body interface{}, config types.Map) map[string]string {
+	res := aztypes.GetKnownArrayItemIdentifiers(resourceType)
+	for path, key := range AsMapOfString(config) {
+		res[path] = key
+	}
+	if resourceDef != nil {
+		for path, key := range aztypes.GetArrayItemIdentifiers(resourceDef.AsTypeBase(), body) {
+			res[path] = key
+		}
+	}
+	return res
+}
+
+// exclusiveLockIds returns the IDs which are locked exclusively, the parent ID is included if the `auto_lock_parent` is enabled
+// and the resource type is known to conflict with the other child resources of the same parent resource.
+// The `auto_lock_parent` in the resource block takes precedence over the provider-level default.
+func exclusiveLockIds(lockIds types.List, autoLockParent types.Bool, userFeatures features.UserFeatures, resourceType string, parentId string) []string {
+	res := AsStringList(lockIds)
+	enabled := userFeatures.AutoLockParent
+	if !autoLockParent.IsNull() && !autoLockParent.IsUnknown() {
+		enabled = autoLockParent.ValueBool()
+	}
+	if enabled && parentId != "" && locks.NeedsParentLock(resourceType, userFeatures.AutoLockParentTypes) {
+		res = append(res, parentId)
+	}
+	return res
+}
+
 func AsStringList(input types.List) []string {
 	var result []string
 	diags := input.ElementsAs(context.Background(), &result, false)
@@ -185,7 +251,7 @@ func unmarshalBody(input types.Dynamic, out interface{}) error {
 	if err != nil {
 		return fmt.Errorf(`invalid dynamic value: value: %s, err: %+v`, input.String(), err)
 	}
-	if err = json.Unmarshal(data, &out); err != nil {
+	if err = utils.UnmarshalJSON(data, &out); err != nil {
 		return fmt.Errorf(`unmarshaling failed: value: %s, err: %+v`, string(data), err)
 	}
 	return nil
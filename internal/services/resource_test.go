@@ -5,7 +5,12 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/Azure/terraform-provider-azapi/internal/azure"
+	"github.com/Azure/terraform-provider-azapi/internal/features"
 	"github.com/Azure/terraform-provider-azapi/internal/services/dynamic"
+	"github.com/Azure/terraform-provider-azapi/utils"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
@@ -129,3 +134,268 @@ func Test_FlattenOutputJMES(t *testing.T) {
 		}
 	}
 }
+
+func Test_FlattenOutputJMESLargeInteger(t *testing.T) {
+	var responseBody interface{}
+	if err := utils.UnmarshalJSON([]byte(`{"properties":{"quota":9007199254740993,"limits":[{"name":"a","value":9007199254740993}]}}`), &responseBody); err != nil {
+		t.Fatal(err)
+	}
+
+	resultData := flattenOutputJMES(responseBody, map[string]string{
+		"quota":  "properties.quota",
+		"limits": "properties.limits[?value > `0`].value",
+	})
+	resultJson, err := dynamic.ToJSON(resultData.(types.Dynamic))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"limits":[9007199254740993],"quota":9007199254740993}`
+	if string(resultJson) != expected {
+		t.Fatalf("Expected %s but got %s", expected, string(resultJson))
+	}
+}
+
+func Test_DataPlaneSchemaValidation(t *testing.T) {
+	testcases := []struct {
+		ResourceType string
+		ApiVersion   string
+		Body         map[string]interface{}
+		ExpectError  bool
+	}{
+		{
+			ResourceType: "Microsoft.AppConfiguration/configurationStores/keyValues",
+			ApiVersion:   "1.0",
+			Body: map[string]interface{}{
+				"content_type": "",
+				"value":        "foo",
+			},
+			ExpectError: false,
+		},
+		{
+			ResourceType: "Microsoft.AppConfiguration/configurationStores/keyValues",
+			ApiVersion:   "1.0",
+			Body: map[string]interface{}{
+				"value": true,
+			},
+			ExpectError: true,
+		},
+		{
+			// api-version which is not embedded is not validated
+			ResourceType: "Microsoft.AppConfiguration/configurationStores/keyValues",
+			ApiVersion:   "2.0",
+			Body: map[string]interface{}{
+				"value": true,
+			},
+			ExpectError: false,
+		},
+		{
+			// resource type which is not embedded is not validated
+			ResourceType: "Microsoft.DeviceUpdate/accounts/groups",
+			ApiVersion:   "2022-10-01",
+			Body: map[string]interface{}{
+				"foo": "bar",
+			},
+			ExpectError: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		resourceDef, _ := azure.GetDataPlaneResourceDefinition(tc.ResourceType, tc.ApiVersion)
+		err := dataPlaneSchemaValidation(tc.ResourceType, tc.ApiVersion, resourceDef, tc.Body)
+		if tc.ExpectError != (err != nil) {
+			t.Errorf("resource type %s api-version %s: expect error %v, got %v", tc.ResourceType, tc.ApiVersion, tc.ExpectError, err)
+		}
+	}
+}
+
+func Test_FlattenDataPlaneBody(t *testing.T) {
+	testcases := []struct {
+		ResourceType string
+		ApiVersion   string
+		ResponseBody string
+		ExpectJson   string
+	}{
+		{
+			ResourceType: "Microsoft.KeyVault/vaults/secrets",
+			ApiVersion:   "7.4",
+			ResponseBody: `{"id":"https://myvault.vault.azure.net/secrets/foo/123","value":"bar","attributes":{"enabled":true,"created":1700000000,"updated":1700000000,"recoveryLevel":"Recoverable"}}`,
+			ExpectJson:   `{"value":"bar","attributes":{"enabled":true}}`,
+		},
+		{
+			// resource type which is not embedded drops the known read-only fields
+			ResourceType: "Microsoft.DeviceUpdate/accounts/groups",
+			ApiVersion:   "2022-10-01",
+			ResponseBody: `{"name":"foo","etag":"abc","attributes":{"enabled":true,"created":1700000000,"lastModified":"2024-01-01T00:00:00Z"},"systemData":{"createdBy":"user"}}`,
+			ExpectJson:   `{"name":"foo","attributes":{"enabled":true}}`,
+		},
+	}
+
+	for _, tc := range testcases {
+		var responseBody interface{}
+		if err := json.Unmarshal([]byte(tc.ResponseBody), &responseBody); err != nil {
+			t.Fatal(err)
+		}
+		resourceDef, _ := azure.GetDataPlaneResourceDefinition(tc.ResourceType, tc.ApiVersion)
+		actual, err := flattenDataPlaneBody(responseBody, resourceDef)
+		if err != nil {
+			t.Fatalf("resource type %s: %+v", tc.ResourceType, err)
+		}
+		actualJson, err := dynamic.ToJSON(actual)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var actualValue, expectValue interface{}
+		_ = json.Unmarshal(actualJson, &actualValue)
+		_ = json.Unmarshal([]byte(tc.ExpectJson), &expectValue)
+		if !reflect.DeepEqual(actualValue, expectValue) {
+			t.Errorf("resource type %s: expect %s, got %s", tc.ResourceType, tc.ExpectJson, string(actualJson))
+		}
+	}
+}
+
+func Test_ArrayItemKeys(t *testing.T) {
+	testcases := []struct {
+		Name         string
+		ResourceType string
+		Config       types.Map
+		Expected     map[string]string
+	}{
+		{
+			Name:         "built-in identifiers",
+			ResourceType: "Microsoft.Network/ApplicationGatewayWebApplicationFirewallPolicies",
+			Config:       types.MapNull(types.StringType),
+			Expected: map[string]string{
+				"properties.customRules": "priority",
+				"properties.managedRules.managedRuleSets.ruleGroupOverrides":       "ruleGroupName",
+				"properties.managedRules.managedRuleSets.ruleGroupOverrides.rules": "ruleId",
+			},
+		},
+		{
+			Name:         "configured keys override the built-in identifiers",
+			ResourceType: "Microsoft.Network/networkSecurityGroups",
+			Config: types.MapValueMust(types.StringType, map[string]attr.Value{
+				"properties.securityRules": types.StringValue("properties.priority"),
+			}),
+			Expected: map[string]string{
+				"properties.securityRules": "properties.priority",
+			},
+		},
+		{
+			Name:         "unknown resource type",
+			ResourceType: "Microsoft.Network/virtualNetworks",
+			Config:       types.MapNull(types.StringType),
+			Expected:     map[string]string{},
+		},
+	}
+
+	for _, tc := range testcases {
+		actual := arrayItemKeys(tc.ResourceType, nil, nil, tc.Config)
+		if !reflect.DeepEqual(actual, tc.Expected) {
+			t.Errorf("%s: expected %v, got %v", tc.Name, tc.Expected, actual)
+		}
+	}
+
+	// the custom rules which are reordered by the server are matched by the priority
+	var body, response interface{}
+	_ = json.Unmarshal([]byte(`{"properties":{"customRules":[{"priority":10,"action":"Block"},{"priority":20,"action":"Allow"}]}}`), &body)
+	_ = json.Unmarshal([]byte(`{"properties":{"customRules":[{"priority":20,"action":"Allow","state":"Enabled"},{"priority":10,"action":"Block","state":"Enabled"}]}}`), &response)
+	option := utils.UpdateJsonOption{
+		ArrayItemKeys: arrayItemKeys("Microsoft.Network/ApplicationGatewayWebApplicationFirewallPolicies", nil, body, types.MapNull(types.StringType)),
+	}
+	if actual := utils.UpdateObject(body, response, option); !reflect.DeepEqual(actual, body) {
+		t.Errorf("expected the reordered custom rules to match the body, got %v", actual)
+	}
+}
+
+func Test_ExclusiveLockIds(t *testing.T) {
+	vnetId := "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet"
+	lockIds := types.ListValueMust(types.StringType, []attr.Value{types.StringValue("lock")})
+
+	testcases := []struct {
+		Name           string
+		ResourceType   string
+		AutoLockParent types.Bool
+		Features       features.UserFeatures
+		Expected       []string
+	}{
+		{
+			Name:           "disabled by default",
+			ResourceType:   "Microsoft.Network/virtualNetworks/subnets",
+			AutoLockParent: types.BoolNull(),
+			Expected:       []string{"lock"},
+		},
+		{
+			Name:           "enabled in the provider block",
+			ResourceType:   "Microsoft.Network/virtualNetworks/subnets",
+			AutoLockParent: types.BoolNull(),
+			Features:       features.UserFeatures{AutoLockParent: true},
+			Expected:       []string{"lock", vnetId},
+		},
+		{
+			Name:           "disabled in the resource block",
+			ResourceType:   "Microsoft.Network/virtualNetworks/subnets",
+			AutoLockParent: types.BoolValue(false),
+			Features:       features.UserFeatures{AutoLockParent: true},
+			Expected:       []string{"lock"},
+		},
+		{
+			Name:           "enabled in the resource block",
+			ResourceType:   "Microsoft.Network/virtualNetworks/subnets",
+			AutoLockParent: types.BoolValue(true),
+			Expected:       []string{"lock", vnetId},
+		},
+		{
+			Name:           "unknown resource type",
+			ResourceType:   "Microsoft.Network/virtualNetworks/foos",
+			AutoLockParent: types.BoolValue(true),
+			Expected:       []string{"lock"},
+		},
+		{
+			Name:           "additional resource type",
+			ResourceType:   "Microsoft.Network/virtualNetworks/foos",
+			AutoLockParent: types.BoolValue(true),
+			Features:       features.UserFeatures{AutoLockParentTypes: []string{"microsoft.network/virtualnetworks/foos"}},
+			Expected:       []string{"lock", vnetId},
+		},
+	}
+
+	for _, tc := range testcases {
+		actual := exclusiveLockIds(lockIds, tc.AutoLockParent, tc.Features, tc.ResourceType, vnetId)
+		if !reflect.DeepEqual(actual, tc.Expected) {
+			t.Errorf("%s: expected %v, got %v", tc.Name, tc.Expected, actual)
+		}
+	}
+}
+
+func Test_PolicyFieldPath(t *testing.T) {
+	body, err := dynamic.FromJSONImplied([]byte(`{"properties":{"minimumTlsVersion":"TLS1_0","networkAcls":{"ipRules":[{"value":"1.1.1.1"}]}},"tags":{"environment":"dev"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	model := &AzapiResourceModel{
+		Body:     body,
+		Identity: types.ListNull(types.ObjectType{}),
+		Location: types.StringValue("westus"),
+		Tags:     types.MapNull(types.StringType),
+	}
+
+	testcases := []struct {
+		Field    string
+		Expected path.Path
+	}{
+		{Field: "name", Expected: path.Root("name")},
+		{Field: "location", Expected: path.Root("location")},
+		{Field: "properties.MinimumTlsVersion", Expected: path.Root("body").AtName("properties").AtName("minimumTlsVersion")},
+		{Field: "properties.networkAcls.ipRules[*].value", Expected: path.Root("body").AtName("properties").AtName("networkAcls").AtName("ipRules")},
+		{Field: "properties.encryption.keySource", Expected: path.Root("body").AtName("properties")},
+		{Field: "tags['environment']", Expected: path.Root("body").AtName("tags").AtName("environment")},
+		{Field: "Microsoft.Storage/storageAccounts/minimumTlsVersion", Expected: path.Root("body")},
+	}
+
+	for _, tc := range testcases {
+		if actual := policyFieldPath(tc.Field, model); !actual.Equal(tc.Expected) {
+			t.Errorf("field %s: expect path %s, got %s", tc.Field, tc.Expected, actual)
+		}
+	}
+}
@@ -0,0 +1,413 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	aztypes "github.com/Azure/terraform-provider-azapi/internal/azure/types"
+	"github.com/Azure/terraform-provider-azapi/internal/clients"
+	"github.com/Azure/terraform-provider-azapi/internal/docstrings"
+	"github.com/Azure/terraform-provider-azapi/internal/features"
+	"github.com/Azure/terraform-provider-azapi/internal/locks"
+	"github.com/Azure/terraform-provider-azapi/internal/retry"
+	"github.com/Azure/terraform-provider-azapi/internal/services/defaults"
+	"github.com/Azure/terraform-provider-azapi/internal/services/dynamic"
+	"github.com/Azure/terraform-provider-azapi/internal/services/myplanmodifier"
+	"github.com/Azure/terraform-provider-azapi/internal/services/myvalidator"
+	"github.com/Azure/terraform-provider-azapi/internal/services/parse"
+	"github.com/Azure/terraform-provider-azapi/utils"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// FlagRestoreOnDestroy is the private state key which stores the original values of the properties specified in the `body`.
+const FlagRestoreOnDestroy = "restore_on_destroy"
+
+// privateState is used to access the private state in the create, update and delete responses.
+type privateState interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
+// updateTarget is the existing resource which is updated by azapi_update_resource and azapi_data_plane_update_resource.
+// The requests are sent by either the resource manager client or the data plane client, the other logic is shared.
+type updateTarget struct {
+	ID           string
+	Description  string
+	ResourceType string
+	ResourceDef  *aztypes.ResourceType
+
+	get            func(ctx context.Context, options clients.RequestOptions) (interface{}, error)
+	createOrUpdate func(ctx context.Context, body interface{}, options clients.RequestOptions) error
+	// exists is used to check the response of the GET request, some resource manager APIs return 200 for a resource which doesn't exist
+	exists func(responseBody interface{}) bool
+}
+
+func newResourceManagerUpdateTarget(client clients.Requester, id parse.ResourceId) updateTarget {
+	return updateTarget{
+		ID:           id.ID(),
+		Description:  id.String(),
+		ResourceType: id.AzureResourceType,
+		ResourceDef:  id.ResourceDef,
+		get: func(ctx context.Context, options clients.RequestOptions) (interface{}, error) {
+			return client.Get(ctx, id.AzureResourceId, id.ApiVersion, options)
+		},
+		createOrUpdate: func(ctx context.Context, body interface{}, options clients.RequestOptions) error {
+			_, err := client.CreateOrUpdate(ctx, id.AzureResourceId, id.ApiVersion, body, options)
+			return err
+		},
+		exists: func(responseBody interface{}) bool {
+			return utils.GetId(responseBody) != nil
+		},
+	}
+}
+
+func newDataPlaneUpdateTarget(client clients.DataPlaneRequester, id parse.DataPlaneResourceId) updateTarget {
+	return updateTarget{
+		ID:           id.ID(),
+		Description:  id.String(),
+		ResourceType: id.AzureResourceType,
+		ResourceDef:  id.ResourceDef,
+		get: func(ctx context.Context, options clients.RequestOptions) (interface{}, error) {
+			return client.Get(ctx, id, options)
+		},
+		createOrUpdate: func(ctx context.Context, body interface{}, options clients.RequestOptions) error {
+			_, err := client.CreateOrUpdateThenPoll(ctx, id, body, options)
+			return err
+		},
+	}
+}
+
+// updateOptions are the arguments shared by azapi_update_resource and azapi_data_plane_update_resource.
+type updateOptions struct {
+	Body                  types.Dynamic
+	IgnoreCasing          bool
+	IgnoreMissingProperty bool
+	ArrayItemKeys         types.Map
+	MergeStrategy         string
+	RestoreOnDestroy      bool
+	ResponseExportValues  types.Dynamic
+	Locks                 []string
+	SharedLocks           []string
+	ReadOptions           clients.RequestOptions
+	UpdateOptions         clients.RequestOptions
+}
+
+// updateResourceAttributes returns the schema attributes shared by azapi_update_resource and azapi_data_plane_update_resource.
+func updateResourceAttributes(ctx context.Context, resourceName string) map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"type": schema.StringAttribute{
+			Required: true,
+			Validators: []validator.String{
+				myvalidator.StringIsResourceType(),
+			},
+			MarkdownDescription: docstrings.Type(),
+		},
+
+		// The body attribute is a dynamic attribute that only allows users to specify the resource body as an HCL object
+		"body": schema.DynamicAttribute{
+			Optional: true,
+			PlanModifiers: []planmodifier.Dynamic{
+				myplanmodifier.DynamicUseStateWhen(dynamic.SemanticallyEqual),
+			},
+			MarkdownDescription: docstrings.Body(),
+			Validators: []validator.Dynamic{
+				myvalidator.DynamicIsNotStringValidator(),
+			},
+		},
+
+		"ignore_casing": schema.BoolAttribute{
+			Optional:            true,
+			Computed:            true,
+			Default:             defaults.BoolDefault(false),
+			MarkdownDescription: docstrings.IgnoreCasing(),
+		},
+
+		"ignore_missing_property": schema.BoolAttribute{
+			Optional:            true,
+			Computed:            true,
+			Default:             defaults.BoolDefault(true),
+			MarkdownDescription: docstrings.IgnoreMissingProperty(),
+		},
+
+		"array_item_keys": schema.MapAttribute{
+			ElementType:         types.StringType,
+			Optional:            true,
+			MarkdownDescription: docstrings.ArrayItemKeys(),
+		},
+
+		"merge_strategy": schema.StringAttribute{
+			Optional: true,
+			Computed: true,
+			Default:  defaults.StringDefault(utils.MergeStrategyDeep),
+			Validators: []validator.String{
+				stringvalidator.OneOf(utils.MergeStrategyDeep, utils.MergeStrategyMergePatch, utils.MergeStrategyArrayByKey),
+			},
+			MarkdownDescription: docstrings.MergeStrategy(),
+		},
+
+		"restore_on_destroy": schema.BoolAttribute{
+			Optional:            true,
+			Computed:            true,
+			Default:             defaults.BoolDefault(false),
+			MarkdownDescription: "Whether to restore the original values of the properties specified in the `body` when this resource is destroyed. The original values are captured before the properties are updated for the first time. Properties which don't exist before the update are left as is. Defaults to `false`.",
+		},
+
+		"response_export_values": schema.DynamicAttribute{
+			Optional: true,
+			PlanModifiers: []planmodifier.Dynamic{
+				myplanmodifier.DynamicUseStateWhen(dynamic.SemanticallyEqual),
+			},
+			MarkdownDescription: docstrings.ResponseExportValues(),
+		},
+
+		"locks": schema.ListAttribute{
+			ElementType: types.StringType,
+			Optional:    true,
+			Validators: []validator.List{
+				listvalidator.ValueStringsAre(myvalidator.StringIsNotEmpty()),
+			},
+			MarkdownDescription: docstrings.Locks(),
+		},
+
+		"shared_locks": schema.ListAttribute{
+			ElementType: types.StringType,
+			Optional:    true,
+			Validators: []validator.List{
+				listvalidator.ValueStringsAre(myvalidator.StringIsNotEmpty()),
+			},
+			MarkdownDescription: docstrings.SharedLocks(),
+		},
+
+		"output": schema.DynamicAttribute{
+			Computed:            true,
+			MarkdownDescription: docstrings.Output(resourceName),
+		},
+
+		"retry": retry.RetrySchema(ctx),
+
+		"update_headers": schema.MapAttribute{
+			ElementType:         types.StringType,
+			Optional:            true,
+			MarkdownDescription: "A mapping of headers to be sent with the update request.",
+		},
+
+		"update_query_parameters": schema.MapAttribute{
+			ElementType: types.ListType{
+				ElemType: types.StringType,
+			},
+			Optional:            true,
+			MarkdownDescription: "A mapping of query parameters to be sent with the update request.",
+		},
+
+		"read_headers": schema.MapAttribute{
+			ElementType:         types.StringType,
+			Optional:            true,
+			MarkdownDescription: "A mapping of headers to be sent with the read request.",
+		},
+
+		"read_query_parameters": schema.MapAttribute{
+			ElementType: types.ListType{
+				ElemType: types.StringType,
+			},
+			Optional:            true,
+			MarkdownDescription: "A mapping of query parameters to be sent with the read request.",
+		},
+	}
+}
+
+// applyUpdate merges the `body` into the existing resource and sends the update request.
+// If `restore_on_destroy` is enabled, the original values of the properties in the `body` are saved in the private state.
+// The locks are held from reading the existing resource to sending the update request, so the concurrent updates on the same target don't overwrite each other.
+func applyUpdate(ctx context.Context, target updateTarget, options updateOptions, private privateState, isNewResource bool) diag.Diagnostics {
+	var diags diag.Diagnostics
+	unlock, err := locks.Acquire(ctx, target.ID, options.Locks, options.SharedLocks)
+	if err != nil {
+		diags.AddError("Failed to acquire locks", err.Error())
+		return diags
+	}
+	defer unlock()
+
+	existing, err := target.get(ctx, options.ReadOptions)
+	if err != nil && !utils.ResponseErrorWasNotFound(err) {
+		diags.AddError("Failed to retrieve resource", fmt.Errorf("checking for presence of existing %s: %+v", target.Description, err).Error())
+		return diags
+	}
+	if err != nil || (target.exists != nil && !target.exists(existing)) {
+		diags.AddError("Failed to retrieve resource", fmt.Errorf("update target does not exist %s", target.Description).Error())
+		return diags
+	}
+
+	var requestBody interface{}
+	if err := unmarshalBody(options.Body, &requestBody); err != nil {
+		diags.AddError("Invalid body", fmt.Sprintf(`The argument "body" is invalid: err: %+v`, err))
+		return diags
+	}
+
+	keys := arrayItemKeys(target.ResourceType, target.ResourceDef, requestBody, options.ArrayItemKeys)
+
+	var originalBody []byte
+	if options.RestoreOnDestroy {
+		originalBody, diags = captureOriginalBody(ctx, private, isNewResource, requestBody, existing, options.MergeStrategy, keys)
+		if diags.HasError() {
+			return diags
+		}
+	}
+
+	requestBody = utils.MergeObjectWithStrategy(existing, requestBody, options.MergeStrategy, keys)
+
+	if target.ResourceDef != nil {
+		requestBody = (*target.ResourceDef).GetWriteOnly(utils.NormalizeObject(requestBody))
+	}
+
+	if err := target.createOrUpdate(ctx, requestBody, options.UpdateOptions); err != nil {
+		diags.AddError("Failed to update resource", fmt.Errorf("updating %q: %+v", target.Description, err).Error())
+		return diags
+	}
+
+	if originalBody != nil {
+		diags.Append(private.SetKey(ctx, FlagRestoreOnDestroy, originalBody)...)
+	}
+	return diags
+}
+
+// restoreUpdate restores the original values which are saved in the private state by applyUpdate.
+func restoreUpdate(ctx context.Context, target updateTarget, options updateOptions, private privateState) diag.Diagnostics {
+	originalData, diags := private.GetKey(ctx, FlagRestoreOnDestroy)
+	if diags.HasError() {
+		return diags
+	}
+	if originalData == nil {
+		diags.AddWarning("Unable to restore the original values", fmt.Sprintf("The original values of resource %q were not captured, because `restore_on_destroy` was enabled after the resource was created.", target.ID))
+		return diags
+	}
+
+	var originalBody interface{}
+	if err := utils.UnmarshalJSON(originalData, &originalBody); err != nil {
+		diags.AddError("Invalid original values", fmt.Errorf("unmarshalling the original values: %+v", err).Error())
+		return diags
+	}
+
+	unlock, err := locks.Acquire(ctx, target.ID, options.Locks, options.SharedLocks)
+	if err != nil {
+		diags.AddError("Failed to acquire locks", err.Error())
+		return diags
+	}
+	defer unlock()
+
+	existing, err := target.get(ctx, options.ReadOptions)
+	if err != nil {
+		if utils.ResponseErrorWasNotFound(err) {
+			tflog.Info(ctx, fmt.Sprintf("[INFO] %q doesn't exist - skip restoring the original values", target.ID))
+			return diags
+		}
+		diags.AddError("Failed to retrieve resource", fmt.Errorf("reading %s: %+v", target.Description, err).Error())
+		return diags
+	}
+
+	requestBody := utils.MergeObjectWithStrategy(existing, originalBody, options.MergeStrategy, arrayItemKeys(target.ResourceType, target.ResourceDef, originalBody, options.ArrayItemKeys))
+
+	if target.ResourceDef != nil {
+		requestBody = (*target.ResourceDef).GetWriteOnly(utils.NormalizeObject(requestBody))
+	}
+
+	if err := target.createOrUpdate(ctx, requestBody, options.UpdateOptions); err != nil {
+		diags.AddError("Failed to restore resource", fmt.Errorf("restoring the original values of %q: %+v", target.Description, err).Error())
+	}
+	return diags
+}
+
+// captureOriginalBody returns the values of the paths specified in the `body` from the existing resource.
+// The values captured by the previous updates are kept, so the paths which are added to the `body` later are captured before they're updated.
+func captureOriginalBody(ctx context.Context, private privateState, isNewResource bool, requestBody interface{}, existing interface{}, mergeStrategy string, arrayItemKeys map[string]string) ([]byte, diag.Diagnostics) {
+	var previous interface{}
+	if !isNewResource {
+		data, diags := private.GetKey(ctx, FlagRestoreOnDestroy)
+		if diags.HasError() {
+			return nil, diags
+		}
+		// restore_on_destroy is enabled after the resource is created, the existing values have already been updated
+		if data == nil {
+			return nil, nil
+		}
+		if err := utils.UnmarshalJSON(data, &previous); err != nil {
+			return nil, diag.Diagnostics{diag.NewErrorDiagnostic("Invalid original values", fmt.Errorf("unmarshalling the original values: %+v", err).Error())}
+		}
+	}
+
+	if mergeStrategy == utils.MergeStrategyArrayByKey {
+		existing = utils.SelectObjectByKey(requestBody, existing, arrayItemKeys)
+	}
+	original := utils.UpdateObject(requestBody, existing, utils.UpdateJsonOption{})
+	if previous != nil {
+		original = utils.MergeObject(original, previous)
+	}
+
+	data, err := json.Marshal(original)
+	if err != nil {
+		return nil, diag.Diagnostics{diag.NewErrorDiagnostic("Invalid original values", fmt.Errorf("marshalling the original values: %+v", err).Error())}
+	}
+	return data, nil
+}
+
+// flattenUpdateBody returns the `body` in the state, which only contains the properties specified in the configured `body`.
+func flattenUpdateBody(ctx context.Context, target updateTarget, options updateOptions, responseBody interface{}) (types.Dynamic, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if options.Body.IsNull() {
+		return options.Body, diags
+	}
+
+	requestBody := make(map[string]interface{})
+	if err := unmarshalBody(options.Body, &requestBody); err != nil {
+		diags.AddError("Invalid body", fmt.Sprintf(`The argument "body" is invalid: %s`, err.Error()))
+		return options.Body, diags
+	}
+
+	keys := arrayItemKeys(target.ResourceType, target.ResourceDef, requestBody, options.ArrayItemKeys)
+
+	// only the array items specified in the body are compared, the other items are not managed by this resource
+	if options.MergeStrategy == utils.MergeStrategyArrayByKey {
+		responseBody = utils.SelectObjectByKey(requestBody, responseBody, keys)
+	}
+
+	option := utils.UpdateJsonOption{
+		IgnoreCasing:          options.IgnoreCasing,
+		IgnoreMissingProperty: options.IgnoreMissingProperty,
+		ArrayItemKeys:         keys,
+	}
+	body := utils.UpdateObject(requestBody, responseBody, option)
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		diags.AddError("Invalid body", err.Error())
+		return options.Body, diags
+	}
+
+	payload, err := dynamic.FromJSON(data, options.Body.UnderlyingValue().Type(ctx))
+	if err != nil {
+		tflog.Warn(ctx, fmt.Sprintf("Failed to parse payload: %s", err.Error()))
+		payload, err = dynamic.FromJSONImplied(data)
+		if err != nil {
+			diags.AddError("Invalid payload", err.Error())
+			return options.Body, diags
+		}
+	}
+	return payload, diags
+}
+
+// flattenUpdateOutput returns the `output` built from the response body, the read-only properties are exported by default.
+func flattenUpdateOutput(target updateTarget, responseBody interface{}, responseExportValues types.Dynamic, features features.UserFeatures) (types.Dynamic, error) {
+	var defaultOutput interface{}
+	if !features.DisableDefaultOutput {
+		defaultOutput = target.ResourceDef.GetReadOnly(responseBody)
+		defaultOutput = utils.RemoveFields(defaultOutput, volatileFieldList())
+	}
+	return buildOutputFromBody(responseBody, responseExportValues, defaultOutput)
+}
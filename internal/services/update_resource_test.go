@@ -0,0 +1,167 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/terraform-provider-azapi/internal/clients"
+	"github.com/Azure/terraform-provider-azapi/internal/services/dynamic"
+	"github.com/Azure/terraform-provider-azapi/utils"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type fakePrivateState map[string][]byte
+
+func (s fakePrivateState) GetKey(_ context.Context, key string) ([]byte, diag.Diagnostics) {
+	return s[key], nil
+}
+
+func (s fakePrivateState) SetKey(_ context.Context, key string, value []byte) diag.Diagnostics {
+	s[key] = value
+	return nil
+}
+
+func newFakeUpdateTarget(existing interface{}) (updateTarget, *interface{}) {
+	current := &existing
+	mu := &sync.Mutex{}
+	return updateTarget{
+		ID:          "/subscriptions/000/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet",
+		Description: "virtual network vnet",
+		get: func(_ context.Context, _ clients.RequestOptions) (interface{}, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if *current == nil {
+				return nil, &azcore.ResponseError{StatusCode: http.StatusNotFound}
+			}
+			// the merge modifies the returned value, so a copy is returned like the response of a real request
+			return utils.NormalizeObject(*current), nil
+		},
+		createOrUpdate: func(_ context.Context, body interface{}, _ clients.RequestOptions) error {
+			mu.Lock()
+			defer mu.Unlock()
+			*current = body
+			return nil
+		},
+	}, current
+}
+
+func Test_ApplyAndRestoreUpdate(t *testing.T) {
+	var existing interface{}
+	_ = utils.UnmarshalJSON([]byte(`{"properties":{"enableDdosProtection":false,"addressSpace":{"addressPrefixes":["10.0.0.0/16"]}}}`), &existing)
+	target, current := newFakeUpdateTarget(existing)
+
+	body, err := dynamic.FromJSONImplied([]byte(`{"properties":{"enableDdosProtection":true}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	options := updateOptions{
+		Body:             body,
+		ArrayItemKeys:    types.MapNull(types.StringType),
+		MergeStrategy:    utils.MergeStrategyDeep,
+		RestoreOnDestroy: true,
+	}
+	private := fakePrivateState{}
+
+	if diags := applyUpdate(context.Background(), target, options, private, true); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	properties := (*current).(map[string]interface{})["properties"].(map[string]interface{})
+	if properties["enableDdosProtection"] != true || properties["addressSpace"] == nil {
+		t.Fatalf("expect the body to be merged into the existing resource, got %v", *current)
+	}
+
+	if diags := restoreUpdate(context.Background(), target, options, private); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if !reflect.DeepEqual(*current, existing) {
+		t.Fatalf("expect the original values to be restored, got %v", *current)
+	}
+}
+
+func Test_ApplyUpdateArrayByKey(t *testing.T) {
+	var existing interface{}
+	_ = utils.UnmarshalJSON([]byte(`{"properties":{"securityRules":[{"name":"allow-http","properties":{"priority":100,"access":"Allow"}},{"name":"allow-https","properties":{"priority":200,"access":"Allow"}}]}}`), &existing)
+	target, current := newFakeUpdateTarget(existing)
+
+	body, err := dynamic.FromJSONImplied([]byte(`{"properties":{"securityRules":[{"properties":{"priority":200,"access":"Deny"}}]}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	options := updateOptions{
+		Body:          body,
+		MergeStrategy: utils.MergeStrategyArrayByKey,
+		ArrayItemKeys: types.MapValueMust(types.StringType, map[string]attr.Value{
+			"properties.securityRules": types.StringValue("properties.priority"),
+		}),
+	}
+
+	if diags := applyUpdate(context.Background(), target, options, fakePrivateState{}, true); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	var expected interface{}
+	_ = utils.UnmarshalJSON([]byte(`{"properties":{"securityRules":[{"name":"allow-http","properties":{"priority":100,"access":"Allow"}},{"name":"allow-https","properties":{"priority":200,"access":"Deny"}}]}}`), &expected)
+	if !reflect.DeepEqual(utils.NormalizeObject(*current), utils.NormalizeObject(expected)) {
+		t.Fatalf("expect the items to be matched by the configured key, got %v", *current)
+	}
+}
+
+func Test_ApplyUpdateConcurrently(t *testing.T) {
+	var existing interface{}
+	_ = utils.UnmarshalJSON([]byte(`{"properties":{"securityRules":[]}}`), &existing)
+	target, current := newFakeUpdateTarget(existing)
+	// the updates read the same existing resource if the locks are acquired after the read
+	get := target.get
+	target.get = func(ctx context.Context, options clients.RequestOptions) (interface{}, error) {
+		res, err := get(ctx, options)
+		time.Sleep(50 * time.Millisecond)
+		return res, err
+	}
+
+	var wg sync.WaitGroup
+	results := make(chan diag.Diagnostics, 2)
+	for _, rule := range []string{"allow-http", "allow-https"} {
+		body, err := dynamic.FromJSONImplied([]byte(fmt.Sprintf(`{"properties":{"securityRules":[{"name":%q}]}}`, rule)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		options := updateOptions{
+			Body:          body,
+			ArrayItemKeys: types.MapNull(types.StringType),
+			MergeStrategy: utils.MergeStrategyArrayByKey,
+			Locks:         []string{target.ID},
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results <- applyUpdate(context.Background(), target, options, fakePrivateState{}, true)
+		}()
+	}
+	wg.Wait()
+	close(results)
+	for diags := range results {
+		if diags.HasError() {
+			t.Fatalf("unexpected error: %v", diags)
+		}
+	}
+
+	rules := (*current).(map[string]interface{})["properties"].(map[string]interface{})["securityRules"].([]interface{})
+	if len(rules) != 2 {
+		t.Fatalf("expect both updates to be applied, got %v", *current)
+	}
+}
+
+func Test_ApplyUpdateTargetNotExist(t *testing.T) {
+	target, _ := newFakeUpdateTarget(nil)
+	diags := applyUpdate(context.Background(), target, updateOptions{ArrayItemKeys: types.MapNull(types.StringType), MergeStrategy: utils.MergeStrategyDeep}, fakePrivateState{}, true)
+	if !diags.HasError() || diags.Errors()[0].Detail() != "update target does not exist virtual network vnet" {
+		t.Fatalf("expect an error that the update target does not exist, got %v", diags)
+	}
+}
@@ -0,0 +1,123 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Azure/terraform-provider-azapi/version"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// TracerName is the name of the tracer which creates the spans of the provider
+	TracerName = "github.com/Azure/terraform-provider-azapi"
+
+	// AttributeResourceID is the attribute of the resource ID, it's the same as the `resource_id` field in the logs
+	AttributeResourceID = "resource_id"
+
+	serviceName = "terraform-provider-azapi"
+)
+
+// Tracer returns the tracer of the provider, the spans are not exported unless the tracer provider is configured by Configure
+func Tracer() trace.Tracer {
+	return otel.Tracer(TracerName, trace.WithInstrumentationVersion(version.ProviderVersion))
+}
+
+// Start starts a span with the resource ID, the resource ID is omitted if it's empty
+func Start(ctx context.Context, name string, resourceId string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if resourceId != "" {
+		attrs = append(attrs, attribute.String(AttributeResourceID, resourceId))
+	}
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records the error if it's not nil and ends the span
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// EndWithDiagnostics records the error diagnostics and ends the span, the diagnostics are passed by pointer,
+// so it can be deferred before the diagnostics are added.
+func EndWithDiagnostics(span trace.Span, diags *diag.Diagnostics) {
+	if diags != nil && diags.HasError() {
+		messages := make([]string, 0)
+		for _, d := range diags.Errors() {
+			messages = append(messages, fmt.Sprintf("%s: %s", d.Summary(), d.Detail()))
+		}
+		span.SetStatus(codes.Error, strings.Join(messages, "\n"))
+	}
+	span.End()
+}
+
+// Configure sets the global tracer provider which exports the spans with the OTLP exporter, if the exporter is configured by
+// the standard `OTEL_EXPORTER_OTLP_ENDPOINT` or `OTEL_EXPORTER_OTLP_TRACES_ENDPOINT` environment variables. The other `OTEL_EXPORTER_OTLP_*`
+// environment variables, for example, the headers and the protocol, are also honored. It returns a function to flush and stop the exporter.
+func Configure(ctx context.Context) (func(context.Context) error, error) {
+	if !isExporterConfigured() {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch protocol() {
+	case "grpc":
+		exporter, err = otlptracegrpc.New(ctx)
+	case "http/protobuf":
+		exporter, err = otlptracehttp.New(ctx)
+	default:
+		return nil, fmt.Errorf("the OTLP protocol %q is not supported, the supported protocols are `grpc` and `http/protobuf`", protocol())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("creating the OTLP trace exporter: %+v", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(serviceName),
+		semconv.ServiceVersion(version.ProviderVersion),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("building the trace resource: %+v", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return provider.Shutdown, nil
+}
+
+func isExporterConfigured() bool {
+	if strings.EqualFold(os.Getenv("OTEL_SDK_DISABLED"), "true") || strings.EqualFold(os.Getenv("OTEL_TRACES_EXPORTER"), "none") {
+		return false
+	}
+	return os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != ""
+}
+
+// protocol returns the OTLP protocol of the traces, the default is `http/protobuf` as defined in the specification
+func protocol() string {
+	if v := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_PROTOCOL"); v != "" {
+		return v
+	}
+	if v := os.Getenv("OTEL_EXPORTER_OTLP_PROTOCOL"); v != "" {
+		return v
+	}
+	return "http/protobuf"
+}
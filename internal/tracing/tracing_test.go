@@ -0,0 +1,55 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func Test_Spans(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(previous)
+
+	resourceId := "/subscriptions/000/resourceGroups/rg"
+	ctx, span := Start(context.Background(), "azapi_resource.Create", resourceId)
+	_, child := Start(ctx, "locks.Acquire", "")
+	End(child, errors.New("waiting for the lock"))
+	diags := diag.Diagnostics{}
+	diags.AddError("Failed to acquire locks", "waiting for the lock")
+	EndWithDiagnostics(span, &diags)
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, "locks.Acquire", spans[0].Name)
+	assert.Equal(t, codes.Error, spans[0].Status.Code)
+	assert.Empty(t, spans[0].Attributes)
+	assert.Equal(t, spans[1].SpanContext.SpanID(), spans[0].Parent.SpanID())
+	assert.Equal(t, "azapi_resource.Create", spans[1].Name)
+	assert.Equal(t, codes.Error, spans[1].Status.Code)
+	assert.Equal(t, "Failed to acquire locks: waiting for the lock", spans[1].Status.Description)
+	assert.Equal(t, []attribute.KeyValue{attribute.String(AttributeResourceID, resourceId)}, spans[1].Attributes)
+}
+
+func Test_Configure(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+	shutdown, err := Configure(context.Background())
+	assert.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://localhost:4318")
+	t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", "http/json")
+	_, err = Configure(context.Background())
+	assert.Error(t, err)
+}
@@ -6,6 +6,7 @@ import (
 	"log"
 
 	"github.com/Azure/terraform-provider-azapi/internal/provider"
+	"github.com/Azure/terraform-provider-azapi/internal/tracing"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 )
 
@@ -33,7 +34,19 @@ func main() {
 		Address: "registry.terraform.io/Azure/azapi",
 	}
 
-	err := providerserver.Serve(context.Background(), provider.AzureProvider, serveOpts)
+	// the spans are exported if the OTEL_EXPORTER_OTLP_* environment variables are set
+	shutdownTracing, err := tracing.Configure(context.Background())
+	if err != nil {
+		log.Printf("[WARN] Failed to configure tracing: %s", err)
+	}
+
+	err = providerserver.Serve(context.Background(), provider.AzureProvider, serveOpts)
+
+	if shutdownTracing != nil {
+		if err := shutdownTracing(context.Background()); err != nil {
+			log.Printf("[WARN] Failed to flush the spans: %s", err)
+		}
+	}
 
 	if err != nil {
 		log.Fatalf("Error serving provider: %s", err)
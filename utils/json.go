@@ -1,8 +1,12 @@
 package utils
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math/big"
 	"reflect"
 	"regexp"
 	"strings"
@@ -16,13 +20,117 @@ func NormalizeJson(jsonString interface{}) string {
 	}
 	var j interface{}
 
-	if err := json.Unmarshal([]byte(jsonString.(string)), &j); err != nil {
+	if err := UnmarshalJSON([]byte(jsonString.(string)), &j); err != nil {
 		return fmt.Sprintf("Error parsing JSON: %+v", err)
 	}
-	b, _ := json.Marshal(j)
+	b, _ := json.Marshal(normalizeNumbers(j))
 	return string(b)
 }
 
+// UnmarshalJSON is similar to json.Unmarshal, but the numbers are decoded as json.Number to keep their precision.
+func UnmarshalJSON(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return fmt.Errorf("invalid character after top-level value")
+	}
+	return nil
+}
+
+// FormatNumber returns the shortest representation of the number which keeps its precision, integers are never in the exponent format.
+func FormatNumber(f *big.Float) string {
+	if f.IsInt() {
+		return f.Text('f', 0)
+	}
+	return f.Text('g', -1)
+}
+
+// ParseNumber parses the number with the same precision as the terraform number type.
+func ParseNumber(s string) (*big.Float, error) {
+	f, _, err := big.ParseFloat(s, 10, 512, big.ToNearestEven)
+	return f, err
+}
+
+// normalizeNumbers formats the json.Number in the input, so the same numbers in different formats like `1` and `1.0` are equal.
+func normalizeNumbers(input interface{}) interface{} {
+	switch v := input.(type) {
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			res[key] = normalizeNumbers(value)
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, 0, len(v))
+		for _, value := range v {
+			res = append(res, normalizeNumbers(value))
+		}
+		return res
+	case json.Number:
+		if f, err := ParseNumber(v.String()); err == nil {
+			return json.Number(FormatNumber(f))
+		}
+	}
+	return input
+}
+
+// numbersToFloat64 converts the json.Number in the input to float64, it's used by the libraries which don't support json.Number.
+// The original numbers are recorded in the numbers by their float64 values if it's not nil, so they can be restored by floatsToNumbers.
+// The float64 value which is converted from different numbers is recorded as an empty json.Number, because it can't be restored.
+func numbersToFloat64(input interface{}, numbers map[float64]json.Number) interface{} {
+	switch v := input.(type) {
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			res[key] = numbersToFloat64(value, numbers)
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, 0, len(v))
+		for _, value := range v {
+			res = append(res, numbersToFloat64(value, numbers))
+		}
+		return res
+	case json.Number:
+		if f, err := v.Float64(); err == nil {
+			if numbers != nil {
+				if existing, ok := numbers[f]; ok && existing != v {
+					numbers[f] = ""
+				} else {
+					numbers[f] = v
+				}
+			}
+			return f
+		}
+	}
+	return input
+}
+
+// floatsToNumbers converts the float64 in the input back to the original json.Number recorded by numbersToFloat64.
+// The float64 values which are not recorded, for example, the results of the JMESPath functions, are kept.
+func floatsToNumbers(input interface{}, numbers map[float64]json.Number) interface{} {
+	switch v := input.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			v[key] = floatsToNumbers(value, numbers)
+		}
+		return v
+	case []interface{}:
+		for i, value := range v {
+			v[i] = floatsToNumbers(value, numbers)
+		}
+		return v
+	case float64:
+		if number := numbers[v]; number != "" {
+			return number
+		}
+	}
+	return input
+}
+
 // MergeObject is used to merge object old and new, if overlaps, use new value
 func MergeObject(old interface{}, new interface{}) interface{} {
 	if new == nil {
@@ -61,13 +169,238 @@ func MergeObject(old interface{}, new interface{}) interface{} {
 	return new
 }
 
+const (
+	// MergeStrategyDeep merges the objects recursively, the arrays with different lengths are replaced.
+	MergeStrategyDeep = "deep"
+	// MergeStrategyMergePatch merges the objects as a JSON merge patch defined in RFC 7396, the null values delete the properties and the arrays are replaced.
+	MergeStrategyMergePatch = "merge_patch"
+	// MergeStrategyArrayByKey merges the objects recursively, the items of the object arrays are matched by keys and merged.
+	MergeStrategyArrayByKey = "array_by_key"
+)
+
+// DefaultArrayMergeKeys are the keys used to match the array items when the key isn't specified.
+var DefaultArrayMergeKeys = []string{"name", "id"}
+
+// MergeObjectWithStrategy merges the new object into the old object with the specified merge strategy.
+// The arrayItemKeys are only used by the `array_by_key` strategy, it's a map from the path of an array to the path of the property which identifies its items.
+func MergeObjectWithStrategy(old interface{}, new interface{}, strategy string, arrayItemKeys map[string]string) interface{} {
+	switch strategy {
+	case MergeStrategyMergePatch:
+		return MergePatch(old, new)
+	case MergeStrategyArrayByKey:
+		return MergeObjectByKey(old, new, arrayItemKeys)
+	default:
+		return MergeObject(old, new)
+	}
+}
+
+// MergePatch applies the new object to the old object as a JSON merge patch defined in RFC 7396
+func MergePatch(old interface{}, new interface{}) interface{} {
+	newMap, ok := new.(map[string]interface{})
+	if !ok {
+		return new
+	}
+	res := make(map[string]interface{})
+	if oldMap, ok := old.(map[string]interface{}); ok {
+		for key, value := range oldMap {
+			res[key] = value
+		}
+	}
+	for key, value := range newMap {
+		if value == nil {
+			delete(res, key)
+			continue
+		}
+		res[key] = MergePatch(res[key], value)
+	}
+	return res
+}
+
+// MergeObjectByKey is similar to MergeObject, but the object items in the arrays are matched by the keys instead of their indexes.
+// The matched items are merged, the new items which don't match any old item are appended, and the old items are kept.
+// The arrayItemKeys is a map from the path of an array to the path of the property which identifies its items, the items of the other arrays are matched by `name`, then `id`.
+func MergeObjectByKey(old interface{}, new interface{}, arrayItemKeys map[string]string) interface{} {
+	return mergeObjectByKey(old, new, arrayItemKeys, "")
+}
+
+func mergeObjectByKey(old interface{}, new interface{}, arrayItemKeys map[string]string, path string) interface{} {
+	if new == nil {
+		return new
+	}
+	switch oldValue := old.(type) {
+	case map[string]interface{}:
+		if newMap, ok := new.(map[string]interface{}); ok {
+			res := make(map[string]interface{})
+			for key, value := range oldValue {
+				if _, ok := newMap[key]; ok {
+					res[key] = mergeObjectByKey(value, newMap[key], arrayItemKeys, joinPath(path, key))
+				} else {
+					res[key] = value
+				}
+			}
+			for key, newValue := range newMap {
+				if res[key] == nil {
+					res[key] = newValue
+				}
+			}
+			return res
+		}
+	case []interface{}:
+		if newArr, ok := new.([]interface{}); ok {
+			keys := arrayMergeKeys(arrayItemKeys, path)
+			res := make([]interface{}, len(oldValue))
+			copy(res, oldValue)
+			for _, newItem := range newArr {
+				index := indexOfArrayItemByKey(res, newItem, keys)
+				if index == -1 {
+					if !isObject(newItem) {
+						// the arrays of primitive values are replaced
+						return newArr
+					}
+					res = append(res, newItem)
+					continue
+				}
+				res[index] = mergeObjectByKey(res[index], newItem, arrayItemKeys, path)
+			}
+			return res
+		}
+	}
+	return new
+}
+
+// SelectObjectByKey returns the new object whose arrays only contain the items matching the items in the old object by the keys, in the order of the old object.
+// It's used to compare a partial body with the response body when the `array_by_key` merge strategy is used.
+func SelectObjectByKey(old interface{}, new interface{}, arrayItemKeys map[string]string) interface{} {
+	return selectObjectByKey(old, new, arrayItemKeys, "")
+}
+
+func selectObjectByKey(old interface{}, new interface{}, arrayItemKeys map[string]string, path string) interface{} {
+	switch oldValue := old.(type) {
+	case map[string]interface{}:
+		if newMap, ok := new.(map[string]interface{}); ok {
+			res := make(map[string]interface{})
+			for key, value := range newMap {
+				res[key] = value
+			}
+			for key, value := range oldValue {
+				if newValue, ok := newMap[key]; ok {
+					res[key] = selectObjectByKey(value, newValue, arrayItemKeys, joinPath(path, key))
+				}
+			}
+			return res
+		}
+	case []interface{}:
+		if newArr, ok := new.([]interface{}); ok {
+			keys := arrayMergeKeys(arrayItemKeys, path)
+			res := make([]interface{}, 0)
+			for _, oldItem := range oldValue {
+				if !isObject(oldItem) {
+					return new
+				}
+				if index := indexOfArrayItemByKey(newArr, oldItem, keys); index != -1 {
+					res = append(res, selectObjectByKey(oldItem, newArr[index], arrayItemKeys, path))
+				}
+			}
+			return res
+		}
+	}
+	return new
+}
+
+// arrayMergeKeys returns the keys used to match the items of the array at the path, the configured key is used if it's specified.
+func arrayMergeKeys(arrayItemKeys map[string]string, path string) []string {
+	if key := arrayItemKeys[path]; key != "" {
+		return []string{key}
+	}
+	return DefaultArrayMergeKeys
+}
+
+// indexOfArrayItemByKey returns the index of the item in the array which has the same value of the first key that the item has, or -1 if not found.
+// The keys are paths of the properties in the items, for example, `name` or `properties.priority`.
+func indexOfArrayItemByKey(arr []interface{}, item interface{}, keys []string) int {
+	if !isObject(item) {
+		return -1
+	}
+	for _, key := range keys {
+		// the zero values like `0` and `false` are valid keys, only the missing keys are skipped
+		value := valueOfArrayItem(item, key)
+		if value == nil {
+			continue
+		}
+		for index, v := range arr {
+			if isSameKeyValue(valueOfArrayItem(v, key), value) {
+				return index
+			}
+		}
+		return -1
+	}
+	return -1
+}
+
+// valueOfArrayItem returns the value of the property at the key path in the array item, or nil if it's not found.
+func valueOfArrayItem(input interface{}, key string) interface{} {
+	value := input
+	for _, part := range strings.Split(key, ".") {
+		inputMap, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = inputMap[part]
+	}
+	return value
+}
+
+// isSameKeyValue compares the values of the keys, the strings are compared case-insensitively because the names and IDs in Azure are case-insensitive.
+func isSameKeyValue(a, b interface{}) bool {
+	aStr, aOk := a.(string)
+	bStr, bOk := b.(string)
+	if aOk && bOk {
+		return strings.EqualFold(aStr, bStr)
+	}
+	return a != nil && reflect.DeepEqual(normalizeNumbers(a), normalizeNumbers(b))
+}
+
+// isPermutation returns true if the two arrays contain the same items regardless of the order
+func isPermutation(a, b []interface{}) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	used := make([]bool, len(b))
+	for _, itemA := range a {
+		found := false
+		for index, itemB := range b {
+			if !used[index] && reflect.DeepEqual(normalizeNumbers(itemA), normalizeNumbers(itemB)) {
+				used[index] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func isObject(input interface{}) bool {
+	_, ok := input.(map[string]interface{})
+	return ok
+}
+
 type UpdateJsonOption struct {
 	IgnoreCasing          bool
 	IgnoreMissingProperty bool
+	// ArrayItemKeys is a map from the path of an array, for example, `properties.securityRules`, to the path of the property in the array items
+	// which identifies an item, for example, `name` or `properties.priority`. The items of the arrays without a configured key are identified by `name`.
+	ArrayItemKeys map[string]string
 }
 
 // UpdateObject is used to get an updated object which has same schema as old, but with new value
 func UpdateObject(old interface{}, new interface{}, option UpdateJsonOption) interface{} {
+	return updateObject(old, new, option, "")
+}
+
+func updateObject(old interface{}, new interface{}, option UpdateJsonOption, path string) interface{} {
 	if reflect.DeepEqual(old, new) {
 		return old
 	}
@@ -78,7 +411,7 @@ func UpdateObject(old interface{}, new interface{}, option UpdateJsonOption) int
 			for key, value := range oldValue {
 				switch {
 				case newMap[key] != nil:
-					res[key] = UpdateObject(value, newMap[key], option)
+					res[key] = updateObject(value, newMap[key], option, joinPath(path, key))
 				case option.IgnoreMissingProperty || isZeroValue(value):
 					res[key] = value
 				}
@@ -91,18 +424,30 @@ func UpdateObject(old interface{}, new interface{}, option UpdateJsonOption) int
 				return new
 			}
 
-			hasIdentifier := identifierOfArrayItem(oldValue[0]) != ""
+			key := option.arrayItemKey(path)
+			hasIdentifier := false
+			for _, item := range oldValue {
+				if identifierOfArrayItem(item, key) != "" {
+					hasIdentifier = true
+					break
+				}
+			}
 			if !hasIdentifier {
 				if len(oldValue) != len(newArr) {
 					return newArr
 				}
+				// the items without identifiers are compared as a set first, so the arrays reordered by the server, like `addressPrefixes`, don't cause a diff
+				if isPermutation(oldValue, newArr) {
+					return oldValue
+				}
 				res := make([]interface{}, 0)
 				for index := range oldValue {
-					res = append(res, UpdateObject(oldValue[index], newArr[index], option))
+					res = append(res, updateObject(oldValue[index], newArr[index], option, path))
 				}
 				return res
 			}
 
+			// the items which have identifiers are compared as a set, the order of the items in the old array is kept
 			res := make([]interface{}, 0)
 			used := make([]bool, len(newArr))
 
@@ -110,7 +455,7 @@ func UpdateObject(old interface{}, new interface{}, option UpdateJsonOption) int
 				found := false
 				for index, newItem := range newArr {
 					if reflect.DeepEqual(oldItem, newItem) && !used[index] {
-						res = append(res, UpdateObject(oldItem, newItem, option))
+						res = append(res, updateObject(oldItem, newItem, option, path))
 						used[index] = true
 						found = true
 						break
@@ -120,8 +465,8 @@ func UpdateObject(old interface{}, new interface{}, option UpdateJsonOption) int
 					continue
 				}
 				for index, newItem := range newArr {
-					if areSameArrayItems(oldItem, newItem) && !used[index] {
-						res = append(res, UpdateObject(oldItem, newItem, option))
+					if areSameArrayItems(oldItem, newItem, key) && !used[index] {
+						res = append(res, updateObject(oldItem, newItem, option, path))
 						used[index] = true
 						break
 					}
@@ -148,29 +493,37 @@ func UpdateObject(old interface{}, new interface{}, option UpdateJsonOption) int
 	return new
 }
 
-func areSameArrayItems(a, b interface{}) bool {
-	aId := identifierOfArrayItem(a)
-	bId := identifierOfArrayItem(b)
+func (option UpdateJsonOption) arrayItemKey(path string) string {
+	if key := option.ArrayItemKeys[path]; key != "" {
+		return key
+	}
+	return "name"
+}
+
+func areSameArrayItems(a, b interface{}, key string) bool {
+	aId := identifierOfArrayItem(a, key)
+	bId := identifierOfArrayItem(b, key)
 	if aId == "" || bId == "" {
 		return false
 	}
 	return aId == bId
 }
 
-func identifierOfArrayItem(input interface{}) string {
-	inputMap, ok := input.(map[string]interface{})
-	if !ok {
-		return ""
-	}
-	name := inputMap["name"]
-	if name == nil {
-		return ""
-	}
-	nameValue, ok := name.(string)
-	if !ok {
-		return ""
+// identifierOfArrayItem returns the value of the property at the key path in the array item, or an empty string if it's not found.
+func identifierOfArrayItem(input interface{}, key string) string {
+	switch v := valueOfArrayItem(input, key).(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case float64, int, int64, bool:
+		return fmt.Sprintf("%v", v)
 	}
-	return nameValue
+	return ""
+}
+
+func joinPath(path, key string) string {
+	return strings.TrimPrefix(path+"."+key, ".")
 }
 
 // ExtractObject is used to extract object from old for a json path
@@ -206,20 +559,29 @@ func ExtractObject(old interface{}, path string) interface{} {
 // ExtractObjectJMES is used to extract object from old using JMES path
 func ExtractObjectJMES(old interface{}, pathKey, path string) interface{} {
 	result := make(map[string]interface{}, 1)
-	value, err := jmes.Search(path, old)
+	// the JMESPath library only supports float64 numbers, the numbers in the result are restored to keep the precision of the large integers
+	numbers := make(map[float64]json.Number)
+	value, err := jmes.Search(path, numbersToFloat64(old, numbers))
 	if err != nil {
 		return nil
 	}
-	result[pathKey] = value
+	result[pathKey] = floatsToNumbers(value, numbers)
 	return result
 }
 
-// OverrideWithPaths is used to override old object with new object for specific paths
-func OverrideWithPaths(old interface{}, new interface{}, path string, pathSet map[string]bool) (interface{}, error) {
+// OverrideWithPaths is used to override old object with new object for specific paths.
+// The arrayItemKeys is a map from the path of an array to the path of the property which identifies its items, the items are identified by `name` by default.
+func OverrideWithPaths(old interface{}, new interface{}, path string, pathSet map[string]bool, arrayItemKeys map[string]string) (interface{}, error) {
 	if len(pathSet) == 0 || old == nil {
 		return old, nil
 	}
 	if _, ok := pathSet[path]; ok {
+		oldArr, oldOk := old.([]interface{})
+		newArr, newOk := new.([]interface{})
+		if oldOk && newOk {
+			key := UpdateJsonOption{ArrayItemKeys: arrayItemKeys}.arrayItemKey(path)
+			return mergeArray(oldArr, newArr, key), nil
+		}
 		return new, nil
 	}
 	switch oldValue := old.(type) {
@@ -229,7 +591,7 @@ func OverrideWithPaths(old interface{}, new interface{}, path string, pathSet ma
 			for key, value := range oldValue {
 				if newValue, ok := newMap[key]; ok {
 					nestedPath := strings.TrimPrefix(path+"."+key, ".")
-					out, err := OverrideWithPaths(value, newValue, nestedPath, pathSet)
+					out, err := OverrideWithPaths(value, newValue, nestedPath, pathSet, arrayItemKeys)
 					if err != nil {
 						return nil, err
 					}
@@ -247,34 +609,25 @@ func OverrideWithPaths(old interface{}, new interface{}, path string, pathSet ma
 				return nil, fmt.Errorf("ignoring specific item in list is not supported")
 			}
 		}
-		if newArr, ok := new.([]interface{}); ok && pathSet[path] {
-			return mergeArray(oldValue, newArr), nil
-		}
 	default:
 	}
 
 	return old, nil
 }
 
-// mergeArray is used to merge two array, if overlaps, use old value. `name` is used as key to compare
-func mergeArray(old []interface{}, new []interface{}) []interface{} {
+// mergeArray is used to merge two array, if overlaps, use old value. The value of the property at the key path is used to compare
+func mergeArray(old []interface{}, new []interface{}, key string) []interface{} {
 	oldMap := make(map[string]interface{})
 	for _, v := range old {
-		if vMap, ok := v.(map[string]interface{}); ok {
-			if name, ok := vMap["name"]; ok {
-				oldMap[name.(string)] = v
-			}
+		if id := identifierOfArrayItem(v, key); id != "" {
+			oldMap[id] = v
 		}
 	}
 	out := make([]interface{}, 0)
 	for _, v := range new {
-		if vMap, ok := v.(map[string]interface{}); ok {
-			if name, ok := vMap["name"]; ok {
-				if oldV, ok := oldMap[name.(string)]; ok {
-					out = append(out, oldV)
-					continue
-				}
-			}
+		if oldV, ok := oldMap[identifierOfArrayItem(v, key)]; ok {
+			out = append(out, oldV)
+			continue
 		}
 		out = append(out, v)
 	}
@@ -285,7 +638,7 @@ func mergeArray(old []interface{}, new []interface{}) []interface{} {
 func NormalizeObject(input interface{}) interface{} {
 	jsonString, _ := json.Marshal(input)
 	var output interface{}
-	_ = json.Unmarshal(jsonString, &output)
+	_ = UnmarshalJSON(jsonString, &output)
 	return output
 }
 
@@ -325,7 +678,18 @@ func isZeroValue(value interface{}) bool {
 		return len(v) == 0
 	case string:
 		return len(v) == 0
-	case int, int32, int64, float32, float64:
+	case json.Number:
+		f, err := ParseNumber(v.String())
+		return err == nil && f.Sign() == 0
+	case int:
+		return v == 0
+	case int32:
+		return v == 0
+	case int64:
+		return v == 0
+	case float32:
+		return v == 0
+	case float64:
 		return v == 0
 	case bool:
 		return !v
@@ -406,6 +406,154 @@ func Test_UpdateObject(t *testing.T) {
 				IgnoreCasing:          true,
 			},
 		},
+		{
+			OldJson: `
+{
+  "properties": {
+    "customRules": [
+      {
+        "priority": 1,
+        "action": "Allow"
+      },
+      {
+        "priority": 2,
+        "action": "Block"
+      }
+    ]
+  }
+}
+`,
+			NewJson: `
+{
+  "properties": {
+    "customRules": [
+      {
+        "priority": 2,
+        "action": "Log"
+      },
+      {
+        "priority": 1,
+        "action": "Allow"
+      }
+    ]
+  }
+}
+`,
+			ExpectJson: `
+{
+  "properties": {
+    "customRules": [
+      {
+        "priority": 1,
+        "action": "Allow"
+      },
+      {
+        "priority": 2,
+        "action": "Log"
+      }
+    ]
+  }
+}
+`,
+			Option: utils.UpdateJsonOption{
+				ArrayItemKeys: map[string]string{
+					"properties.customRules": "priority",
+				},
+			},
+		},
+		{
+			OldJson: `
+{
+  "properties": {
+    "ruleCollections": [
+      {
+        "properties": {
+          "ruleName": "b",
+          "rules": [
+            {
+              "name": "rule1"
+            }
+          ]
+        }
+      },
+      {
+        "properties": {
+          "ruleName": "a"
+        }
+      }
+    ]
+  }
+}
+`,
+			NewJson: `
+{
+  "properties": {
+    "ruleCollections": [
+      {
+        "properties": {
+          "ruleName": "a"
+        }
+      },
+      {
+        "properties": {
+          "ruleName": "b",
+          "rules": [
+            {
+              "name": "rule1",
+              "id": "rule1-id"
+            }
+          ]
+        }
+      }
+    ]
+  }
+}
+`,
+			ExpectJson: `
+{
+  "properties": {
+    "ruleCollections": [
+      {
+        "properties": {
+          "ruleName": "b",
+          "rules": [
+            {
+              "name": "rule1"
+            }
+          ]
+        }
+      },
+      {
+        "properties": {
+          "ruleName": "a"
+        }
+      }
+    ]
+  }
+}
+`,
+			Option: utils.UpdateJsonOption{
+				IgnoreMissingProperty: true,
+				ArrayItemKeys: map[string]string{
+					"properties.ruleCollections": "properties.ruleName",
+				},
+			},
+		},
+		{
+			OldJson:    `{"properties": {"addressSpace": {"addressPrefixes": ["10.0.0.0/16", "10.1.0.0/16"]}, "dhcpOptions": {"dnsServers": ["10.0.0.4", "10.0.0.5"]}}}`,
+			NewJson:    `{"properties": {"addressSpace": {"addressPrefixes": ["10.1.0.0/16", "10.0.0.0/16"]}, "dhcpOptions": {"dnsServers": ["10.0.0.5", "10.0.0.4"]}}}`,
+			ExpectJson: `{"properties": {"addressSpace": {"addressPrefixes": ["10.0.0.0/16", "10.1.0.0/16"]}, "dhcpOptions": {"dnsServers": ["10.0.0.4", "10.0.0.5"]}}}`,
+		},
+		{
+			OldJson:    `{"properties": {"dhcpOptions": {"dnsServers": ["10.0.0.4", "10.0.0.5"]}}}`,
+			NewJson:    `{"properties": {"dhcpOptions": {"dnsServers": ["10.0.0.5", "10.0.0.6"]}}}`,
+			ExpectJson: `{"properties": {"dhcpOptions": {"dnsServers": ["10.0.0.5", "10.0.0.6"]}}}`,
+		},
+		{
+			OldJson:    `{"properties": {"rules": [{"ports": [80, 443]}, {"ports": [22]}]}}`,
+			NewJson:    `{"properties": {"rules": [{"ports": [22]}, {"ports": [80, 443]}]}}`,
+			ExpectJson: `{"properties": {"rules": [{"ports": [80, 443]}, {"ports": [22]}]}}`,
+		},
 	}
 
 	for _, testcase := range testcases {
@@ -674,6 +822,7 @@ func Test_OverrideWithPaths(t *testing.T) {
 		NewJson       string
 		ExpectJson    string
 		IgnoreChanges []string
+		ArrayItemKeys map[string]string
 	}{
 		{
 			OldJson: `
@@ -743,6 +892,68 @@ func Test_OverrideWithPaths(t *testing.T) {
 }`,
 			IgnoreChanges: []string{"properties.provisioningState"},
 		},
+		{
+			OldJson: `
+{
+    "properties": {
+        "securityRules": [
+            {
+                "name": "allow-http",
+                "properties": {
+                    "priority": 100,
+                    "access": "Allow"
+                }
+            }
+        ]
+    }
+}`,
+			NewJson: `
+{
+    "properties": {
+        "securityRules": [
+            {
+                "name": "allow-http-renamed",
+                "properties": {
+                    "priority": 100,
+                    "access": "Deny"
+                }
+            },
+            {
+                "name": "allow-https",
+                "properties": {
+                    "priority": 200,
+                    "access": "Allow"
+                }
+            }
+        ]
+    }
+}`,
+			ExpectJson: `
+{
+    "properties": {
+        "securityRules": [
+            {
+                "name": "allow-http",
+                "properties": {
+                    "priority": 100,
+                    "access": "Allow"
+                }
+            },
+            {
+                "name": "allow-https",
+                "properties": {
+                    "priority": 200,
+                    "access": "Allow"
+                }
+            }
+        ]
+    }
+}`,
+			IgnoreChanges: []string{"properties.securityRules"},
+			ArrayItemKeys: map[string]string{
+				"properties.securityRules": "properties.priority",
+			},
+		},
 	}
 
 	for _, testcase := range testcases {
@@ -755,7 +966,7 @@ func Test_OverrideWithPaths(t *testing.T) {
 		for _, path := range testcase.IgnoreChanges {
 			pathSet[path] = true
 		}
-		result, err := utils.OverrideWithPaths(old, new, "", pathSet)
+		result, err := utils.OverrideWithPaths(old, new, "", pathSet, testcase.ArrayItemKeys)
 		if err != nil {
 			t.Fatalf("Expected no error but got %s", err)
 		}
@@ -861,14 +1072,30 @@ func Test_ExtractObjectJMES(t *testing.T) {
 }
 `,
 		},
+		{
+			InputJson:  `{"values": [{"name": "test1", "quota": 9007199254740993}, {"name": "test2", "quota": 1}]}`,
+			PathKey:    "quotas",
+			Path:       "values[?quota > `1`].quota",
+			ExpectJson: `{"quotas": [9007199254740993]}`,
+		},
+		{
+			InputJson:  `{"values": [{"quota": 1.0}, {"quota": 1}]}`,
+			PathKey:    "total",
+			Path:       "sum(values[*].quota)",
+			ExpectJson: `{"total": 2}`,
+		},
 	}
 
 	for _, testcase := range testcases {
 		var input, expected interface{}
-		_ = json.Unmarshal([]byte(testcase.InputJson), &input)
-		_ = json.Unmarshal([]byte(testcase.ExpectJson), &expected)
+		_ = utils.UnmarshalJSON([]byte(testcase.InputJson), &input)
+		_ = utils.UnmarshalJSON([]byte(testcase.ExpectJson), &expected)
 
 		result := utils.ExtractObjectJMES(input, testcase.PathKey, testcase.Path)
+		// compare the JSON representations without losing the precision, the numbers in the result are either json.Number or float64
+		resultJson, _ := json.Marshal(result)
+		result = nil
+		_ = utils.UnmarshalJSON(resultJson, &result)
 		if !reflect.DeepEqual(result, expected) {
 			expectedJson, _ := json.Marshal(expected)
 			resultJson, _ := json.Marshal(result)
@@ -1101,3 +1328,130 @@ func Test_RemoveFields(t *testing.T) {
 		}
 	}
 }
+
+func Test_MergeObjectWithStrategy(t *testing.T) {
+	testcases := []struct {
+		Name         string
+		Strategy     string
+		Keys         map[string]string
+		OldJson      string
+		NewJson      string
+		ExpectedJson string
+	}{
+		{
+			Name:         "merge patch deletes null properties",
+			Strategy:     utils.MergeStrategyMergePatch,
+			OldJson:      `{"a": 1, "b": {"b1": "b1", "b2": "b2"}, "c": [1, 2]}`,
+			NewJson:      `{"a": null, "b": {"b1": null, "b3": "b3"}, "c": [3]}`,
+			ExpectedJson: `{"b": {"b2": "b2", "b3": "b3"}, "c": [3]}`,
+		},
+		{
+			Name:         "merge patch replaces arrays of objects",
+			Strategy:     utils.MergeStrategyMergePatch,
+			OldJson:      `{"subnets": [{"name": "s1", "properties": {"a": 1}}, {"name": "s2"}]}`,
+			NewJson:      `{"subnets": [{"name": "s1", "properties": {"b": 2}}]}`,
+			ExpectedJson: `{"subnets": [{"name": "s1", "properties": {"b": 2}}]}`,
+		},
+		{
+			Name:         "deep replaces arrays with different lengths",
+			Strategy:     utils.MergeStrategyDeep,
+			OldJson:      `{"subnets": [{"name": "s1", "properties": {"a": 1}}, {"name": "s2"}]}`,
+			NewJson:      `{"subnets": [{"name": "s1", "properties": {"b": 2}}]}`,
+			ExpectedJson: `{"subnets": [{"name": "s1", "properties": {"b": 2}}]}`,
+		},
+		{
+			Name:         "array by key merges the matched items",
+			Strategy:     utils.MergeStrategyArrayByKey,
+			OldJson:      `{"subnets": [{"name": "s1", "properties": {"a": 1}}, {"name": "s2"}]}`,
+			NewJson:      `{"subnets": [{"name": "S2", "properties": {"b": 2}}, {"name": "s3"}]}`,
+			ExpectedJson: `{"subnets": [{"name": "s1", "properties": {"a": 1}}, {"name": "S2", "properties": {"b": 2}}, {"name": "s3"}]}`,
+		},
+		{
+			Name:         "array by key with configured key",
+			Strategy:     utils.MergeStrategyArrayByKey,
+			Keys:         map[string]string{"rules": "priority"},
+			OldJson:      `{"rules": [{"name": "r1", "priority": 100, "access": "Allow"}, {"name": "r2", "priority": 200}]}`,
+			NewJson:      `{"rules": [{"priority": 100, "access": "Deny"}]}`,
+			ExpectedJson: `{"rules": [{"name": "r1", "priority": 100, "access": "Deny"}, {"name": "r2", "priority": 200}]}`,
+		},
+		{
+			Name:         "array by key with configured key of zero value",
+			Strategy:     utils.MergeStrategyArrayByKey,
+			Keys:         map[string]string{"rules": "priority"},
+			OldJson:      `{"rules": [{"name": "r0", "priority": 0, "access": "Allow"}, {"name": "r1", "priority": 100}]}`,
+			NewJson:      `{"rules": [{"priority": 0, "access": "Deny"}]}`,
+			ExpectedJson: `{"rules": [{"name": "r0", "priority": 0, "access": "Deny"}, {"name": "r1", "priority": 100}]}`,
+		},
+		{
+			Name:         "array by key with configured nested key",
+			Strategy:     utils.MergeStrategyArrayByKey,
+			Keys:         map[string]string{"properties.securityRules": "properties.priority"},
+			OldJson:      `{"properties": {"securityRules": [{"name": "r1", "properties": {"priority": 100, "access": "Allow"}}], "subnets": [{"name": "s1", "properties": {"a": 1}}]}}`,
+			NewJson:      `{"properties": {"securityRules": [{"name": "r1-renamed", "properties": {"priority": 100, "access": "Deny"}}], "subnets": [{"name": "s1", "properties": {"b": 2}}]}}`,
+			ExpectedJson: `{"properties": {"securityRules": [{"name": "r1-renamed", "properties": {"priority": 100, "access": "Deny"}}], "subnets": [{"name": "s1", "properties": {"a": 1, "b": 2}}]}}`,
+		},
+		{
+			Name:         "array by key replaces arrays of primitive values",
+			Strategy:     utils.MergeStrategyArrayByKey,
+			OldJson:      `{"addressPrefixes": ["10.0.0.0/16"]}`,
+			NewJson:      `{"addressPrefixes": ["10.1.0.0/16"]}`,
+			ExpectedJson: `{"addressPrefixes": ["10.1.0.0/16"]}`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.Name, func(t *testing.T) {
+			var old, new, expected interface{}
+			_ = json.Unmarshal([]byte(tc.OldJson), &old)
+			_ = json.Unmarshal([]byte(tc.NewJson), &new)
+			_ = json.Unmarshal([]byte(tc.ExpectedJson), &expected)
+
+			result := utils.MergeObjectWithStrategy(old, new, tc.Strategy, tc.Keys)
+			if !reflect.DeepEqual(result, expected) {
+				resultJson, _ := json.Marshal(result)
+				t.Fatalf("Expected %s but got %s", tc.ExpectedJson, resultJson)
+			}
+		})
+	}
+}
+
+func Test_SelectObjectByKey(t *testing.T) {
+	oldJson := `{"properties": {"subnets": [{"name": "s2", "properties": {"b": 2}}]}}`
+	newJson := `{"name": "vnet", "properties": {"subnets": [{"name": "s1", "properties": {"a": 1}}, {"name": "s2", "properties": {"a": 1, "b": 2}}]}}`
+	expectedJson := `{"name": "vnet", "properties": {"subnets": [{"name": "s2", "properties": {"a": 1, "b": 2}}]}}`
+
+	var old, new, expected interface{}
+	_ = json.Unmarshal([]byte(oldJson), &old)
+	_ = json.Unmarshal([]byte(newJson), &new)
+	_ = json.Unmarshal([]byte(expectedJson), &expected)
+
+	result := utils.SelectObjectByKey(old, new, nil)
+	if !reflect.DeepEqual(result, expected) {
+		resultJson, _ := json.Marshal(result)
+		t.Fatalf("Expected %s but got %s", expectedJson, resultJson)
+	}
+}
+
+func Test_UnmarshalJSONNumberPrecision(t *testing.T) {
+	input := `{"quota":9223372036854775807,"timestamp":1717171717171,"ratio":0.1,"same":1.0}`
+
+	var body interface{}
+	if err := utils.UnmarshalJSON([]byte(input), &body); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	output, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if expected := `{"quota":9223372036854775807,"ratio":0.1,"same":1.0,"timestamp":1717171717171}`; string(output) != expected {
+		t.Fatalf("expected %s but got %s", expected, output)
+	}
+
+	if expected := `{"quota":9223372036854775807,"ratio":0.1,"same":1,"timestamp":1717171717171}`; utils.NormalizeJson(input) != expected {
+		t.Fatalf("expected %s but got %s", expected, utils.NormalizeJson(input))
+	}
+
+	if err := utils.UnmarshalJSON([]byte(`{} {}`), &body); err == nil {
+		t.Fatalf("expected an error for the trailing data")
+	}
+}